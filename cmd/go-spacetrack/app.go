@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// ... app is the state shared by the commands of the program: the configuration merged by viper and the client of
+// space-track built from it. It is created once by the root command and passed to everything which needs it.
+type app struct {
+	v *viper.Viper
+	// ... the name of the config file, passed with the config-file parameter.
+	configFile string
+
+	// ... mu guards the configuration while the daemon is running. Runs hold the read lock, so a reload
+	// waits for the current run to finish and the next one sees the whole new configuration.
+	mu  sync.RWMutex
+	cfg Config
+	// ... reloadMu serializes reloads, which read viper again, so it is never read concurrently.
+	reloadMu sync.Mutex
+
+	// ... client is shared by all the jobs, so they share the authenticated session and the rate limiter.
+	client *spacetrack.Client
+
+	// ... startTime is used instead of the last successful run of the jobs which haven't succeeded yet.
+	startTime time.Time
+	// ... lastRuns keeps the last time each job was executed, by name, so jobs restarted by a reload wait for
+	// their interval instead of being executed again right away.
+	lastRuns sync.Map
+	// ... lastSuccess keeps the last time each job was executed successfully, by name.
+	lastSuccess sync.Map
+}
+
+func newApp() *app {
+	return &app{
+		v:         viper.New(),
+		client:    spacetrack.New(spacetrack.Credentials{}, spacetrack.WithMetrics(clientMetrics)),
+		startTime: time.Now(),
+	}
+}
+
+// ... config returns the current configuration.
+func (a *app) config() Config {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.cfg
+}
+
+// ... connect replaces the client with one using the http configuration, recording its responses or replaying them
+// instead of requesting space-track if configured. It is only called at startup, before any job is executed, because
+// the session belongs to the base url.
+func (a *app) connect(c Config) error {
+	h := c.HTTP
+
+	httpClient, err := spacetrack.NewHTTPClient(h.transport())
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case c.Replay != "":
+		if httpClient.Transport, err = spacetrack.NewReplayer(c.Replay); err != nil {
+			return err
+		}
+		Info("replaying the responses of space-track", zap.String("dir", c.Replay))
+	case c.Record != "":
+		if httpClient.Transport, err = spacetrack.NewRecorder(c.Record, httpClient.Transport); err != nil {
+			return err
+		}
+		Info("recording the responses of space-track", zap.String("dir", c.Record))
+	}
+
+	a.client = spacetrack.New(spacetrack.Credentials{},
+		spacetrack.WithHTTPClient(httpClient),
+		spacetrack.WithBaseURL(h.BaseURL),
+		spacetrack.WithUserAgent(h.UserAgent),
+		spacetrack.WithMetrics(clientMetrics),
+	)
+
+	return nil
+}
+
+// ... setup validates the configuration, connects the client to space-track with it and starts the tracing, returning
+// its shutdown, which flushes the pending spans.
+func (a *app) setup(ctx context.Context, c Config) (func(context.Context) error, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	credentials, err := c.Auth.Credentials()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.connect(c); err != nil {
+		return nil, err
+	}
+	a.configureClient(c, credentials)
+
+	return setupTracing(ctx, c.Tracing)
+}
+
+// ... configureClient applies the configuration to the client: credentials, already decrypted, rate limit and logging.
+// The session is kept unless the credentials have changed.
+func (a *app) configureClient(c Config, credentials spacetrack.Credentials) {
+	a.client.SetCredentials(credentials)
+	// ... recordings are replayed as fast as possible, because they don't reach space-track.
+	if c.Replay != "" {
+		a.client.SetRateLimit(spacetrack.NoRateLimit)
+	} else {
+		a.client.SetRateLimit(c.RateLimit)
+	}
+	a.client.SetLogger(L())
+	a.client.SetBodyDump(c.Logger.BodyDump)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/internal/telemetry"
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidBackfill is returned when the class, range or chunk of a backfill are not valid.
+var ErrInvalidBackfill = errors.New("invalid backfill")
+
+// ... backfillClass is how a class of space-track is backfilled: the field of time splitting it in chunks and the
+// fetch of its records.
+type backfillClass struct {
+	field string
+	fetch fetchFunc
+}
+
+// ... backfillClasses are the classes which can be backfilled.
+var backfillClasses = map[string]backfillClass{
+	spacetrack.ClassGPHistory: {field: "EPOCH", fetch: fetch[spacetrack.SpaceTrackTleUnit]},
+	spacetrack.ClassGP:        {field: "EPOCH", fetch: fetch[spacetrack.SpaceTrackTleUnit]},
+	spacetrack.ClassCdmPublic: {field: "CREATED", fetch: fetch[spacetrack.SpaceTrackCdmUnit]},
+	spacetrack.ClassDecay:     {field: "DECAY_EPOCH", fetch: fetch[spacetrack.SpaceTrackDecayUnit]},
+}
+
+// BackfillClassValues are the names of the classes which can be backfilled.
+var BackfillClassValues = []string{spacetrack.ClassGPHistory, spacetrack.ClassGP, spacetrack.ClassCdmPublic, spacetrack.ClassDecay}
+
+// Backfill is a fetch of a past range of time of a class, [From, To), split in chunks which are requested one by one,
+// so no query is big enough to time out or to trip the rate limits of space-track.
+type Backfill struct {
+	// Class is the class of space-track to fetch, see BackfillClassValues.
+	Class string `json:"class"`
+	// Query are the predicates filtering every chunk, without orderby nor limit, e.g. NORAD_CAT_ID/25544
+	Query string `json:"query"`
+	// From is the start of the range, included.
+	From time.Time `json:"from"`
+	// To is the end of the range, excluded.
+	To time.Time `json:"to"`
+	// Chunk is the range of time of each request.
+	Chunk time.Duration `json:"chunk"`
+}
+
+// ... backfillCheckpoint is the progress of a backfill, kept in ${work_dir}/.spacetrack-backfill-${class}.json
+type backfillCheckpoint struct {
+	Backfill
+	// Done is the end of the last chunk persisted, where the backfill is resumed.
+	Done time.Time `json:"done"`
+}
+
+// ... validate checks the class, the range and the chunk of the backfill.
+func (b Backfill) validate() error {
+	if _, ok := backfillClasses[b.Class]; !ok {
+		return fmt.Errorf("%w: unknown class %q, allowed values: %s", ErrInvalidBackfill, b.Class, strings.Join(BackfillClassValues, ", "))
+	}
+
+	if !b.From.Before(b.To) {
+		return fmt.Errorf("%w: from, %s, must be before to, %s", ErrInvalidBackfill, b.From.Format(time.RFC3339), b.To.Format(time.RFC3339))
+	}
+
+	if b.Chunk <= 0 {
+		return fmt.Errorf("%w: chunk must be positive", ErrInvalidBackfill)
+	}
+
+	// ... chunks are sorted by the field of time of the class, and a limit would drop the records of the chunks past it.
+	segments := strings.Split(strings.Trim(b.Query, "/"), "/")
+	for i := 0; i < len(segments); i += 2 {
+		if modifier := strings.ToLower(segments[i]); modifier == "orderby" || modifier == "limit" {
+			return fmt.Errorf("%w: query can't have %s, only predicates filtering the records", ErrInvalidBackfill, modifier)
+		}
+	}
+
+	return nil
+}
+
+// ... same returns true if both backfills fetch the same records in the same chunks, so one can resume the other.
+func (b Backfill) same(o Backfill) bool {
+	return b.Class == o.Class && b.Query == o.Query && b.From.Equal(o.From) && b.To.Equal(o.To) && b.Chunk == o.Chunk
+}
+
+// ... query returns the query of the chunk [from, to) of the backfill, sorted by the field of time of its class. The
+// range goes first, so it's never preceded by modifiers of the query.
+func (b Backfill) query(from, to time.Time) spacetrack.Query {
+	field := backfillClasses[b.Class].field
+
+	return spacetrack.NewQuery(b.Class).
+		Between(field, from, to.Add(-time.Microsecond)).
+		Predicates(b.Query).
+		OrderBy(field + " asc")
+}
+
+// ... backfill fetches the chunks of the range which haven't been persisted yet by a previous execution, unless
+// restart is set, persisting each one with the persister of the job under ${work_dir}/spacetrack-${class}/${unix time
+// of the start of the chunk}, so chunks fetched again are overwritten. The progress is checkpointed after each chunk.
+func (a *app) backfill(ctx context.Context, job Job, b Backfill, restart bool) error {
+	if err := b.validate(); err != nil {
+		return err
+	}
+
+	persister, err := persist.GetPersister(job.Persister, job.Format, persist.WithLogger(L()), persist.WithMetrics(persistMetrics))
+	if err != nil {
+		return err
+	}
+
+	start := b.From
+
+	if !restart {
+		cp, err := readCheckpoint(job.WorkDir, b.Class)
+		if err != nil {
+			return err
+		}
+
+		if cp.same(b) && cp.Done.After(start) {
+			start = cp.Done
+			Info("resuming backfill", zap.String("class", b.Class), zap.Time("from", start))
+		}
+	}
+
+	for from := start; from.Before(b.To); {
+		to := from.Add(b.Chunk)
+		if to.After(b.To) {
+			to = b.To
+		}
+
+		if err := a.backfillChunk(ctx, job, persister, b, from, to); err != nil {
+			return fmt.Errorf("backfilling %s from %s: %w", b.Class, from.Format(time.RFC3339), err)
+		}
+
+		if err := writeCheckpoint(job.WorkDir, backfillCheckpoint{Backfill: b, Done: to}); err != nil {
+			return err
+		}
+
+		from = to
+	}
+
+	Info("backfill completed", zap.String("class", b.Class), zap.Time("from", b.From), zap.Time("to", b.To))
+
+	return nil
+}
+
+// ... backfillChunk fetches and persists the records of the chunk [from, to) of the backfill.
+func (a *app) backfillChunk(ctx context.Context, job Job, persister persist.Persister, b Backfill, from, to time.Time) (err error) {
+	ctx, cl := context.WithTimeout(ctx, job.maxDuration())
+	defer cl()
+
+	ctx, span := telemetry.Start(ctx, "spacetrack.backfill", attribute.String("spacetrack.class", b.Class), attribute.String("spacetrack.from", from.Format(time.RFC3339)), attribute.String("spacetrack.to", to.Format(time.RFC3339)))
+	defer telemetry.End(span, &err)
+
+	f, err := backfillClasses[b.Class].fetch(ctx, a.client, b.query(from, to), fetchOptions{oneE: job.Persister == persist.OneFile})
+	if err != nil {
+		return err
+	}
+
+	if err := persister.Persist(ctx, filepath.Join(job.WorkDir, "spacetrack-"+b.Class, strconv.FormatInt(from.Unix(), 10)), f.records); err != nil {
+		return err
+	}
+
+	Info("backfilled chunk", zap.String("class", b.Class), zap.Time("from", from), zap.Time("to", to))
+
+	return nil
+}
+
+// ... checkpointPath returns the path of the checkpoint of the backfills of the class.
+func checkpointPath(workDir, class string) string {
+	return filepath.Join(workDir, ".spacetrack-backfill-"+class+".json")
+}
+
+// ... readCheckpoint reads the checkpoint of the backfills of the class, being empty if there is none.
+func readCheckpoint(workDir, class string) (backfillCheckpoint, error) {
+	var cp backfillCheckpoint
+
+	content, err := os.ReadFile(checkpointPath(workDir, class))
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, fmt.Errorf("%w: %v", ErrState, err)
+	}
+
+	if err := json.Unmarshal(content, &cp); err != nil {
+		return cp, fmt.Errorf("%w: %s is corrupt, fix or remove it, or run with --restart: %v", ErrState, checkpointPath(workDir, class), err)
+	}
+
+	return cp, nil
+}
+
+// ... writeCheckpoint replaces the checkpoint of the backfills of its class.
+func writeCheckpoint(workDir string, cp backfillCheckpoint) error {
+	content, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrState, err)
+	}
+
+	if err := writeFileAtomic(checkpointPath(workDir, cp.Class), content); err != nil {
+		return fmt.Errorf("%w: %v", ErrState, err)
+	}
+
+	return nil
+}
+
+// ... parseRangeTime parses the start or the end of a range of time of backfill, propagate or passes: a date, e.g.
+// 2022-01-01, or a time, e.g. 2022-01-01T12:00:00Z, in UTC. Dates used as the end of a range include the whole day, so
+// they are parsed as the midnight after it. Errors wrap invalid, the error of the command.
+func parseRangeTime(input string, end bool, invalid error) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", input); err == nil {
+		if end {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, input)
+	if err != nil {
+		return t, fmt.Errorf("%w: %q is not a date, e.g. 2022-01-01, nor a time, e.g. 2022-01-01T12:00:00Z", invalid, input)
+	}
+
+	return t.UTC(), nil
+}
+
+// ... parseChunk parses a duration, e.g. 12h, allowing days and weeks too, e.g. 1d or 2w.
+func parseChunk(input string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if !strings.HasSuffix(input, suffix) {
+			continue
+		}
+
+		if n, err := strconv.Atoi(strings.TrimSuffix(input, suffix)); err == nil {
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(input)
+	if err != nil {
+		return d, fmt.Errorf("%w: chunk %q is not a duration, e.g. 12h, 1d or 1w", ErrInvalidBackfill, input)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/MrTimeout/go-spacetrack/spacetrack/spacetracktest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ... newFakeServerApp returns an app connected to the fake server, without rate limit.
+func newFakeServerApp(t *testing.T, s *spacetracktest.Server) *app {
+	a := newApp()
+	cfg := Config{HTTP: HTTP{BaseURL: s.URL}, RateLimit: int(time.Minute / time.Microsecond)}
+
+	if err := a.connect(cfg); err != nil {
+		t.Fatal(err)
+	}
+	a.configureClient(cfg, spacetrack.Credentials{Identity: spacetracktest.Identity, Password: spacetracktest.Password})
+
+	return a
+}
+
+func TestBackfill(t *testing.T) {
+	var (
+		from = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		b    = Backfill{Class: spacetrack.ClassGPHistory, From: from, To: from.AddDate(0, 0, 3), Chunk: 24 * time.Hour}
+	)
+
+	newJob := func(t *testing.T) Job {
+		return Job{Name: backfillJobName, Format: persist.Json, Persister: persist.OneFile, WorkDir: t.TempDir()}
+	}
+
+	t.Run("every chunk is persisted in its own folder", func(t *testing.T) {
+		s := spacetracktest.NewServer()
+		defer s.Close()
+
+		job := newJob(t)
+
+		assert.Nil(t, newFakeServerApp(t, s).backfill(context.Background(), job, b, false))
+		assert.Equal(t, 3, s.Queries())
+
+		for i := 0; i < 3; i++ {
+			chunk := filepath.Join(job.WorkDir, "spacetrack-gp_history", strconv.FormatInt(from.AddDate(0, 0, i).Unix(), 10))
+			assert.DirExists(t, chunk)
+		}
+
+		cp, err := readCheckpoint(job.WorkDir, b.Class)
+
+		assert.Nil(t, err)
+		assert.True(t, cp.same(b))
+		assert.Equal(t, b.To, cp.Done)
+	})
+
+	t.Run("failed chunk isn't checkpointed", func(t *testing.T) {
+		s := spacetracktest.NewServer()
+		defer s.Close()
+
+		var (
+			a   = newFakeServerApp(t, s)
+			job = newJob(t)
+		)
+
+		if err := a.client.Login(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		s.FailNext(1, http.StatusInternalServerError)
+
+		assert.NotNil(t, a.backfill(context.Background(), job, b, false))
+		assert.NoFileExists(t, checkpointPath(job.WorkDir, b.Class))
+	})
+
+	t.Run("interrupted backfill is resumed from its checkpoint", func(t *testing.T) {
+		s := spacetracktest.NewServer()
+		defer s.Close()
+
+		job := newJob(t)
+		if err := writeCheckpoint(job.WorkDir, backfillCheckpoint{Backfill: b, Done: from.AddDate(0, 0, 1)}); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, newFakeServerApp(t, s).backfill(context.Background(), job, b, false))
+		assert.Equal(t, 2, s.Queries(), "only the chunks after the checkpoint are fetched")
+		assert.NoDirExists(t, filepath.Join(job.WorkDir, "spacetrack-gp_history", strconv.FormatInt(from.Unix(), 10)))
+	})
+
+	t.Run("completed backfill isn't fetched again unless restarted", func(t *testing.T) {
+		s := spacetracktest.NewServer()
+		defer s.Close()
+
+		var (
+			a   = newFakeServerApp(t, s)
+			job = newJob(t)
+		)
+
+		assert.Nil(t, a.backfill(context.Background(), job, b, false))
+		assert.Nil(t, a.backfill(context.Background(), job, b, false))
+		assert.Equal(t, 3, s.Queries())
+
+		assert.Nil(t, a.backfill(context.Background(), job, b, true))
+		assert.Equal(t, 6, s.Queries())
+	})
+
+	t.Run("checkpoint of another backfill is ignored", func(t *testing.T) {
+		s := spacetracktest.NewServer()
+		defer s.Close()
+
+		var (
+			a   = newFakeServerApp(t, s)
+			job = newJob(t)
+		)
+
+		assert.Nil(t, a.backfill(context.Background(), job, b, false))
+		assert.Nil(t, a.backfill(context.Background(), job, Backfill{Class: b.Class, Query: "NORAD_CAT_ID/25544", From: b.From, To: b.To, Chunk: b.Chunk}, false))
+		assert.Equal(t, 6, s.Queries())
+	})
+
+	t.Run("corrupt checkpoint is an error", func(t *testing.T) {
+		job := newJob(t)
+		if err := os.WriteFile(checkpointPath(job.WorkDir, b.Class), []byte("{"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		a, _ := newTestApp(http.StatusOK, `[]`)
+
+		assert.ErrorIs(t, a.backfill(context.Background(), job, b, false), ErrState)
+	})
+}
+
+func TestBackfillQuery(t *testing.T) {
+	b := Backfill{Class: spacetrack.ClassCdmPublic, Query: "EMERGENCY_REPORTABLE/Y", Chunk: time.Hour}
+
+	assert.Equal(t,
+		"/class/cdm_public/CREATED/2022-01-01 00:00:00.000000--2022-01-01 00:59:59.999999/EMERGENCY_REPORTABLE/Y/orderby/CREATED asc/format/json/emptyresult/show",
+		b.query(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 1, 1, 1, 0, 0, 0, time.UTC)).Path(),
+	)
+}
+
+func TestBackfillValidate(t *testing.T) {
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, each := range []struct {
+		description string
+		backfill    Backfill
+		wantErr     bool
+	}{
+		{description: "valid backfill", backfill: Backfill{Class: spacetrack.ClassDecay, From: from, To: from.AddDate(0, 1, 0), Chunk: time.Hour}},
+		{description: "unknown class", backfill: Backfill{Class: "satcat", From: from, To: from.AddDate(0, 1, 0), Chunk: time.Hour}, wantErr: true},
+		{description: "empty range", backfill: Backfill{Class: spacetrack.ClassGP, From: from, To: from, Chunk: time.Hour}, wantErr: true},
+		{description: "non positive chunk", backfill: Backfill{Class: spacetrack.ClassGP, From: from, To: from.AddDate(0, 1, 0)}, wantErr: true},
+		{description: "query with orderby", backfill: Backfill{Class: spacetrack.ClassGPHistory, Query: "NORAD_CAT_ID/25544/orderby/EPOCH desc", From: from, To: from.AddDate(0, 1, 0), Chunk: time.Hour}, wantErr: true},
+		{description: "query with limit", backfill: Backfill{Class: spacetrack.ClassGPHistory, Query: "NORAD_CAT_ID/25544/LIMIT/10", From: from, To: from.AddDate(0, 1, 0), Chunk: time.Hour}, wantErr: true},
+		{description: "query with predicates only", backfill: Backfill{Class: spacetrack.ClassGPHistory, Query: "NORAD_CAT_ID/25544/OBJECT_TYPE/PAYLOAD", From: from, To: from.AddDate(0, 1, 0), Chunk: time.Hour}},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			if err := each.backfill.validate(); each.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidBackfill)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestParseRangeTime(t *testing.T) {
+	for _, each := range []struct {
+		description, input string
+		end                bool
+		want               time.Time
+		wantErr            bool
+	}{
+		{description: "date as start", input: "2022-01-01", want: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{description: "date as end includes the whole day", input: "2022-06-30", end: true, want: time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{description: "time is converted to utc", input: "2022-01-01T12:00:00+02:00", end: true, want: time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{description: "neither date nor time", input: "yesterday", wantErr: true},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got, err := parseRangeTime(each.input, each.end, ErrInvalidEphemeris)
+
+			if each.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidEphemeris)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, each.want, got)
+		})
+	}
+}
+
+func TestParseChunk(t *testing.T) {
+	for _, each := range []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "12h", want: 12 * time.Hour},
+		{input: "1d", want: 24 * time.Hour},
+		{input: "2w", want: 14 * 24 * time.Hour},
+		{input: "1.5d", wantErr: true},
+		{input: "daily", wantErr: true},
+	} {
+		t.Run(each.input, func(t *testing.T) {
+			got, err := parseChunk(each.input)
+
+			if each.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidBackfill)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, each.want, got)
+		})
+	}
+}
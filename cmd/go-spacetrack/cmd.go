@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// ... extension of the config file that we are going to append to the file if we are using
+	// ${PWD} or ${HOME} variables instead of config-file parameter.
+	configFileType = "yml"
+	// ... name of the config file that we are going to prepend to the file if we are using
+	// ${PWD} or ${HOME} variable instead of config-file parameter.
+	configFileName = "spacetrack"
+	// ... prefix of the environment variables which override the config file, e.g. SPACETRACK_AUTH_IDENTITY
+	envPrefix = "spacetrack"
+)
+
+// ... flagKeys maps each persistent flag to its key inside the configuration, so flags take precedence over
+// env vars, config file and defaults.
+var flagKeys = map[string]string{
+	"work-dir":    "work_dir",
+	"interval":    "interval",
+	"one-file":    "one_file",
+	"rest-call":   "rest_call",
+	"format":      "format",
+	"daemon":      "daemon",
+	"username":    "auth.identity",
+	"password":    "auth.password",
+	"log-level":   "logger.console_appender.level",
+	"log-format":  "logger.console_appender.encoding",
+	"record":      "record",
+	"replay":      "replay",
+	"incremental": "incremental",
+	"dedup":       "dedup",
+}
+
+// ... configDefaults are the lowest precedence values. Every key we want to be overridden by an env var must be here,
+// because viper only looks for env vars of the keys it already knows.
+var configDefaults = map[string]any{
+	"auth.identity":                       "",
+	"auth.password":                       "",
+	"work_dir":                            "",
+	"interval":                            "1h",
+	"one_file":                            true,
+	"secret_file":                         "",
+	"rest_call":                           spacetrack.All.String(),
+	"format":                              persist.Json.String(),
+	"daemon":                              false,
+	"rate_limit":                          spacetrack.DefaultRateLimit,
+	"record":                              "",
+	"replay":                              "",
+	"incremental":                         false,
+	"dedup":                               false,
+	"http.base_url":                       spacetrack.DefaultBaseURL,
+	"http.user_agent":                     spacetrack.DefaultUserAgent,
+	"http.timeout":                        "1m",
+	"http.job_timeout":                    defaultJobTimeout.String(),
+	"http.dial_timeout":                   "30s",
+	"http.tls_handshake_timeout":          "10s",
+	"http.response_header_timeout":        "",
+	"http.idle_conn_timeout":              "90s",
+	"http.proxy":                          "",
+	"http.ca_file":                        "",
+	"http.cert_file":                      "",
+	"http.key_file":                       "",
+	"http.tls_min_version":                TLS12,
+	"http.max_idle_conns":                 100,
+	"http.max_idle_conns_per_host":        2,
+	"http.max_conns_per_host":             0,
+	"server.address":                      "",
+	"server.ready_intervals":              defaultReadyIntervals,
+	"tracing.exporter":                    NoneExporter,
+	"tracing.endpoint":                    "",
+	"tracing.insecure":                    false,
+	"tracing.sample_ratio":                1.0,
+	"tracing.service_name":                defaultServiceName,
+	"logger.prod":                         false,
+	"logger.console_appender.level":       InfoLevel,
+	"logger.console_appender.date_format": RFC3339,
+	"logger.console_appender.target":      Stdout,
+	"logger.console_appender.encoding":    ConsoleEncoding,
+	"logger.body_dump.enabled":            false,
+	"logger.body_dump.limit":              spacetrack.DefaultBodyDumpLimit,
+}
+
+// ... decodeHook keeps the viper default hooks, adding the one to decode the types which implement encoding.TextUnmarshaler,
+// like PersisterMod.
+var decodeHook = mapstructure.ComposeDecodeHookFunc(
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+	mapstructure.TextUnmarshallerHookFunc(),
+)
+
+// ... skipLoggerAnnotation is set on the commands which only inspect the configuration, like config validate or
+// healthcheck, leaving the default logger instead of the configured one.
+const skipLoggerAnnotation = "skip-logger"
+
+// ... can't check the file because it doesn't exists, or we don't have permissions.
+var errCheckConfigFile = errors.New("checking config file")
+
+// ... newRootCmd allows us to create the main command to configure the application
+func newRootCmd() *cobra.Command {
+	return newApp().rootCmd()
+}
+
+// ... rootCmd creates the main command, whose subcommands share the app.
+func (a *app) rootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "go-spacetrack",
+		Short: "script to fetch data from spacetrack",
+		Long:  "script to fetch data from spacetrack",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.readConfig(); err != nil {
+				return err
+			}
+
+			if skipsLogger(cmd) {
+				return nil
+			}
+
+			return Configure(a.config().Logger)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := a.config()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			shutdown, err := a.setup(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			// ... the pending spans are flushed even if the context has been canceled.
+			defer shutdown(context.Background()) //nolint:errcheck
+
+			if cfg.Server.Address != "" {
+				if _, err := a.startServer(ctx, cfg.Server.Address); err != nil {
+					return err
+				}
+			}
+
+			if cfg.Daemon {
+				return a.daemon(ctx)
+			}
+
+			return a.run(ctx)
+		},
+	}
+
+	var (
+		restCall                 = spacetrack.All
+		format                   = persist.Json
+		logLevel  LoggerLevel    = InfoLevel
+		logFormat LoggerEncoding = ConsoleEncoding
+	)
+
+	// ... values of these flags are not read directly, but through viper, see flagKeys.
+	root.PersistentFlags().StringP("work-dir", "w", "", "dir where all the spacetrack data will go into: ${work_dir}/spacetrack-tle and/or ${work-dir}/spacetrack-dec and/or ${work-dir}/spacetrack-cdm")
+	root.PersistentFlags().StringP("interval", "i", "1h", "interval of time in which the script is going to fetch data from space-track")
+	root.PersistentFlags().Bool("one-file", true, "if set to true, the program will persist each item in one file under its parent-folder, aka ${work-dir}/spacetrack-${rest-call}/${unix-time-seconds}")
+	root.PersistentFlags().Var(&restCall, "rest-call", "rest call to select: tle, cdm, dec (decay) or all (which means the three mentioned before)")
+	root.PersistentFlags().Var(&format, "format", "format of the output")
+	root.PersistentFlags().Bool("daemon", false, "if set to true, the program keeps running, fetching data each interval and reloading the config file when it changes or on SIGHUP")
+
+	root.PersistentFlags().Bool("incremental", false, "if set to true, only the records newer than the high-water marks of the previous runs are fetched, instead of every record of the queries")
+
+	root.PersistentFlags().Bool("dedup", false, "if set to true, only the records added or updated since the previous fetch are persisted, with a changeset of the objects added, updated and removed")
+
+	root.PersistentFlags().String("record", "", "dir where each raw response of space-track is saved, with the metadata of its request, to replay the run later")
+	root.PersistentFlags().String("replay", "", "dir of the recordings which answer the requests instead of space-track, running the whole pipeline without network")
+
+	root.PersistentFlags().StringP("username", "u", "", "username, aka identity in spacetrack, that we are going to use to authenticate")
+	root.PersistentFlags().StringP("password", "p", "", "password that we are going to use to authenticate")
+
+	root.PersistentFlags().Var(&logLevel, "log-level", "level of the console logs: debug, info, warn, error, dpanic, panic or fatal")
+	root.PersistentFlags().Var(&logFormat, "log-format", "encoding of the console logs: console or json")
+
+	root.PersistentFlags().StringVarP(&a.configFile, "config-file", "f", "", "config file to parse information like identity, password, interval, etc. Precedence is flags > env vars (SPACETRACK_ prefix, e.g. SPACETRACK_AUTH_IDENTITY) > config file > defaults")
+
+	if err := bindConfig(a.v, root.PersistentFlags()); err != nil {
+		panic(err)
+	}
+
+	root.AddCommand(a.configCmd(), a.healthcheckCmd(), a.backfillCmd(), a.diffCmd(), a.convertCmd(), a.propagateCmd(), a.passesCmd())
+
+	root.MarkPersistentFlagDirname("work-dir")     //nolint:errcheck
+	root.MarkPersistentFlagDirname("record")       //nolint:errcheck
+	root.MarkPersistentFlagDirname("replay")       //nolint:errcheck
+	root.MarkPersistentFlagFilename("config-file") //nolint:errcheck
+
+	//nolint:errcheck
+	root.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return persist.FormatValues, cobra.ShellCompDirectiveDefault
+	})
+
+	//nolint:errcheck
+	root.RegisterFlagCompletionFunc("log-level", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return loggerLevelValues, cobra.ShellCompDirectiveDefault
+	})
+
+	//nolint:errcheck
+	root.RegisterFlagCompletionFunc("log-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return LoggerEncodingValues, cobra.ShellCompDirectiveDefault
+	})
+
+	//nolint:errcheck
+	root.RegisterFlagCompletionFunc("rest-call", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return spacetrack.RestCallValues, cobra.ShellCompDirectiveDefault
+	})
+
+	return root
+}
+
+// ... bindConfig sets the precedence of the configuration sources: flags > env vars > config file > defaults.
+func bindConfig(v *viper.Viper, flags *pflag.FlagSet) error {
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	for key, value := range configDefaults {
+		v.SetDefault(key, value)
+	}
+
+	for flag, key := range flagKeys {
+		if err := v.BindPFlag(key, flags.Lookup(flag)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ... we try to read the config file, if any, and update the config of the app with all the sources merged.
+// The config file is optional unless the user has passed it explicitly with the config-file parameter.
+func (a *app) readConfig() error {
+	if a.checkConfigFile() {
+		if err := a.v.ReadInConfig(); err != nil {
+			return err
+		}
+	} else if a.configFile != "" {
+		return errCheckConfigFile
+	}
+
+	c, err := decodeConfig(a.v)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.cfg = c
+	a.mu.Unlock()
+
+	return nil
+}
+
+// ... skipsLogger tells whether the command, or some of its parents, only inspects the configuration, so the logger
+// must not open its files nor dial syslog, see skipLoggerAnnotation.
+func skipsLogger(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[skipLoggerAnnotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ... decodeConfig returns the configuration merged from all the sources known by viper.
+func decodeConfig(v *viper.Viper) (Config, error) {
+	var c Config
+
+	if err := v.Unmarshal(&c, viper.DecodeHook(decodeHook)); err != nil {
+		return c, err
+	}
+
+	markPersisters(v, &c)
+
+	// ... if the secret file doesn't exist, we leave it to Validate, so it is reported along with the rest of problems.
+	if c.SecretFile != "" && fileExists(c.SecretFile) {
+		secret, err := ReadOnlyFilePassphrase(c.SecretFile)
+		if err != nil {
+			return c, err
+		}
+		c.Auth.Secret = secret
+	}
+
+	return c, nil
+}
+
+// ... we try to read first the config-file parameter if it is not empty. If we can't find the file or it doesn't exist, we use some default paths
+// like ${PWD} or ${HOME}, taking preference actual directory, aka ${PWD}
+func (a *app) checkConfigFile() bool {
+	var (
+		v      = a.v
+		exists = false
+	)
+
+	if a.configFile != "" {
+		if fileExists(a.configFile) {
+			v.SetConfigFile(a.configFile)
+			return true
+		}
+		return false
+	}
+
+	if pwd, err := os.Getwd(); err == nil && fileExists(buildCfg(pwd)) {
+		exists = true
+		v.AddConfigPath(pwd)
+	}
+
+	if home, err := os.UserHomeDir(); err == nil && fileExists(buildCfg(home)) {
+		exists = true
+		v.AddConfigPath(home)
+	}
+
+	if exists {
+		v.SetConfigType(configFileType)
+		v.SetConfigName(configFileName)
+	}
+
+	return exists
+}
+
+func fileExists(file string) bool {
+	_, err := os.OpenFile(file, os.O_RDONLY, 0444)
+	return err == nil
+}
+
+// ... name of the configuration by default that we have to use
+func buildCfg(dir string) string {
+	return filepath.Join(dir, configFileName+"."+configFileType)
+}
+
+// ... main entrypoint of the cmd file, so we can configure the app and observe if there is something wrong
+func execute() error {
+	root := newRootCmd()
+
+	err := root.Execute()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/spf13/cobra"
+)
+
+// ... name of the job of the backfills in the logs and traces.
+const backfillJobName = "backfill"
+
+// ... backfillCmd fetches a past range of time of a class in chunks, persisting them like the jobs, with the top level
+// format, persister and work dir.
+func (a *app) backfillCmd() *cobra.Command {
+	var (
+		b               Backfill
+		from, to, chunk string
+		restart         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "fetch a past range of time of a class in chunks",
+		Long: "fetch a past range of time of a class, e.g. gp_history, splitting it in chunks which are requested one by one, respecting the rate limit. " +
+			"The progress is checkpointed in ${work_dir}/.spacetrack-backfill-${class}.json after each chunk, so an interrupted backfill is resumed running it again with the same parameters",
+		Example: "go-spacetrack backfill --class gp_history --from 2022-01-01 --to 2022-06-30 --chunk 1d",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if b.From, err = parseRangeTime(from, false, ErrInvalidBackfill); err != nil {
+				return err
+			}
+
+			if b.To, err = parseRangeTime(to, true, ErrInvalidBackfill); err != nil {
+				return err
+			}
+
+			if b.Chunk, err = parseChunk(chunk); err != nil {
+				return err
+			}
+
+			cfg := a.config()
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			shutdown, err := a.setup(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			// ... the pending spans are flushed even if the context has been canceled.
+			defer shutdown(context.Background()) //nolint:errcheck
+
+			job := cfg.defaultJob()
+			job.Name = backfillJobName
+
+			return a.backfill(ctx, job, b, restart)
+		},
+	}
+
+	cmd.Flags().StringVar(&b.Class, "class", spacetrack.ClassGPHistory, "class to fetch: gp_history, gp, cdm_public or decay")
+	cmd.Flags().StringVar(&b.Query, "query", "", "predicates filtering every chunk, without orderby nor limit, e.g. NORAD_CAT_ID/25544")
+	cmd.Flags().StringVar(&from, "from", "", "start of the range, included, as a date, e.g. 2022-01-01, or a time in UTC, e.g. 2022-01-01T12:00:00Z")
+	cmd.Flags().StringVar(&to, "to", "", "end of the range, as a date whose whole day is included, e.g. 2022-06-30, or a time excluded, e.g. 2022-07-01T00:00:00Z")
+	cmd.Flags().StringVar(&chunk, "chunk", "1d", "range of time of each request, e.g. 12h, 1d or 1w")
+	cmd.Flags().BoolVar(&restart, "restart", false, "if set to true, the checkpoint is ignored and the whole range is fetched again")
+
+	cmd.MarkFlagRequired("from") //nolint:errcheck
+	cmd.MarkFlagRequired("to")   //nolint:errcheck
+
+	//nolint:errcheck
+	cmd.RegisterFlagCompletionFunc("class", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return BackfillClassValues, cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
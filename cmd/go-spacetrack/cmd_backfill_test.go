@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack/spacetracktest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfillCmd(t *testing.T) {
+	s := spacetracktest.NewServer()
+	defer s.Close()
+
+	var (
+		dir     = t.TempDir()
+		content = fmt.Sprintf("auth:\n  identity: %s\n  password: %s\nwork_dir: %s\nrate_limit: 60000000\nhttp:\n  base_url: %s\n", spacetracktest.Identity, spacetracktest.Password, dir, s.URL)
+	)
+
+	f := createFile(t, t.TempDir(), "spacetrack.yml", 0666)
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, each := range []struct {
+		description string
+		args        []string
+		wantErr     error
+		queries     int
+	}{
+		{
+			description: "unknown class",
+			args:        []string{"--class", "satcat", "--from", "2022-01-01", "--to", "2022-01-02"},
+			wantErr:     ErrInvalidBackfill,
+		},
+		{
+			description: "invalid chunk",
+			args:        []string{"--from", "2022-01-01", "--to", "2022-01-02", "--chunk", "daily"},
+			wantErr:     ErrInvalidBackfill,
+		},
+		{
+			description: "range of two days in chunks of 12 hours",
+			args:        []string{"--class", "decay", "--from", "2022-01-01", "--to", "2022-01-02", "--chunk", "12h"},
+			queries:     4,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			root := newRootCmd()
+			root.SetArgs(append([]string{"backfill", "--config-file", f.Name()}, each.args...))
+
+			err := root.Execute()
+
+			assert.ErrorIs(t, err, each.wantErr)
+			if each.wantErr == nil {
+				assert.Equal(t, each.queries, s.Queries())
+				assert.FileExists(t, filepath.Join(dir, ".spacetrack-backfill-decay.json"))
+			}
+		})
+	}
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ... sources of the values of the configuration, from highest to lowest precedence.
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceFile    = "file"
+	sourceDefault = "default"
+
+	// ... size of the passphrase used to encrypt the credentials, AES-256.
+	passphraseSize = 32
+)
+
+var (
+	errConfigFileExists = errors.New("config file already exists, use --force to overwrite it")
+	errSecretFileExists = errors.New("secret file already exists, use --force to overwrite it")
+	errShowOutput       = errors.New("output must be yaml or json")
+)
+
+// ... configTemplate is the commented template written by config init.
+var configTemplate = template.Must(template.New("spacetrack.yml").Funcs(template.FuncMap{"quote": strconv.Quote}).Parse(`# go-spacetrack configuration file.
+#
+# Every value can be overridden using env vars, with the SPACETRACK_ prefix and nested keys joined by _,
+# e.g. SPACETRACK_AUTH_IDENTITY, or flags. Precedence is flags > env vars > config file > defaults.
+
+# Credentials of www.space-track.org.
+auth:
+  identity: {{ quote .Identity }}
+  password: {{ quote .Password }}
+{{- if .SecretFile }}
+# File with the passphrase, encoded in base64, used to decrypt identity and password.
+secret_file: {{ quote .SecretFile }}
+{{- end }}
+
+# Dir where all the data is persisted: ${work_dir}/spacetrack-tle, ${work_dir}/spacetrack-dec and ${work_dir}/spacetrack-cdm
+work_dir: /tmp/spacetrack
+# Interval of time between fetches, e.g. 30m, 1h.
+interval: 1h
+# Rest call to execute: tle, cdm, dec (decay) or all.
+rest_call: all
+# Format of the persisted files: json, xml, csv, html, ndjson or parquet.
+format: json
+# Fetch only the records newer than the high-water marks in ${work_dir}/.spacetrack-state.json, instead of every
+# record of the queries, so the run folders aren't snapshots anymore.
+incremental: false
+# Persist only the records added or updated since the previous fetch, with a changeset of the objects in each run folder.
+dedup: false
+# Dir where each raw response of space-track is saved, to replay the run later without network.
+# record: /tmp/spacetrack-recordings
+# Dir of the recordings which answer the requests instead of space-track. Credentials aren't needed.
+# replay: /tmp/spacetrack-recordings
+
+# Http listener exposing the prometheus metrics under /metrics and the health under /healthz and /readyz,
+# disabled if empty, e.g. :9090
+server:
+  address: ""
+  # Intervals a job can go without a successful run before /readyz fails.
+  ready_intervals: 3
+
+# Connection to space-track. Changes are applied on restart.
+http:
+  base_url: https://www.space-track.org
+  user_agent: go-spacetrack
+  # Maximum time of each request, and of each execution of a job including the waits of the rate limiter.
+  timeout: 1m
+  job_timeout: 2m
+  # http, https or socks5 proxy. If empty, HTTP_PROXY, HTTPS_PROXY and NO_PROXY env vars are used.
+  proxy: ""
+  # PEM bundle trusted besides the system certificates, e.g. the CA of a corporate proxy.
+  ca_file: ""
+  # PEM client certificate and key, set together.
+  # cert_file: /etc/spacetrack/client.pem
+  # key_file: /etc/spacetrack/client.key
+  # 1.0, 1.1, 1.2 or 1.3
+  tls_min_version: "1.2"
+  # dial_timeout: 30s
+  # tls_handshake_timeout: 10s
+  # response_header_timeout: 30s
+  # idle_conn_timeout: 90s
+  # max_idle_conns: 100
+  # max_idle_conns_per_host: 2
+  # max_conns_per_host: 0
+
+# OpenTelemetry spans of each run: none, stdout, otlp_http or otlp_grpc.
+tracing:
+  exporter: none
+  # endpoint: localhost:4318
+  # insecure: true
+  sample_ratio: 1
+
+logger:
+  # Production encoder config, with less verbose output.
+  prod: false
+  console_appender:
+    # debug, info, warn, error, dpanic, panic or fatal.
+    level: info
+    date_format: rfc3339
+    # stdout or stderr.
+    target: stdout
+    # console or json, e.g. for docker log drivers.
+    encoding: console
+  # file_appenders:
+  # - file: /tmp/spacetrack.json
+  #   level: debug
+  #   date_format: rfc3339
+  #   # Rotate the file each max_size megabytes, keeping max_backups files up to max_age days.
+  #   rotation:
+  #     max_size: 100
+  #     max_age: 30
+  #     max_backups: 5
+  #     compress: true
+  #     # Rotates the file every interval too, whatever its size.
+  #     interval: 24h
+  # RFC 5424 syslog servers, over udp, tcp or unix sockets.
+  # syslog_appenders:
+  # - network: udp
+  #   address: localhost:514
+  #   level: info
+  #   facility: user
+  #   tag: go-spacetrack
+  # Log the http bodies, in debug level only, truncated to limit bytes.
+  body_dump:
+    enabled: false
+    limit: 1024
+`))
+
+// ... configCmd groups all the subcommands related to the configuration of the application
+func (a *app) configCmd() *cobra.Command {
+	config := &cobra.Command{
+		Use:         "config",
+		Short:       "manage the configuration of go-spacetrack",
+		Long:        "manage the configuration of go-spacetrack",
+		Annotations: map[string]string{skipLoggerAnnotation: ""},
+	}
+
+	config.AddCommand(a.configValidateCmd(), newConfigInitCmd(), a.configShowCmd())
+
+	return config
+}
+
+// ... configValidateCmd checks the configuration, printing all the problems found and exiting with non-zero code if any.
+func (a *app) configValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "validate",
+		Short:        "validate the configuration, reporting all the problems found",
+		Long:         "validate the configuration merged from flags, env vars and config file, reporting all the problems found. It exits with non-zero code if the configuration is invalid, so it can be used in CI",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.config().Validate(); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "configuration is valid")
+
+			return nil
+		},
+	}
+}
+
+// ... initValues are the values asked to the user to fill the config template.
+type initValues struct {
+	Identity   string
+	Password   string
+	SecretFile string
+}
+
+// ... newConfigInitCmd writes a commented config file, asking the user for the credentials.
+func newConfigInitCmd() *cobra.Command {
+	var (
+		output     string
+		secretFile string
+		force      bool
+		noInput    bool
+	)
+
+	initCmd := &cobra.Command{
+		Use:          "init",
+		Short:        "write a commented config file",
+		Long:         "write a commented config file, asking for the credentials and optionally encrypting them with a passphrase persisted in --secret-file",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var values initValues
+
+			if !force && fileExists(output) {
+				return errConfigFileExists
+			}
+
+			if !noInput {
+				if err := promptInitValues(cmd.InOrStdin(), cmd.OutOrStdout(), &values, secretFile, force); err != nil {
+					return err
+				}
+			}
+
+			if err := writeConfigTemplate(output, values); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "config file written to "+output)
+
+			return nil
+		},
+	}
+
+	initCmd.Flags().StringVarP(&output, "output", "o", buildCfg("."), "file where the config is written")
+	initCmd.Flags().StringVar(&secretFile, "secret-file", "./spacetrack.key", "file where the passphrase is written if the credentials are encrypted")
+	initCmd.Flags().BoolVar(&force, "force", false, "overwrite the config and secret files if they already exist")
+	initCmd.Flags().BoolVar(&noInput, "no-input", false, "don't ask for the credentials, leaving them empty")
+
+	return initCmd
+}
+
+// ... promptInitValues asks for the credentials and, if the user wants to, encrypts them writing the passphrase to secretFile,
+// which is only overwritten if force is set.
+func promptInitValues(in io.Reader, out io.Writer, values *initValues, secretFile string, force bool) error {
+	var (
+		reader = bufio.NewReader(in)
+		err    error
+	)
+
+	if values.Identity, err = prompt(reader, out, "identity: "); err != nil {
+		return err
+	}
+
+	if values.Password, err = promptPassword(in, reader, out, "password: "); err != nil {
+		return err
+	}
+
+	encrypt, err := prompt(reader, out, "encrypt credentials using "+secretFile+"? [y/N]: ")
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(encrypt, "y") || strings.EqualFold(encrypt, "yes") {
+		return encryptInitValues(values, secretFile, force)
+	}
+
+	return nil
+}
+
+func prompt(reader *bufio.Reader, out io.Writer, question string) (string, error) {
+	fmt.Fprint(out, question)
+
+	line, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// ... promptPassword reads the answer without echoing it if the input is a terminal, or as any other prompt otherwise,
+// e.g. piped input.
+func promptPassword(in io.Reader, reader *bufio.Reader, out io.Writer, question string) (string, error) {
+	f, ok := in.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return prompt(reader, out, question)
+	}
+
+	fmt.Fprint(out, question)
+
+	b, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(out)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// ... encryptInitValues generates a random passphrase, writes it to secretFile and encrypts identity and password with it,
+// the same way they are decrypted when the secret_file is loaded, see SpaceTrackAuth.credentials. An existing secretFile
+// is only overwritten if force is set, as the configs encrypted with its passphrase couldn't be decrypted anymore.
+func encryptInitValues(values *initValues, secretFile string, force bool) error {
+	var passphrase = make([]byte, passphraseSize)
+
+	if !force && fileExists(secretFile) {
+		return errSecretFileExists
+	}
+
+	if _, err := rand.Read(passphrase); err != nil {
+		return err
+	}
+
+	identity, err := Encrypt([]byte(values.Identity), passphrase)
+	if err != nil {
+		return err
+	}
+
+	password, err := Encrypt([]byte(values.Password), passphrase)
+	if err != nil {
+		return err
+	}
+
+	if err := WritePassphraseToFile(secretFile, Encode(passphrase), force); err != nil {
+		return err
+	}
+
+	values.Identity, values.Password, values.SecretFile = string(identity), string(password), secretFile
+
+	return nil
+}
+
+func writeConfigTemplate(output string, values initValues) error {
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(output, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return configTemplate.Execute(f, values)
+}
+
+// ... configEntry is each one of the values of the effective configuration printed by config show.
+type configEntry struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  any    `json:"value" yaml:"value"`
+	Source string `json:"source" yaml:"source"`
+}
+
+// ... configShowCmd prints the effective configuration, merged from all the sources, with the secrets masked.
+func (a *app) configShowCmd() *cobra.Command {
+	var output string
+
+	show := &cobra.Command{
+		Use:          "show",
+		Short:        "print the effective configuration",
+		Long:         "print the effective configuration merged from flags, env vars, config file and defaults, with the source of each value and the secrets masked",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries := effectiveConfig(a.v, cmd.Flags())
+
+			switch strings.ToLower(output) {
+			case "yaml":
+				enc := yaml.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent(2)
+				return enc.Encode(entries)
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			default:
+				return errShowOutput
+			}
+		},
+	}
+
+	show.Flags().StringVarP(&output, "output", "o", "yaml", "output of the configuration: yaml or json")
+
+	return show
+}
+
+// ... effectiveConfig returns all the keys known by viper, sorted, with their values and sources.
+func effectiveConfig(v *viper.Viper, flags *pflag.FlagSet) []configEntry {
+	var (
+		keys    = v.AllKeys()
+		entries = make([]configEntry, len(keys))
+	)
+
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		entries[i] = configEntry{
+			Key:    key,
+			Value:  maskValue(key, v.Get(key)),
+			Source: configSource(v, flags, key),
+		}
+	}
+
+	return entries
+}
+
+// ... configSource returns where the value of the key comes from, following the precedence of bindConfig.
+func configSource(v *viper.Viper, flags *pflag.FlagSet, key string) string {
+	for flag, k := range flagKeys {
+		if k == key {
+			if f := flags.Lookup(flag); f != nil && f.Changed {
+				return sourceFlag
+			}
+		}
+	}
+
+	if _, ok := os.LookupEnv(strings.ToUpper(envPrefix + "_" + strings.ReplaceAll(key, ".", "_"))); ok {
+		return sourceEnv
+	}
+
+	if v.InConfig(key) {
+		return sourceFile
+	}
+
+	return sourceDefault
+}
+
+// ... maskValue hides the values of the sensitive keys, like the password. Empty values are kept, so the user knows they are missing.
+func maskValue(key string, value any) any {
+	var name = key[strings.LastIndex(key, ".")+1:]
+
+	if spacetrack.IsSensitive(name) && fmt.Sprint(value) != "" {
+		return spacetrack.RedactedValue
+	}
+
+	return value
+}
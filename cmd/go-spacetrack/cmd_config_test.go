@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+// ... executeRoot runs the root command with the args and stdin passed, returning its output.
+func executeRoot(t *testing.T, stdin string, args ...string) (string, error) {
+	var out bytes.Buffer
+
+	root := newRootCmd()
+	root.SetIn(strings.NewReader(stdin))
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(args)
+
+	err := root.Execute()
+
+	return out.String(), err
+}
+
+func TestConfigInit(t *testing.T) {
+	t.Run("config init with plain credentials", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "spacetrack.yml")
+
+		_, err := executeRoot(t, "someone\nsecret\nn\n", "config", "init", "--output", output, "--config-file", output+".notexistent")
+		assert.ErrorIs(t, err, errCheckConfigFile, "config file passed explicitly must exist")
+
+		if _, err = executeRoot(t, "someone\nsecret\nn\n", "config", "init", "--output", output); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := loadConfig(t, nil, nil, string(b))
+
+		assert.Equal(t, "someone", got.Auth.Identity)
+		assert.Equal(t, "secret", got.Auth.Password)
+		assert.Nil(t, got.Validate())
+	})
+
+	t.Run("config init with encrypted credentials", func(t *testing.T) {
+		var (
+			dir        = t.TempDir()
+			output     = filepath.Join(dir, "spacetrack.yml")
+			secretFile = filepath.Join(dir, "spacetrack.key")
+		)
+
+		if _, err := executeRoot(t, "someone\nsecret\ny\n", "config", "init", "--output", output, "--secret-file", secretFile); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NotContains(t, string(b), "someone")
+		assert.NotContains(t, string(b), "secret\"")
+
+		got := loadConfig(t, nil, nil, string(b))
+		credentials, err := got.Auth.Credentials()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, spacetrack.Credentials{Identity: "someone", Password: "secret"}, credentials)
+
+		info, err := os.Stat(secretFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "the passphrase is only readable by its owner")
+	})
+
+	t.Run("config init doesn't overwrite the secret file without force", func(t *testing.T) {
+		var (
+			dir        = t.TempDir()
+			output     = filepath.Join(dir, "spacetrack.yml")
+			secretFile = filepath.Join(dir, "spacetrack.key")
+			previous   = strings.Repeat("k", 2*passphraseSize*4/3+8)
+		)
+
+		if err := os.WriteFile(secretFile, []byte(previous), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := executeRoot(t, "someone\nsecret\ny\n", "config", "init", "--output", output, "--secret-file", secretFile)
+		assert.ErrorIs(t, err, errSecretFileExists)
+		assert.NoFileExists(t, output)
+
+		b, err := os.ReadFile(secretFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, previous, string(b), "the previous passphrase is kept")
+
+		if _, err := executeRoot(t, "someone\nsecret\ny\n", "config", "init", "--force", "--output", output, "--secret-file", secretFile); err != nil {
+			t.Fatal(err)
+		}
+
+		passphrase, err := ReadOnlyFilePassphrase(secretFile)
+		assert.Nil(t, err, "the new passphrase isn't followed by the bytes of the longer previous one")
+		assert.Len(t, passphrase, passphraseSize)
+	})
+
+	t.Run("config init doesn't overwrite without force", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "spacetrack.yml")
+		if err := os.WriteFile(output, []byte("work_dir: /tmp\n"), 0666); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := executeRoot(t, "", "config", "init", "--no-input", "--output", output)
+		assert.ErrorIs(t, err, errConfigFileExists)
+
+		_, err = executeRoot(t, "", "config", "init", "--no-input", "--force", "--output", output)
+		assert.Nil(t, err)
+	})
+}
+
+func TestConfigShow(t *testing.T) {
+	f := createFile(t, t.TempDir(), "spacetrack.yml", 0666)
+	if _, err := f.WriteString("auth:\n  identity: someone\n  password: s3cr3t\nwork_dir: /tmp/from-file\ninterval: 2h\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SPACETRACK_INTERVAL", "3h")
+
+	out, err := executeRoot(t, "", "config", "show", "--output", "json", "--config-file", f.Name(), "--format", "xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []configEntry
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]configEntry, len(entries))
+	for _, e := range entries {
+		got[e.Key] = e
+	}
+
+	assert.Equal(t, configEntry{Key: "auth.password", Value: spacetrack.RedactedValue, Source: sourceFile}, got["auth.password"])
+	assert.Equal(t, configEntry{Key: "auth.identity", Value: spacetrack.RedactedValue, Source: sourceFile}, got["auth.identity"])
+	assert.Equal(t, configEntry{Key: "work_dir", Value: "/tmp/from-file", Source: sourceFile}, got["work_dir"])
+	assert.Equal(t, configEntry{Key: "interval", Value: "3h", Source: sourceEnv}, got["interval"])
+	assert.Equal(t, configEntry{Key: "format", Value: "xml", Source: sourceFlag}, got["format"])
+	assert.Equal(t, configEntry{Key: "rest_call", Value: "all", Source: sourceDefault}, got["rest_call"])
+	assert.NotContains(t, out, "s3cr3t")
+}
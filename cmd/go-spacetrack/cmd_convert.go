@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/spf13/cobra"
+)
+
+var errConvertClass = errors.New("class must be tle, cdm or dec")
+
+// ... convertFunc reads the records of a class from the input, in the format passed or the one of the extension of its
+// files, and writes them all into the output file in the other format, returning the amount of records converted.
+type convertFunc func(in, out string, from, to persist.Format) (int, error)
+
+// ... converters maps each rest call to the conversion of the records of its class.
+var converters = map[spacetrack.RestCall]convertFunc{
+	spacetrack.Tle:   convert[spacetrack.SpaceTrackTleUnit],
+	spacetrack.Cdm:   convert[spacetrack.SpaceTrackCdmUnit],
+	spacetrack.Decay: convert[spacetrack.SpaceTrackDecayUnit],
+}
+
+// ... convertCmd converts the records persisted in one format to another one.
+func (a *app) convertCmd() *cobra.Command {
+	var (
+		from, to persist.Format
+		class    = spacetrack.Tle
+	)
+
+	cmd := &cobra.Command{
+		Use:   "convert <in> <out>",
+		Short: "convert the records persisted from one format to another",
+		Long: "convert the records of a class persisted in one format, a file or the folder of an execution, e.g. ${work_dir}/spacetrack-tle/1672531200, " +
+			"which is read whole whether it was persisted in one file or in one file per row, to a single file in another format. " +
+			"If --from or --to are not set, the formats are the ones of the extensions of the files",
+		Example:      "go-spacetrack convert --class tle --from xml --to ndjson /tmp/spacetrack/spacetrack-tle/1672531200 /tmp/tle-1672531200.ndjson",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fn, ok := converters[class]
+			if !ok {
+				return errConvertClass
+			}
+
+			if to == "" {
+				var err error
+				if to, err = persist.FormatOf(args[1]); err != nil {
+					return err
+				}
+			}
+
+			n, err := fn(args[0], args[1], from, to)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%d records converted to %s\n", n, args[1])
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Var(&class, "class", "rest call whose records are converted: tle, cdm or dec")
+	cmd.Flags().Var(&from, "from", "format of the input, by default the one of the extension of its files")
+	cmd.Flags().Var(&to, "to", "format of the output, by default the one of its extension")
+
+	for _, name := range []string{"from", "to"} {
+		//nolint:errcheck
+		cmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return persist.FormatValues, cobra.ShellCompDirectiveDefault
+		})
+	}
+
+	return cmd
+}
+
+func convert[T spacetrack.Unit](in, out string, from, to persist.Format) (int, error) {
+	arr, err := readRecords[T](in, from)
+	if err != nil {
+		return 0, err
+	}
+
+	m, err := persist.GetMarshaller(to)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := m.Marshal(spacetrack.Wrap(arr))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(arr), writeFileAtomic(out, b)
+}
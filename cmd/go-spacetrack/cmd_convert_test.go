@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+// ... assertRoundTrip converts the records, persisted one file per row in xml, to every other format and back to xml,
+// reading the records of the last conversion.
+func assertRoundTrip[T spacetrack.Unit](t *testing.T, class string, want []T) {
+	for _, f := range []persist.Format{persist.Json, persist.Csv, persist.Html, persist.Ndjson, persist.Parquet} {
+		t.Run(class+" from xml to "+f.String()+" and back", func(t *testing.T) {
+			var (
+				dir       = t.TempDir()
+				converted = filepath.Join(dir, "converted."+f.String())
+				back      = filepath.Join(dir, "back.xml")
+			)
+
+			out, err := executeRoot(t, "", "convert", "--class", class, "--from", "xml", writeSnapshot(t, persist.OneFilePerRow, persist.Xml, want...), converted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Contains(t, out, "2 records converted")
+
+			if _, err := executeRoot(t, "", "convert", "--class", class, "--to", "xml", converted, back); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := readRecords[T](back, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// ... the xml names of the records decoded are set, so they are compared as json, which ignores them.
+			wantJSON, _ := json.Marshal(want) //nolint:errcheck
+			gotJSON, _ := json.Marshal(got)   //nolint:errcheck
+
+			assert.JSONEq(t, string(wantJSON), string(gotJSON))
+		})
+	}
+}
+
+func TestConvertCmd(t *testing.T) {
+	assertRoundTrip(t, "tle", []spacetrack.SpaceTrackTleUnit{
+		{NoradCatId: "25544", ObjectName: "ISS (ZARYA)", Epoch: "2022-01-01T00:00:00", MeanMotion: "15.50103472"},
+		{NoradCatId: "5", ObjectName: "VANGUARD 1", Epoch: "2022-01-01T00:00:00", MeanMotion: "10.85"},
+	})
+
+	assertRoundTrip(t, "cdm", []spacetrack.SpaceTrackCdmUnit{
+		{CdmID: "1", Created: "2022-01-01 00:00:00", EmergencyReportable: "Y", MinRng: "120"},
+		{CdmID: "2", Created: "2022-01-02 00:00:00", EmergencyReportable: "N", MinRng: "900"},
+	})
+
+	assertRoundTrip(t, "dec", []spacetrack.SpaceTrackDecayUnit{
+		{NoradCatID: "49863", ObjectName: "COSMOS 1408 DEB", IntlDes: "1982-092BX"},
+		{NoradCatID: "53000", ObjectName: "STARLINK-3400"},
+	})
+
+	t.Run("only the files of the format passed are read", func(t *testing.T) {
+		folder := writeSnapshot(t, persist.OneFilePerRow, persist.Json, []spacetrack.SpaceTrackTleUnit{{NoradCatId: "25544"}, {NoradCatId: "5"}}...)
+
+		_, err := executeRoot(t, "", "convert", "--from", "xml", "--to", "json", folder, filepath.Join(t.TempDir(), "tle"))
+		assert.ErrorIs(t, err, ErrNoRecords)
+	})
+
+	t.Run("output without extension nor format", func(t *testing.T) {
+		folder := writeSnapshot(t, persist.OneFilePerRow, persist.Json, []spacetrack.SpaceTrackTleUnit{{NoradCatId: "25544"}}...)
+
+		_, err := executeRoot(t, "", "convert", folder, filepath.Join(t.TempDir(), "tle"))
+		assert.ErrorIs(t, err, persist.ErrUnknownExtension)
+	})
+
+	t.Run("all is not a class", func(t *testing.T) {
+		folder := writeSnapshot(t, persist.OneFilePerRow, persist.Json, []spacetrack.SpaceTrackTleUnit{{NoradCatId: "25544"}}...)
+
+		_, err := executeRoot(t, "", "convert", "--class", "all", folder, filepath.Join(t.TempDir(), "tle.json"))
+		assert.ErrorIs(t, err, errConvertClass)
+	})
+}
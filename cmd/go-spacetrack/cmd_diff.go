@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/spf13/cobra"
+)
+
+var errDiffOutput = errors.New("output must be table or json")
+
+// ... diffCmd compares two catalogs of element sets, e.g. two executions of the tle rest call.
+func (a *app) diffCmd() *cobra.Command {
+	var (
+		output     string
+		thresholds = spacetrack.DefaultThresholds
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <before> <after>",
+		Short: "compare two catalogs of element sets",
+		Long: "compare two catalogs of element sets, folders of executions of the tle rest call, e.g. ${work_dir}/spacetrack-tle/1672531200, or files, in any format, " +
+			"reporting the objects which have appeared, disappeared or decayed, and the ones whose elements have changed beyond the thresholds, e.g. a change of the mean motion may be a maneuver. " +
+			"A threshold of 0 disables the comparison of its element",
+		Example:      "go-spacetrack diff /tmp/spacetrack/spacetrack-tle/1672531200 /tmp/spacetrack/spacetrack-tle/1672534800 --mean-motion 0.0005",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			before, err := readRecords[spacetrack.SpaceTrackTleUnit](args[0], "")
+			if err != nil {
+				return err
+			}
+
+			after, err := readRecords[spacetrack.SpaceTrackTleUnit](args[1], "")
+			if err != nil {
+				return err
+			}
+
+			diff := spacetrack.DiffCatalogs(before, after, thresholds)
+
+			switch strings.ToLower(output) {
+			case "table":
+				return writeDiffTable(cmd.OutOrStdout(), diff)
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(diff)
+			default:
+				return errDiffOutput
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "output of the differences: table or json")
+	cmd.Flags().Float64Var(&thresholds.Inclination, "inclination", thresholds.Inclination, "minimum change of the inclination, in degrees")
+	cmd.Flags().Float64Var(&thresholds.MeanMotion, "mean-motion", thresholds.MeanMotion, "minimum change of the mean motion, in revolutions per day")
+	cmd.Flags().Float64Var(&thresholds.Eccentricity, "eccentricity", thresholds.Eccentricity, "minimum change of the eccentricity")
+	cmd.Flags().Float64Var(&thresholds.RaOfAscNode, "ra-of-asc-node", thresholds.RaOfAscNode, "minimum change of the right ascension of the ascending node, in degrees")
+
+	return cmd
+}
+
+// ... writeDiffTable writes one row per object, with its changed elements, followed by the amount of objects of each status.
+func writeDiffTable(w io.Writer, diff spacetrack.CatalogDiff) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "NORAD_CAT_ID\tOBJECT_NAME\tSTATUS\tCHANGES")
+
+	for _, o := range diff.Objects {
+		changes := make([]string, len(o.Changes))
+		for i, c := range o.Changes {
+			changes[i] = fmt.Sprintf("%s %s -> %s (%+.6g)", c.Element, formatFloat(c.Before), formatFloat(c.After), c.Delta)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", o.NoradCatID, o.ObjectName, o.Status, strings.Join(changes, ", "))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d appeared, %d disappeared, %d decayed, %d changed\n",
+		diff.Count(spacetrack.Appeared), diff.Count(spacetrack.Disappeared), diff.Count(spacetrack.Decayed), diff.Count(spacetrack.Changed))
+
+	return err
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
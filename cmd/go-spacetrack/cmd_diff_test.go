@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCmd(t *testing.T) {
+	var (
+		iss      = spacetrack.SpaceTrackTleUnit{NoradCatId: "25544", ObjectName: "ISS (ZARYA)", Epoch: "2022-01-01T00:00:00", MeanMotion: "15.50103472", Inclination: "51.6416"}
+		boosted  = spacetrack.SpaceTrackTleUnit{NoradCatId: "25544", ObjectName: "ISS (ZARYA)", Epoch: "2022-01-02T00:00:00", MeanMotion: "15.49103472", Inclination: "51.6416"}
+		debris   = spacetrack.SpaceTrackTleUnit{NoradCatId: "49863", ObjectName: "COSMOS 1408 DEB", Epoch: "2022-01-01T00:00:00", MeanMotion: "15.7", Inclination: "82.5"}
+		decayed  = spacetrack.SpaceTrackTleUnit{NoradCatId: "49863", ObjectName: "COSMOS 1408 DEB", Epoch: "2022-01-02T00:00:00", MeanMotion: "16.2", Inclination: "82.5", DecayDate: "2022-01-02"}
+		starlink = spacetrack.SpaceTrackTleUnit{NoradCatId: "44713", ObjectName: "STARLINK-1007", Epoch: "2022-01-02T00:00:00", MeanMotion: "15.06", Inclination: "53.05"}
+	)
+
+	for _, f := range []persist.Format{persist.Json, persist.Xml, persist.Csv, persist.Html, persist.Parquet} {
+		t.Run("snapshots in "+f.String(), func(t *testing.T) {
+			before := writeSnapshot(t, persist.OneFile, f, iss, debris)
+			after := writeSnapshot(t, persist.OneFile, f, boosted, decayed, starlink)
+
+			out, err := executeRoot(t, "", "diff", before, after, "-o", "json")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got spacetrack.CatalogDiff
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, 1, got.Count(spacetrack.Appeared))
+			assert.Equal(t, 1, got.Count(spacetrack.Decayed))
+			assert.Equal(t, 1, got.Count(spacetrack.Changed))
+		})
+	}
+
+	t.Run("table with the changes and the totals", func(t *testing.T) {
+		before := writeSnapshot(t, persist.OneFile, persist.Json, iss, debris)
+		after := writeSnapshot(t, persist.OneFile, persist.Xml, boosted)
+
+		out, err := executeRoot(t, "", "diff", before, after)
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "MEAN_MOTION 15.50103472 -> 15.49103472 (-0.01)")
+		assert.Contains(t, out, "49863         COSMOS 1408 DEB  disappeared")
+		assert.Contains(t, out, "0 appeared, 1 disappeared, 0 decayed, 1 changed")
+	})
+
+	t.Run("threshold of 0 disables the element", func(t *testing.T) {
+		before := writeSnapshot(t, persist.OneFile, persist.Json, iss)
+		after := writeSnapshot(t, persist.OneFile, persist.Json, boosted)
+
+		out, err := executeRoot(t, "", "diff", before, after, "--mean-motion", "0")
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "0 appeared, 0 disappeared, 0 decayed, 0 changed")
+	})
+
+	t.Run("single files can be compared", func(t *testing.T) {
+		before, err := persist.RecordFiles(writeSnapshot(t, persist.OneFile, persist.Csv, iss))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := executeRoot(t, "", "diff", before[0], writeSnapshot(t, persist.OneFile, persist.Html, boosted))
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "1 changed")
+	})
+
+	t.Run("folder without records", func(t *testing.T) {
+		_, err := executeRoot(t, "", "diff", t.TempDir(), writeSnapshot(t, persist.OneFile, persist.Json, iss))
+		assert.ErrorIs(t, err, ErrNoRecords)
+	})
+
+	t.Run("unknown output", func(t *testing.T) {
+		folder := writeSnapshot(t, persist.OneFile, persist.Json, iss)
+
+		_, err := executeRoot(t, "", "diff", folder, folder, "-o", "yaml")
+		assert.ErrorIs(t, err, errDiffOutput)
+	})
+
+	t.Run("unknown extension", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "catalog.txt")
+		if err := os.WriteFile(file, []byte("25544"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := executeRoot(t, "", "diff", file, file)
+		assert.ErrorIs(t, err, persist.ErrUnknownExtension)
+	})
+}
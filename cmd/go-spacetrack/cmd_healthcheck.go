@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	errHealthcheckAddress = errors.New("server address is empty, set server.address or use --address")
+	errUnhealthy          = errors.New("unhealthy")
+)
+
+// ... healthcheckCmd queries the health endpoints of a running program, exiting with 1 if it is not healthy,
+// so it can be used as the HEALTHCHECK of the docker image.
+func (a *app) healthcheckCmd() *cobra.Command {
+	var (
+		address string
+		ready   bool
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "healthcheck",
+		Short: "check the health of the running program through its server",
+		Long:  "check the health of the running program querying /healthz, or /readyz with --ready, of the server at server.address",
+		Args:  cobra.NoArgs,
+		// ... it runs next to the program, so it must not write into its log files.
+		Annotations: map[string]string{skipLoggerAnnotation: ""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if address == "" {
+				address = a.config().Server.Address
+			}
+
+			url, err := healthcheckUrl(address, ready)
+			if err != nil {
+				return err
+			}
+
+			return healthcheck(cmd.OutOrStdout(), url, timeout)
+		},
+	}
+
+	cmd.Flags().StringVar(&address, "address", "", "address of the server, e.g. :9090. If empty, server.address is used")
+	cmd.Flags().BoolVar(&ready, "ready", false, "check the readiness, /readyz, instead of the liveness, /healthz")
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "maximum amount of time to wait for the answer")
+
+	return cmd
+}
+
+// ... healthcheckUrl returns the url of the health endpoint, using the loopback if the server listens on every interface.
+func healthcheckUrl(address string, ready bool) (string, error) {
+	if address == "" {
+		return "", errHealthcheckAddress
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+
+	path := "/healthz"
+	if ready {
+		path = "/readyz"
+	}
+
+	return "http://" + net.JoinHostPort(host, port) + path, nil
+}
+
+func healthcheck(out io.Writer, url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+
+	res, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUnhealthy, err)
+	}
+	defer res.Body.Close()
+
+	if _, err := io.Copy(out, res.Body); err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", errUnhealthy, res.Status)
+	}
+
+	return nil
+}
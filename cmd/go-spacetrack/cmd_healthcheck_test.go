@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthcheckUrl(t *testing.T) {
+	for _, each := range []struct {
+		description, address, want string
+		ready                      bool
+		wantErr                    bool
+	}{
+		{description: "every interface uses the loopback", address: ":9090", want: "http://127.0.0.1:9090/healthz"},
+		{description: "unspecified ipv6 uses the loopback", address: "[::]:9090", want: "http://127.0.0.1:9090/healthz"},
+		{description: "host is kept", address: "spacetrack:9090", ready: true, want: "http://spacetrack:9090/readyz"},
+		{description: "empty address", wantErr: true},
+		{description: "address without port", address: "spacetrack", wantErr: true},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got, err := healthcheckUrl(each.address, each.ready)
+
+			assert.Equal(t, each.wantErr, err != nil)
+			assert.Equal(t, each.want, got)
+		})
+	}
+}
+
+func TestHealthcheckCmd(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// ... the app of the server has never authenticated, so it isn't ready.
+	addr, err := newApp().startServer(ctx, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		out, err := executeRoot(t, "", "healthcheck", "--address", addr.String())
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, `"status":"ok"`)
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		t.Setenv("SPACETRACK_SERVER_ADDRESS", addr.String())
+
+		out, err := executeRoot(t, "", "healthcheck", "--ready")
+
+		assert.ErrorIs(t, err, errUnhealthy)
+		assert.Contains(t, out, `"status":"fail"`)
+	})
+
+	t.Run("server which isn't running", func(t *testing.T) {
+		_, err := executeRoot(t, "", "healthcheck", "--address", "127.0.0.1:1", "--timeout", "1s")
+
+		assert.ErrorIs(t, err, errUnhealthy)
+	})
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/spf13/cobra"
+)
+
+// ... passesCmd writes the passes over a ground station of the objects of the element sets of a run folder, with the
+// top level format.
+func (a *app) passesCmd() *cobra.Command {
+	var (
+		o           Observation
+		start, stop string
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "passes <tle>",
+		Short: "write the passes of the objects of some element sets over a ground station",
+		Long: "predict with SGP4/SDP4 the passes over a ground station of the newest element set of each object of a folder of an execution of the tle rest call, e.g. ${work_dir}/spacetrack-tle/1672531200, or a file, " +
+			"with the time, azimuth, elevation, range and illumination of their acquisition of signal, maximum elevation and loss of signal, writing them into ${output}/passes, " +
+			"in the format of the top level configuration",
+		Example:      "go-spacetrack passes /tmp/spacetrack/spacetrack-tle/1672531200 --lat 40.4168 --lon -3.7038 --alt 0.667 --min-elevation 10 --start 2023-01-01 --stop 2023-01-02 --format csv",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if o.Start, err = parseRangeTime(start, false, ErrInvalidEphemeris); err != nil {
+				return err
+			}
+
+			if o.Stop, err = parseRangeTime(stop, true, ErrInvalidEphemeris); err != nil {
+				return err
+			}
+
+			if err := o.validate(); err != nil {
+				return err
+			}
+
+			tles, err := readRecords[spacetrack.SpaceTrackTleUnit](args[0], "")
+			if err != nil {
+				return err
+			}
+
+			cfg := a.config()
+
+			m, err := persist.GetMarshaller(cfg.Format)
+			if err != nil {
+				return err
+			}
+
+			passes, err := predictPasses(tles, o)
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				output = filepath.Join(cfg.WorkDir, "spacetrack-passes")
+			}
+
+			if err := os.MkdirAll(output, 0755); err != nil {
+				return err
+			}
+
+			if err := persist.NewWriter(m, L()).Write(context.Background(), filepath.Join(output, "passes"), passes); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%d passes written to %s\n", len(passes.Items), output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.NoradCatIDs, "norad", nil, "NORAD_CAT_ID of the objects observed, all of them if not set, e.g. 25544,48274")
+	cmd.Flags().Float64Var(&o.Observer.Latitude, "lat", 0, "geodetic latitude of the ground station, in degrees, positive to the north")
+	cmd.Flags().Float64Var(&o.Observer.Longitude, "lon", 0, "longitude of the ground station, in degrees, positive to the east")
+	cmd.Flags().Float64Var(&o.Observer.Altitude, "alt", 0, "altitude of the ground station over the WGS84 ellipsoid, in km")
+	cmd.Flags().Float64Var(&o.MinElevation, "min-elevation", 10, "elevation over the horizon, in degrees, from which an object is in a pass")
+	cmd.Flags().StringVar(&start, "start", "", "start of the window of the passes, as a date, e.g. 2023-01-01, or a time in UTC, e.g. 2023-01-01T12:00:00Z")
+	cmd.Flags().StringVar(&stop, "stop", "", "stop of the window of the passes, as a date, whose whole day is included, or a time in UTC")
+	cmd.Flags().StringVar(&output, "output", "", "folder of the passes, ${work_dir}/spacetrack-passes by default")
+
+	cmd.MarkFlagRequired("lat")   //nolint:errcheck
+	cmd.MarkFlagRequired("lon")   //nolint:errcheck
+	cmd.MarkFlagRequired("start") //nolint:errcheck
+	cmd.MarkFlagRequired("stop")  //nolint:errcheck
+	cmd.MarkFlagDirname("output") //nolint:errcheck
+
+	return cmd
+}
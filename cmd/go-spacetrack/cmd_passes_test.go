@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPassesCmd(t *testing.T) {
+	folder := writeSnapshot(t, persist.OneFile, persist.Json, vanguardTle, issTle)
+
+	t.Run("passes in the format passed", func(t *testing.T) {
+		output := t.TempDir()
+
+		out, err := executeRoot(t, "", "passes", folder, "--norad", "25544", "--lat", "40.4168", "--lon", "-3.7038", "--alt", "0.667", "--start", "2008-09-21", "--stop", "2008-09-22", "--format", "csv", "--output", output)
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "passes written to "+output)
+
+		b, err := os.ReadFile(filepath.Join(output, "passes.csv"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+
+		assert.Equal(t, "NORAD_CAT_ID,OBJECT_NAME,OBJECT_ID,AOS,AOS_AZIMUTH,AOS_ELEVATION,AOS_RANGE,AOS_ILLUMINATION,MAX,MAX_AZIMUTH,MAX_ELEVATION,MAX_RANGE,MAX_ILLUMINATION,LOS,LOS_AZIMUTH,LOS_ELEVATION,LOS_RANGE,LOS_ILLUMINATION,DURATION", lines[0])
+		assert.Greater(t, len(lines), 1)
+	})
+
+	t.Run("invalid observer", func(t *testing.T) {
+		_, err := executeRoot(t, "", "passes", folder, "--lat", "100", "--lon", "0", "--start", "2008-09-21", "--stop", "2008-09-22", "--output", t.TempDir())
+		assert.ErrorIs(t, err, ErrInvalidObserver)
+	})
+
+	t.Run("invalid start", func(t *testing.T) {
+		_, err := executeRoot(t, "", "passes", folder, "--lat", "0", "--lon", "0", "--start", "yesterday", "--stop", "2008-09-22", "--output", t.TempDir())
+		assert.ErrorIs(t, err, ErrInvalidEphemeris)
+	})
+}
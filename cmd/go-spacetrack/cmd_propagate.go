@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/spf13/cobra"
+)
+
+// ... propagateCmd writes the ephemerides of the objects of the element sets of a run folder, with the top level format
+// or as OEM.
+func (a *app) propagateCmd() *cobra.Command {
+	var (
+		p           Propagation
+		start, stop string
+		oem         bool
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "propagate <tle>",
+		Short: "write the ephemerides of the objects of some element sets",
+		Long: "propagate with SGP4/SDP4 the newest element set of each object of a folder of an execution of the tle rest call, e.g. ${work_dir}/spacetrack-tle/1672531200, or a file, " +
+			"writing the ephemeris of each one, with its position and velocity in the TEME frame and its latitude, longitude and altitude, into ${output}/ephemeris-${norad_cat_id}, " +
+			"in the format of the top level configuration or as a CCSDS OEM",
+		Example:      "go-spacetrack propagate /tmp/spacetrack/spacetrack-tle/1672531200 --norad 25544 --start 2023-01-01T00:00:00Z --stop 2023-01-01T06:00:00Z --step 60s --format csv",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if p.Start, err = parseRangeTime(start, false, ErrInvalidEphemeris); err != nil {
+				return err
+			}
+
+			if p.Stop, err = parseRangeTime(stop, true, ErrInvalidEphemeris); err != nil {
+				return err
+			}
+
+			tles, err := readRecords[spacetrack.SpaceTrackTleUnit](args[0], "")
+			if err != nil {
+				return err
+			}
+
+			cfg := a.config()
+
+			var m persist.Marshaller = OEMMarshaller{}
+			if !oem {
+				if m, err = persist.GetMarshaller(cfg.Format); err != nil {
+					return err
+				}
+			}
+
+			if output == "" {
+				output = filepath.Join(cfg.WorkDir, "spacetrack-ephemeris")
+			}
+
+			if err := os.MkdirAll(output, 0755); err != nil {
+				return err
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			n, err := propagate(ctx, tles, p, persist.NewWriter(m, L()), output)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%d ephemerides written to %s\n", n, output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&p.NoradCatIDs, "norad", nil, "NORAD_CAT_ID of the objects to propagate, all of them if not set, e.g. 25544,48274")
+	cmd.Flags().StringVar(&start, "start", "", "start of the ephemerides, included, as a date, e.g. 2023-01-01, or a time in UTC, e.g. 2023-01-01T12:00:00Z")
+	cmd.Flags().StringVar(&stop, "stop", "", "stop of the ephemerides, included, as a date, whose whole day is included, or a time in UTC")
+	cmd.Flags().DurationVar(&p.Step, "step", time.Minute, "time between the points of the ephemerides")
+	cmd.Flags().BoolVar(&oem, "oem", false, "if set to true, the ephemerides are written as CCSDS OEM instead of in the configured format")
+	cmd.Flags().StringVar(&output, "output", "", "folder of the ephemerides, ${work_dir}/spacetrack-ephemeris by default")
+
+	cmd.MarkFlagRequired("start") //nolint:errcheck
+	cmd.MarkFlagRequired("stop")  //nolint:errcheck
+	cmd.MarkFlagDirname("output") //nolint:errcheck
+
+	return cmd
+}
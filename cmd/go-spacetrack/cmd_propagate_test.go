@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropagateCmd(t *testing.T) {
+	folder := writeSnapshot(t, persist.OneFile, persist.Json, vanguardTle, issTle)
+
+	t.Run("ephemeris in the format passed", func(t *testing.T) {
+		output := t.TempDir()
+
+		out, err := executeRoot(t, "", "propagate", folder, "--norad", "5", "--start", "2000-06-27T18:50:19Z", "--stop", "2000-06-27T19:50:19Z", "--step", "10m", "--format", "csv", "--output", output)
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "1 ephemerides written")
+
+		b, err := os.ReadFile(filepath.Join(output, "ephemeris-5.csv"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+
+		assert.Equal(t, "NORAD_CAT_ID,OBJECT_NAME,OBJECT_ID,EPOCH,X,Y,Z,X_DOT,Y_DOT,Z_DOT,LATITUDE,LONGITUDE,ALTITUDE", lines[0])
+		assert.Len(t, lines, 8, "header and a point every 10 minutes, start and stop included")
+		assert.NoFileExists(t, filepath.Join(output, "ephemeris-25544.csv"))
+	})
+
+	t.Run("dates as stop include the whole day", func(t *testing.T) {
+		output := t.TempDir()
+
+		_, err := executeRoot(t, "", "propagate", folder, "--norad", "5", "--start", "2000-06-28", "--stop", "2000-06-28", "--step", "6h", "--format", "csv", "--output", output)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := os.ReadFile(filepath.Join(output, "ephemeris-5.csv"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+
+		if assert.Len(t, lines, 6, "header and a point every 6 hours until the midnight after the stop") {
+			assert.Contains(t, lines[5], "2000-06-29T00:00:00")
+		}
+	})
+
+	t.Run("ephemerides as oem", func(t *testing.T) {
+		output := t.TempDir()
+
+		_, err := executeRoot(t, "", "propagate", folder, "--start", "2008-09-21", "--stop", "2008-09-22", "--oem", "--output", output)
+
+		assert.Nil(t, err)
+		assert.FileExists(t, filepath.Join(output, "ephemeris-5.oem"))
+		assert.FileExists(t, filepath.Join(output, "ephemeris-25544.oem"))
+	})
+
+	t.Run("invalid start", func(t *testing.T) {
+		_, err := executeRoot(t, "", "propagate", folder, "--start", "yesterday", "--stop", "2008-09-22", "--output", t.TempDir())
+		assert.ErrorIs(t, err, ErrInvalidEphemeris)
+	})
+}
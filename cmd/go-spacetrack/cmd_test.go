@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+// ... loadConfig runs the same configuration loading as the root command, with the arguments, env vars and file content passed.
+func loadConfig(t *testing.T, args []string, env map[string]string, content string) Config {
+	f := createFile(t, t.TempDir(), "spacetrack.yml", 0666)
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+
+	a := newApp()
+	root := a.rootCmd()
+	if err := root.ParseFlags(append(args, "--config-file", f.Name())); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := root.PersistentPreRunE(root, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return a.config()
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	const (
+		flagValue = "/tmp/from-flag"
+		envValue  = "/tmp/from-env"
+		fileValue = "/tmp/from-file"
+	)
+
+	for _, each := range []struct {
+		description     string
+		flag, env, file bool
+		want            string
+	}{
+		{description: "default when no source is set", want: ""},
+		{description: "file over default", file: true, want: fileValue},
+		{description: "env over default", env: true, want: envValue},
+		{description: "env over file", env: true, file: true, want: envValue},
+		{description: "flag over default", flag: true, want: flagValue},
+		{description: "flag over file", flag: true, file: true, want: flagValue},
+		{description: "flag over env", flag: true, env: true, want: flagValue},
+		{description: "flag over env and file", flag: true, env: true, file: true, want: flagValue},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var (
+				args    []string
+				env     = map[string]string{}
+				content = "interval: 2h\n"
+			)
+
+			if each.flag {
+				args = append(args, "--work-dir", flagValue)
+			}
+
+			if each.env {
+				env["SPACETRACK_WORK_DIR"] = envValue
+			}
+
+			if each.file {
+				content += "work_dir: " + fileValue + "\n"
+			}
+
+			got := loadConfig(t, args, env, content)
+
+			assert.Equal(t, each.want, got.WorkDir)
+			assert.Equal(t, "2h", got.Interval, "values of the file which are not overridden must be kept")
+		})
+	}
+}
+
+func TestConfigNestedEnvVars(t *testing.T) {
+	got := loadConfig(t, []string{"--password", "flag-password"}, map[string]string{
+		"SPACETRACK_AUTH_IDENTITY":                 "env-identity",
+		"SPACETRACK_AUTH_PASSWORD":                 "env-password",
+		"SPACETRACK_LOGGER_CONSOLE_APPENDER_LEVEL": "warn",
+	}, "auth:\n  identity: file-identity\n  password: file-password\nlogger:\n  console_appender:\n    level: debug\n")
+
+	assert.Equal(t, "env-identity", got.Auth.Identity)
+	assert.Equal(t, "flag-password", got.Auth.Password)
+	assert.Equal(t, LoggerLevel(WarnLevel), got.Logger.ConsoleAppender.LoggerFileLevel)
+}
+
+func TestConfigLogFlags(t *testing.T) {
+	got := loadConfig(t, []string{"--log-level", "debug", "--log-format", "json"}, map[string]string{
+		"SPACETRACK_LOGGER_CONSOLE_APPENDER_LEVEL": "warn",
+	}, "logger:\n  console_appender:\n    level: error\n    encoding: console\n")
+
+	assert.Equal(t, LoggerLevel(DebugLevel), got.Logger.ConsoleAppender.LoggerFileLevel)
+	assert.Equal(t, LoggerEncoding(JsonEncoding), got.Logger.ConsoleAppender.Encoding)
+}
+
+func TestConfigDefaults(t *testing.T) {
+	got := loadConfig(t, nil, nil, "")
+
+	assert.Equal(t, "1h", got.Interval)
+	assert.Equal(t, persist.Json, got.Format)
+	assert.Equal(t, spacetrack.All, got.RestCall)
+	assert.True(t, got.OneFile)
+	assert.Equal(t, LoggerLevel(InfoLevel), got.Logger.ConsoleAppender.LoggerFileLevel)
+	assert.Equal(t, LoggerEncoding(ConsoleEncoding), got.Logger.ConsoleAppender.Encoding)
+}
+
+func TestConfigFileNotFound(t *testing.T) {
+	root := newRootCmd()
+	if err := root.ParseFlags([]string{"--config-file", "./notexistentfile.yml"}); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.ErrorIs(t, root.PersistentPreRunE(root, nil), errCheckConfigFile)
+}
+
+func TestConfigInspectionSkipsLogger(t *testing.T) {
+	var (
+		dir     = t.TempDir()
+		logFile = filepath.Join(dir, "spacetrack.json")
+		content = "auth:\n  identity: identity\n  password: password\nwork_dir: " + dir + "\nlogger:\n  file_appenders:\n  - file: " + logFile + "\n    level: info\n"
+		config  = filepath.Join(dir, "spacetrack.yml")
+	)
+	defer SetLogger(nil)
+
+	if err := os.WriteFile(config, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"config", "validate"}, {"config", "show"}, {"healthcheck", "--address", "127.0.0.1:1", "--timeout", "10ms"}} {
+		t.Run(args[0]+" "+args[1], func(t *testing.T) {
+			executeRoot(t, "", append(args, "--config-file", config)...) //nolint:errcheck
+
+			assert.NoFileExists(t, logFile, "the log files aren't opened by the commands which only inspect the configuration")
+		})
+	}
+
+	t.Run("commands running jobs configure the logger", func(t *testing.T) {
+		loadConfig(t, nil, nil, content)
+
+		assert.FileExists(t, logFile)
+	})
+}
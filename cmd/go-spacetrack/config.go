@@ -0,0 +1,685 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+var (
+	// ErrDateTimeFormatNotAllowed is used to indicate to the user that the format which is trying to use is not allowed
+	// for zap logger. Possible values are in this file and there are quite of them.
+	ErrDateTimeFormatNotAllowed = errors.New("date time format not allowed for zap logger")
+	// ErrLoggerLevelNotAllowed is used to indicate that the logger level the user has specified is not allowed.
+	ErrLoggerLevelNotAllowed = errors.New("logger level not allowed")
+	// ErrIncorrectSecret is thrown when the secret to decrypt credentials is incorrect
+	ErrIncorrectSecret = errors.New("incorrect secret")
+	// ErrConsoleTargetNotAllowed is used to indicate that the console appender can only write to stdout or stderr.
+	ErrConsoleTargetNotAllowed = errors.New("console target not allowed")
+	// ErrLoggerEncodingNotAllowed is used to indicate that the encoding of the console appender is not console or json.
+	ErrLoggerEncodingNotAllowed = errors.New("logger encoding not allowed")
+	// ErrLoggerAppender is returned when some appender of the logger can't be opened, e.g. a file without permissions.
+	ErrLoggerAppender = errors.New("logger appender can't be opened")
+)
+
+// Config is the main configuration structure of the project
+type Config struct {
+	// Auth is the authentication structure.
+	Auth SpaceTrackAuth `json:"auth" yaml:"auth" mapstructure:"auth"`
+	// WorkDir is the parent folder where all the space track data will be persisted.
+	WorkDir string `json:"work_dir" yaml:"work_dir" mapstructure:"work_dir"`
+	// Interval is used to execute the script each time.Duration.
+	Interval string `json:"interval" yaml:"interval" mapstructure:"interval"`
+	// OneFile allows us to split each response in one item per file
+	OneFile bool `json:"one_file" yaml:"one_file" mapstructure:"one_file"`
+	// SecretFile is the file which contains the passphrase, encoded in base64, to decrypt identity and password.
+	SecretFile string `json:"secret_file" yaml:"secret_file" mapstructure:"secret_file"`
+	// RestCall is the rest call that we want to execute to www.space-track.org, being tle, dec, cdm and all(meaning the three before mentioned)
+	RestCall spacetrack.RestCall `json:"rest_call" yaml:"rest_call" mapstructure:"rest_call"`
+	// Format is how we persist the data. We can persist de data using xml, json, csv, html, ndjson and parquet format
+	Format persist.Format `json:"format" yaml:"format" mapstructure:"format"`
+	// Daemon if true, the program keeps running, fetching data each Interval and reloading the configuration when it changes.
+	Daemon bool `json:"daemon" yaml:"daemon" mapstructure:"daemon"`
+	// Server is the http listener exposing the metrics. It is disabled if its address is empty.
+	Server Server `json:"server" yaml:"server" mapstructure:"server"`
+	// Tracing is the OpenTelemetry configuration. It is disabled by default.
+	Tracing Tracing `json:"tracing" yaml:"tracing" mapstructure:"tracing"`
+	// HTTP is the connection to space-track: url, timeouts, proxy and certificates.
+	HTTP HTTP `json:"http" yaml:"http" mapstructure:"http"`
+	// Record is the dir where each raw response of space-track is saved, with the metadata of its request, to replay
+	// the run later. If empty, nothing is recorded.
+	Record string `json:"record" yaml:"record" mapstructure:"record"`
+	// Replay is the dir of the recordings which answer the requests, instead of space-track, running the whole
+	// parse and persist pipeline without network. If empty, space-track is requested.
+	Replay string `json:"replay" yaml:"replay" mapstructure:"replay"`
+	// Incremental only fetches the records newer than the high-water marks of the previous fetches of each job, so the
+	// run folders aren't snapshots of the whole query anymore. It is disabled by default.
+	Incremental bool `json:"incremental" yaml:"incremental" mapstructure:"incremental"`
+	// Dedup only persists the records added or updated since the previous fetch of each job, writing the changes of
+	// the objects, added, updated or removed, in a changeset next to them.
+	Dedup bool `json:"dedup" yaml:"dedup" mapstructure:"dedup"`
+	// RateLimit is the maximum amount of requests per minute to space-track, shared by all the jobs.
+	RateLimit int `json:"rate_limit" yaml:"rate_limit" mapstructure:"rate_limit"`
+	// Jobs are the fetches to execute, each one with its own interval. If empty, a job is built from the top level fields.
+	Jobs []Job `json:"jobs" yaml:"jobs" mapstructure:"jobs"`
+	// Logger is the logger structure
+	Logger Logger `json:"logger" yaml:"logger" mapstructure:"logger"`
+}
+
+// SpaceTrackAuth contains the username, aka identity, and password of the credentials.
+type SpaceTrackAuth struct {
+	// Identity is the username and it is inside the config file or passed as parameter.
+	Identity string `json:"identity" yaml:"identity" mapstructure:"identity"`
+	// Password is the password of the user and it is inside the config file or passed as parameter.
+	Password string `json:"password" yaml:"password" mapstructure:"password"`
+	// Secret is the passphrase read from the secret file to decrypt identity and password. It is never persisted.
+	Secret string `json:"-" yaml:"-" mapstructure:"-"`
+}
+
+// Credentials returns the identity and password, decrypting them with the secret if there is one.
+func (sta SpaceTrackAuth) Credentials() (spacetrack.Credentials, error) {
+	cred := spacetrack.Credentials{Identity: sta.Identity, Password: sta.Password}
+
+	if sta.Secret != "" {
+		var err error
+
+		if cred.Identity, err = sta.decrypt(sta.Identity); err != nil {
+			return cred, err
+		}
+		if cred.Password, err = sta.decrypt(sta.Password); err != nil {
+			return cred, err
+		}
+	}
+
+	return cred, nil
+}
+
+func (sta SpaceTrackAuth) decrypt(value string) (string, error) {
+	decrypted, err := Decrypt([]byte(value), []byte(sta.Secret))
+	if err != nil {
+		Warn("incorrect secret", zap.Error(err))
+		return "", ErrIncorrectSecret
+	}
+	return string(decrypted), nil
+}
+
+// Logger is where all zap logger(library) stuff will go.
+type Logger struct {
+	// Production when we are using in production mode and we don't want a lot of output.
+	Production bool `json:"prod" yaml:"prod" mapstructure:"prod"`
+	// FileAppenders are the file to append content.
+	FileAppenders []FileLoggerAppender `json:"file_appenders" yaml:"file_appenders" mapstructure:"file_appenders"`
+	// ConsoleAppender if true, the program will output to the console.
+	ConsoleAppender ConsoleAppender `json:"console_appender" yaml:"console_appender" mapstructure:"console_appender"`
+	// SyslogAppenders are the syslog servers to send the logs to.
+	SyslogAppenders []SyslogAppender `json:"syslog_appenders" yaml:"syslog_appenders" mapstructure:"syslog_appenders"`
+	// BodyDump is the debug-only opt-in to log the http bodies exchanged with space-track.
+	BodyDump spacetrack.BodyDump `json:"body_dump" yaml:"body_dump" mapstructure:"body_dump"`
+}
+
+// NewLogger returns a new Logger with a logger level and some files
+func NewLogger(console bool, loggerLevel LoggerLevel, loggerFileNames ...string) Logger {
+	var (
+		appenders       = make([]FileLoggerAppender, len(loggerFileNames))
+		consoleAppender ConsoleAppender
+	)
+
+	for i := range loggerFileNames {
+		appenders[i] = NewFileLoggerAppender(loggerLevel, loggerFileNames[i], RFC3339)
+	}
+
+	if console {
+		consoleAppender = NewConsoleAppender(loggerLevel)
+	}
+
+	return Logger{
+		Production:      false,
+		FileAppenders:   appenders,
+		ConsoleAppender: consoleAppender,
+	}
+}
+
+// Tee create core loggers to log into them. Sensitive fields are always masked, see spacetrack.NewRedactCore.
+// It returns an error wrapping ErrLoggerAppender if some appender can't be opened. The files and connections of the
+// appenders are kept open, see Configure, which closes them when the logger is replaced.
+func (l Logger) Tee() (*zap.Logger, error) {
+	zl, _, err := l.open()
+	return zl, err
+}
+
+// ... open builds the logger like Tee, returning the sinks of its appenders, which are closed if some of them fails.
+func (l Logger) open() (*zap.Logger, sinks, error) {
+	var (
+		cfg    zapcore.EncoderConfig
+		opened sinks
+		cores  = make([]zapcore.Core, 0, len(l.FileAppenders)+len(l.SyslogAppenders)+1)
+	)
+
+	if l.Production {
+		cfg = zap.NewProductionEncoderConfig()
+	} else {
+		cfg = zap.NewDevelopmentEncoderConfig()
+	}
+
+	appenders := make([]Appender, 0, cap(cores))
+	for i := range l.FileAppenders {
+		appenders = append(appenders, l.FileAppenders[i])
+	}
+	for i := range l.SyslogAppenders {
+		appenders = append(appenders, l.SyslogAppenders[i])
+	}
+	appenders = append(appenders, l.ConsoleAppender)
+
+	for _, a := range appenders {
+		core, sink, err := a.core(cfg)
+		if err != nil {
+			opened.Close() //nolint:errcheck
+			return nil, nil, err
+		}
+
+		cores = append(cores, core)
+		if sink != nil {
+			opened = append(opened, sink)
+		}
+	}
+
+	return zap.New(spacetrack.NewRedactCore(zapcore.NewTee(cores...)), zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)), opened, nil
+}
+
+// ... sinks are the files and connections opened by the appenders of a logger.
+type sinks []io.Closer
+
+// Close closes every sink, returning the first error.
+func (s sinks) Close() error {
+	var first error
+
+	for _, c := range s {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// Appender describes a standard appender to the zap logger.
+type Appender interface {
+	// ... implements all the logic which can help us to create the zap logger, returning what must be closed when
+	// the logger is replaced, if anything.
+	core(zapcore.EncoderConfig) (zapcore.Core, io.Closer, error)
+}
+
+// ConsoleAppender is the struct that allows to add a console appender to the zap logger
+type ConsoleAppender struct {
+	LoggerFileLevel LoggerLevel    `json:"level" yaml:"level" mapstructure:"level"`
+	DateTimeFormat  DateTimeFormat `json:"date_format" yaml:"date_format" mapstructure:"date_format"`
+	// Target is where the logs are written: stdout or stderr. If empty, stdout is used.
+	Target ConsoleTarget `json:"target" yaml:"target" mapstructure:"target"`
+	// Encoding of the logs: console or json, e.g. for docker log drivers. If empty, console is used.
+	Encoding LoggerEncoding `json:"encoding" yaml:"encoding" mapstructure:"encoding"`
+}
+
+// NewConsoleAppender returns a ConsoleAppender with logger level specified
+func NewConsoleAppender(loggerLevel LoggerLevel) ConsoleAppender {
+	return ConsoleAppender{
+		LoggerFileLevel: loggerLevel,
+		DateTimeFormat:  RFC3339,
+		Target:          Stdout,
+		Encoding:        ConsoleEncoding,
+	}
+}
+
+func (ca ConsoleAppender) core(config zapcore.EncoderConfig) (zapcore.Core, io.Closer, error) {
+	config.EncodeTime = ca.DateTimeFormat.ToZapTimeEncoder()
+	return zapcore.NewCore(ca.Encoding.encoder(config), ca.Target.writer(), ca.LoggerFileLevel.ToZapLevel()), nil, nil
+}
+
+// ConsoleTarget is the standard stream where the console appender writes.
+type ConsoleTarget string
+
+const (
+	// Stdout is the standard output, the default target.
+	Stdout = "stdout"
+	// Stderr is the standard error.
+	Stderr = "stderr"
+)
+
+// ConsoleTargetValues are the targets allowed in the configuration.
+var ConsoleTargetValues = []string{Stdout, Stderr}
+
+// Set tries to set the ConsoleTarget returning error if the input is incorrect
+func (ct *ConsoleTarget) Set(input string) error {
+	switch strings.ToLower(input) {
+	case Stdout:
+		*ct = Stdout
+	case Stderr:
+		*ct = Stderr
+	default:
+		return ErrConsoleTargetNotAllowed
+	}
+	return nil
+}
+
+// ... writer returns the stream of the target, resolved when the logger is built.
+func (ct ConsoleTarget) writer() zapcore.WriteSyncer {
+	if ct == Stderr {
+		return zapcore.Lock(os.Stderr)
+	}
+	return zapcore.Lock(os.Stdout)
+}
+
+// LoggerEncoding is how each log entry is encoded.
+type LoggerEncoding string
+
+const (
+	// ConsoleEncoding is the human readable encoding, the default one.
+	ConsoleEncoding = "console"
+	// JsonEncoding encodes each entry as a json object in one line.
+	JsonEncoding = "json"
+)
+
+// LoggerEncodingValues are the encodings allowed in the configuration.
+var LoggerEncodingValues = []string{ConsoleEncoding, JsonEncoding}
+
+// Set tries to set the LoggerEncoding returning error if the input is incorrect
+func (le *LoggerEncoding) Set(input string) error {
+	switch strings.ToLower(input) {
+	case ConsoleEncoding:
+		*le = ConsoleEncoding
+	case JsonEncoding:
+		*le = JsonEncoding
+	default:
+		return ErrLoggerEncodingNotAllowed
+	}
+	return nil
+}
+
+// Type returns the type of the LoggerEncoding, so it can be used as a flag.
+func (le *LoggerEncoding) Type() string {
+	return "string"
+}
+
+// String is the string representation of the LoggerEncoding
+func (le *LoggerEncoding) String() string {
+	return string(*le)
+}
+
+func (le LoggerEncoding) encoder(config zapcore.EncoderConfig) zapcore.Encoder {
+	if le == JsonEncoding {
+		return zapcore.NewJSONEncoder(config)
+	}
+	return zapcore.NewConsoleEncoder(config)
+}
+
+// FileLoggerAppender is the struct that allows to add a file appender
+// to the zap logger
+type FileLoggerAppender struct {
+	LoggerFileLevel LoggerLevel    `json:"level" yaml:"level" mapstructure:"level"`
+	LoggerFileName  string         `json:"file" yaml:"file" mapstructure:"file"`
+	DateTimeFormat  DateTimeFormat `json:"date_format" yaml:"date_format" mapstructure:"date_format"`
+	// Rotation of the file. If empty, the file grows forever.
+	Rotation Rotation `json:"rotation" yaml:"rotation" mapstructure:"rotation"`
+}
+
+// Rotation rotates the log file when it reaches MaxSize megabytes, and every Interval if set, removing the rotated files
+// older than MaxAge days or beyond the MaxBackups newest ones. Zero values of MaxAge and MaxBackups keep all the rotated
+// files.
+type Rotation struct {
+	// MaxSize is the size in megabytes of the file before it gets rotated. If zero, 100 megabytes is used.
+	MaxSize int `json:"max_size" yaml:"max_size" mapstructure:"max_size"`
+	// MaxAge is the maximum number of days to keep the rotated files.
+	MaxAge int `json:"max_age" yaml:"max_age" mapstructure:"max_age"`
+	// MaxBackups is the maximum number of rotated files to keep.
+	MaxBackups int `json:"max_backups" yaml:"max_backups" mapstructure:"max_backups"`
+	// Compress the rotated files using gzip.
+	Compress bool `json:"compress" yaml:"compress" mapstructure:"compress"`
+	// Interval is the time between rotations, e.g. 24h, whatever the size of the file. If empty, the file is only
+	// rotated by its size.
+	Interval string `json:"interval" yaml:"interval" mapstructure:"interval"`
+}
+
+// ... interval returns the time between rotations, which has already been validated, or zero if it isn't set.
+func (r Rotation) interval() time.Duration {
+	d, _ := time.ParseDuration(r.Interval) //nolint:errcheck
+	return d
+}
+
+// ... enabled is true when some of the fields is set, so configurations without rotation keep appending to the file.
+func (r Rotation) enabled() bool {
+	return r != Rotation{}
+}
+
+// NewFileLoggerAppender returns a FileLoggerAppender with values passed as parameters
+func NewFileLoggerAppender(loggerLevel LoggerLevel, fileName string, dateTimeFormat DateTimeFormat) FileLoggerAppender {
+	return FileLoggerAppender{
+		LoggerFileLevel: loggerLevel,
+		LoggerFileName:  fileName,
+		DateTimeFormat:  dateTimeFormat,
+	}
+}
+
+func (fla FileLoggerAppender) core(config zapcore.EncoderConfig) (zapcore.Core, io.Closer, error) {
+	sink, err := fla.sink()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: file %s: %v", ErrLoggerAppender, fla.LoggerFileName, err)
+	}
+
+	config.EncodeTime = fla.DateTimeFormat.ToZapTimeEncoder()
+	return zapcore.NewCore(zapcore.NewJSONEncoder(config), sink, fla.LoggerFileLevel.ToZapLevel()), sink, nil
+}
+
+// ... sink opens the file, rotating it with lumberjack if the rotation is enabled.
+func (fla FileLoggerAppender) sink() (*fileSink, error) {
+	if !fla.Rotation.enabled() {
+		logfile, err := os.OpenFile(fla.LoggerFileName, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0660)
+		if err != nil {
+			return nil, err
+		}
+		return &fileSink{w: logfile}, nil
+	}
+
+	lj := &lumberjack.Logger{
+		Filename:   fla.LoggerFileName,
+		MaxSize:    fla.Rotation.MaxSize,
+		MaxAge:     fla.Rotation.MaxAge,
+		MaxBackups: fla.Rotation.MaxBackups,
+		Compress:   fla.Rotation.Compress,
+	}
+
+	// ... lumberjack opens the file lazily, so we write nothing to find out now if it can be opened.
+	if _, err := lj.Write(nil); err != nil {
+		return nil, err
+	}
+
+	sink := &fileSink{w: lj}
+	if d := fla.Rotation.interval(); d > 0 {
+		sink.rotateEvery(lj, d)
+	}
+
+	return sink, nil
+}
+
+// ... fileSink is the file of a file appender, which drops the writes once it is closed, so a logger replaced while
+// some goroutine is still logging with it never opens the file again.
+type fileSink struct {
+	mu     sync.Mutex
+	w      io.WriteCloser
+	closed bool
+	// ... done stops the rotations by interval, if any.
+	done chan struct{}
+}
+
+func (fs *fileSink) Write(p []byte) (int, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.closed {
+		return 0, os.ErrClosed
+	}
+
+	return fs.w.Write(p)
+}
+
+func (fs *fileSink) Sync() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if f, ok := fs.w.(*os.File); ok && !fs.closed {
+		return f.Sync()
+	}
+
+	return nil
+}
+
+func (fs *fileSink) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.closed {
+		return nil
+	}
+
+	fs.closed = true
+	if fs.done != nil {
+		close(fs.done)
+	}
+
+	return fs.w.Close()
+}
+
+// ... rotateEvery rotates the file every interval until the sink is closed. Rotation errors, like a full disk, are
+// left to the next writes.
+func (fs *fileSink) rotateEvery(lj *lumberjack.Logger, d time.Duration) {
+	fs.done = make(chan struct{})
+
+	go func(done <-chan struct{}) {
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fs.mu.Lock()
+				if !fs.closed {
+					lj.Rotate() //nolint:errcheck
+				}
+				fs.mu.Unlock()
+			}
+		}
+	}(fs.done)
+}
+
+// DateTimeFormat is just a string type, that contains all the date time formats allowed by zap library.
+type DateTimeFormat string
+
+const (
+	ANSIC       = "ansic"
+	UnixDate    = "unixDate"
+	RubyDate    = "rubyDate"
+	RFC822      = "rfc822"
+	RFC822Z     = "rfc822z"
+	RFC850      = "rfc850"
+	RFC1123     = "rfc1123"
+	RFC1123Z    = "rfc1123z"
+	RFC3339     = "rfc3339"
+	RFC3339Nano = "rfc3339nano"
+	Kitchen     = "kitchen"
+	Stamp       = "stamp"
+	StampMilli  = "stampMilli"
+	StampMicro  = "stampMicro"
+	StampNano   = "stampNano"
+)
+
+// Type give us the type of DateTimeFormat. It is useful when dealing with flags in go.
+func (d DateTimeFormat) Type() string {
+	return "string"
+}
+
+// Set tries to set the value checking if the input is correct and returning error ErrDateTimeFormatNotAllowed otherwise.
+func (d *DateTimeFormat) Set(input string) error {
+	if !d.unmarshal(strings.ToLower(input)) {
+		return ErrDateTimeFormatNotAllowed
+	}
+	return nil
+}
+
+// nolint:cyclop
+func (d *DateTimeFormat) unmarshal(input string) bool {
+	switch input {
+	case "ansic":
+		*d = ANSIC
+	case "unixdate":
+		*d = UnixDate
+	case "rubydate":
+		*d = RubyDate
+	case "rfc822":
+		*d = RFC822
+	case "rfc822z":
+		*d = RFC822Z
+	case "rfc850":
+		*d = RFC850
+	case "rfc1123":
+		*d = RFC1123
+	case "rfc3339":
+		*d = RFC3339
+	case "rfc3339nano":
+		*d = RFC3339Nano
+	case "kitchen":
+		*d = Kitchen
+	case "stamp":
+		*d = Stamp
+	case "stampmilli":
+		*d = StampMilli
+	case "stampmicro":
+		*d = StampMicro
+	case "stampnano":
+		*d = StampNano
+	default:
+		return false
+	}
+
+	return true
+}
+
+// nolint:cyclop
+// String is the string representation of the date time format.
+func (d *DateTimeFormat) String() string {
+	var result = ""
+
+	switch *d {
+	case ANSIC:
+		result = time.ANSIC
+	case UnixDate:
+		result = time.UnixDate
+	case RubyDate:
+		result = time.RubyDate
+	case RFC822:
+		result = time.RFC822
+	case RFC822Z:
+		result = time.RFC822Z
+	case RFC850:
+		result = time.RFC850
+	case RFC1123:
+		result = time.RFC1123
+	case RFC3339:
+		result = time.RFC3339
+	case RFC3339Nano:
+		result = time.RFC3339Nano
+	case Kitchen:
+		result = time.Kitchen
+	case Stamp:
+		result = time.Stamp
+	case StampMilli:
+		result = time.StampMilli
+	case StampMicro:
+		result = time.StampMicro
+	case StampNano:
+		result = time.StampNano
+	}
+
+	return result
+}
+
+// ToZapTimeEncoder returns the zapcore.TimeEncoder so we can use it to set it into the zap.logger configuration
+func (d *DateTimeFormat) ToZapTimeEncoder() zapcore.TimeEncoder {
+	return zapcore.TimeEncoderOfLayout(d.String())
+}
+
+// LoggerLevel is just a wrapper of the zapcore.Level type of zap library which we
+// are going to use with cobra and viper
+type LoggerLevel string
+
+const (
+	// DebugLevel just for development.
+	DebugLevel = "debug"
+	// InfoLevel is the default logging priority.
+	InfoLevel = "info"
+	// WarnLevel is more important logs, but don't require human review.
+	WarnLevel = "warn"
+	// ErrorLevel logs of high-priority.
+	ErrorLevel = "error"
+	// DPanicLevel logs are particularly important errors.
+	DPanicLevel = "dpanic"
+	// PanicLevel logs a message, then panics.
+	PanicLevel = "panic"
+	// FatalLevel logs a message, then calls os.Exit(1)
+	FatalLevel = "fatal"
+)
+
+// Type returns the type of the LoggerLevel type
+func (l *LoggerLevel) Type() string {
+	return "string"
+}
+
+// Set tries to set the LoggerLevel returning error if the input is incorrect
+func (l *LoggerLevel) Set(input string) error {
+	if !l.unmarshal(strings.ToLower(input)) {
+		return ErrLoggerLevelNotAllowed
+	}
+	return nil
+}
+
+func (l *LoggerLevel) unmarshal(input string) bool {
+	switch input {
+	case "debug":
+		*l = DebugLevel
+	case "info":
+		*l = InfoLevel
+	case "warn":
+		*l = WarnLevel
+	case "error":
+		*l = ErrorLevel
+	case "dpanic":
+		*l = DPanicLevel
+	case "panic":
+		*l = PanicLevel
+	case "fatal":
+		*l = FatalLevel
+	default:
+		return false
+	}
+	return true
+}
+
+// String is the string representation of the LoggerLevel
+func (l *LoggerLevel) String() string {
+	var result = ""
+
+	switch *l {
+	case DebugLevel:
+		result = "debug"
+	case InfoLevel:
+		result = "info"
+	case WarnLevel:
+		result = "warn"
+	case ErrorLevel:
+		result = "error"
+	case DPanicLevel:
+		result = "dpanic"
+	case PanicLevel:
+		result = "panic"
+	case FatalLevel:
+		result = "fatal"
+	}
+
+	return result
+}
+
+// ToZapLevel is used to parse our custom LoggerLevel to the zapcore.Level
+// so we can use it to our zap.Logger configuration
+func (l *LoggerLevel) ToZapLevel() zapcore.Level {
+	z, _ := zapcore.ParseLevel(l.String()) //nolint:errcheck
+	return z
+}
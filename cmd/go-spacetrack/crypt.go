@@ -87,16 +87,23 @@ func ReadOnlyFilePassphrase(filename string) (string, error) {
 	return string(b), nil
 }
 
-// WritePassphraseToFile will write the passphrase to the file
-func WritePassphraseToFile(filename string, passphrase []byte) error {
+// WritePassphraseToFile will write the passphrase to the file, readable only by its owner, failing if the file already
+// exists unless overwrite is set
+func WritePassphraseToFile(filename string, passphrase []byte, overwrite bool) error {
 	var (
-		f   *os.File
-		err error
+		f    *os.File
+		err  error
+		flag = os.O_WRONLY | os.O_CREATE | os.O_EXCL
 	)
 
-	if f, err = os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0664); err != nil {
+	if overwrite {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	if f, err = os.OpenFile(filename, flag, 0600); err != nil {
 		return err
 	}
+	defer f.Close()
 
 	_, err = f.Write(passphrase)
 
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// ... daemon executes each job every its interval until the process is interrupted, reloading the configuration
+// when the config file changes or the process receives SIGHUP. Jobs are restarted after each reload.
+func (a *app) daemon(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	reloaded := a.watchConfig(ctx)
+
+	for {
+		var (
+			wg                sync.WaitGroup
+			jobsCtx, stopJobs = context.WithCancel(ctx)
+		)
+
+		for _, job := range a.currentJobs() {
+			wg.Add(1)
+			go func(job Job) {
+				defer wg.Done()
+				a.schedule(ctx, jobsCtx, job)
+			}(job)
+		}
+
+		select {
+		case <-ctx.Done():
+			stopJobs()
+			wg.Wait()
+			Info("stopping daemon")
+			return nil
+		case <-reloaded:
+			stopJobs()
+			wg.Wait()
+			Info("restarting jobs with the new configuration")
+		}
+	}
+}
+
+// ... schedule executes the job every its interval until jobsCtx is done. An execution which has already started
+// is not canceled by jobsCtx, but only by ctx, so reloads wait for it instead of leaving half written folders.
+func (a *app) schedule(ctx, jobsCtx context.Context, job Job) {
+	Info("scheduling job", zap.String("job", job.Name), zap.Duration("interval", job.interval()))
+
+	for {
+		if last, ok := a.lastRuns.Load(job.Name); ok {
+			timer := time.NewTimer(time.Until(last.(time.Time).Add(job.interval())))
+
+			select {
+			case <-jobsCtx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		a.lastRuns.Store(job.Name, time.Now())
+		a.runJobLocked(ctx, job)
+
+		if jobsCtx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (a *app) runJobLocked(ctx context.Context, job Job) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if err := a.runJob(ctx, job); err != nil {
+		Warn("space-track job", zap.Error(err))
+	}
+}
+
+// ... currentJobs are the jobs of the current configuration, which has already been validated.
+func (a *app) currentJobs() []Job {
+	return a.config().jobs()
+}
+
+// ... watchConfig reloads the configuration when the config file changes or the process receives SIGHUP, notifying
+// through the returned channel each time a new configuration is applied. Both triggers are handled by the same
+// goroutine, so viper is never read concurrently.
+func (a *app) watchConfig(ctx context.Context) <-chan struct{} {
+	var (
+		reloaded = make(chan struct{}, 1)
+		hup      = make(chan os.Signal, 1)
+		changes  = a.watchConfigFile(ctx)
+	)
+
+	notify := func() {
+		select {
+		case reloaded <- struct{}{}:
+		default:
+		}
+	}
+
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				Info("received SIGHUP, reloading configuration")
+				if err := a.readAndReloadConfig(); err == nil {
+					notify()
+				}
+			case <-changes:
+				if err := a.readAndReloadConfig(); err == nil {
+					notify()
+				}
+			}
+		}
+	}()
+
+	return reloaded
+}
+
+// ... watchConfigFile notifies through the returned channel each time the config file is written or replaced, like
+// viper.WatchConfig, whose goroutine reads the file by itself. Its folder is watched, so the file is followed when
+// it is replaced by a rename or a symlink swap, e.g. kubernetes config maps. Without a config file, nothing is notified.
+func (a *app) watchConfigFile(ctx context.Context) <-chan struct{} {
+	var (
+		changes = make(chan struct{}, 1)
+		file    = filepath.Clean(a.v.ConfigFileUsed())
+	)
+
+	if a.v.ConfigFileUsed() == "" {
+		return changes
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err == nil {
+		err = w.Add(filepath.Dir(file))
+	}
+
+	if err != nil {
+		Warn("watching config file, it is only reloaded on SIGHUP", zap.String("config_file", file), zap.Error(err))
+		if w != nil {
+			w.Close()
+		}
+		return changes
+	}
+
+	realFile, _ := filepath.EvalSymlinks(file)
+
+	go func() {
+		defer w.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-w.Events:
+				if !ok {
+					return
+				}
+
+				current, _ := filepath.EvalSymlinks(file)
+				written := filepath.Clean(e.Name) == file && e.Op&(fsnotify.Write|fsnotify.Create) != 0
+
+				if written || (current != "" && current != realFile) {
+					realFile = current
+					select {
+					case changes <- struct{}{}:
+					default:
+					}
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				Warn("watching config file", zap.Error(err))
+			}
+		}
+	}()
+
+	return changes
+}
+
+func (a *app) readAndReloadConfig() error {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	if a.v.ConfigFileUsed() != "" {
+		if err := a.v.ReadInConfig(); err != nil {
+			Warn("rejecting configuration reload, keeping the previous one", zap.Error(err))
+			return err
+		}
+	}
+
+	return a.reloadConfig()
+}
+
+// ... reloadConfig validates the configuration known by viper and, only if it is valid, swaps the configuration of the app,
+// the logger and the client. The session and the rate limiter are kept, unless the credentials or the rate limit have changed.
+// It must be called holding reloadMu.
+func (a *app) reloadConfig() error {
+	var credentials spacetrack.Credentials
+
+	c, err := decodeConfig(a.v)
+	if err == nil {
+		err = c.Validate()
+	}
+
+	if err == nil {
+		credentials, err = c.Auth.Credentials()
+	}
+
+	if err == nil {
+		err = Configure(c.Logger)
+	}
+
+	if err != nil {
+		Warn("rejecting configuration reload, keeping the previous one", zap.Error(err))
+		return err
+	}
+
+	// ... a.cfg is only written by reloads, which are serialized, so it can be read without the lock here.
+	if c.HTTP != a.cfg.HTTP || c.Record != a.cfg.Record || c.Replay != a.cfg.Replay {
+		Warn("http, record and replay changes are applied on restart, keeping the previous ones")
+	}
+
+	a.mu.Lock()
+	a.cfg = c
+	a.configureClient(c, credentials)
+	a.mu.Unlock()
+
+	Info("configuration reloaded", zap.String("config_file", a.v.ConfigFileUsed()))
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+const validConfigContent = "auth:\n  identity: identity\n  password: password\nwork_dir: /tmp/spacetrack\ninterval: 1h\nformat: xml\n"
+
+// ... newTestConfigApp returns an app configured like the root command one, which has already read the content passed.
+// Its client is the fake one of newTestApp.
+func newTestConfigApp(t *testing.T, content string) (*app, string) {
+	f := createFile(t, t.TempDir(), "spacetrack.yml", 0666)
+	writeContent(t, f.Name(), content)
+
+	a, _ := newTestApp(http.StatusOK, `""`)
+	if err := bindConfig(a.v, a.rootCmd().PersistentFlags()); err != nil {
+		t.Fatal(err)
+	}
+
+	a.configFile = f.Name()
+	if err := a.readConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	return a, f.Name()
+}
+
+func writeContent(t *testing.T, fileName, content string) {
+	if err := os.WriteFile(fileName, []byte(content), 0666); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitReload(t *testing.T, reloaded <-chan struct{}) {
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	t.Run("valid configuration is applied keeping the session", func(t *testing.T) {
+		a, fileName := newTestConfigApp(t, validConfigContent)
+		a.configureClient(a.cfg, spacetrack.Credentials{Identity: "identity", Password: "password"})
+		assert.Nil(t, a.client.Login(context.Background()))
+
+		writeContent(t, fileName, validConfigContent+"rest_call: tle\n")
+
+		assert.Nil(t, a.readAndReloadConfig())
+		assert.Equal(t, spacetrack.Tle, a.config().RestCall)
+		assert.Nil(t, a.client.CheckSession())
+	})
+
+	t.Run("session is dropped when credentials change", func(t *testing.T) {
+		a, fileName := newTestConfigApp(t, validConfigContent)
+		a.configureClient(a.cfg, spacetrack.Credentials{Identity: "identity", Password: "password"})
+		assert.Nil(t, a.client.Login(context.Background()))
+
+		writeContent(t, fileName, "auth:\n  identity: another\n  password: password\nwork_dir: /tmp/spacetrack\ninterval: 1h\n")
+
+		assert.Nil(t, a.readAndReloadConfig())
+		assert.Equal(t, "another", a.config().Auth.Identity)
+		assert.ErrorIs(t, a.client.CheckSession(), spacetrack.ErrNotAuthenticated)
+	})
+
+	t.Run("invalid configuration is rejected keeping the previous one", func(t *testing.T) {
+		a, fileName := newTestConfigApp(t, validConfigContent)
+		old := a.config()
+
+		writeContent(t, fileName, strings.Replace(validConfigContent, "interval: 1h", "interval: never", 1))
+
+		assert.ErrorIs(t, a.readAndReloadConfig(), ErrInvalidConfig)
+		assert.Equal(t, old, a.config())
+	})
+
+	t.Run("malformed config file is rejected keeping the previous one", func(t *testing.T) {
+		a, fileName := newTestConfigApp(t, validConfigContent)
+		old := a.config()
+
+		writeContent(t, fileName, "auth: [")
+
+		assert.NotNil(t, a.readAndReloadConfig())
+		assert.Equal(t, old, a.config())
+	})
+}
+
+func TestWatchConfig(t *testing.T) {
+	t.Run("reload when the config file changes", func(t *testing.T) {
+		a, fileName := newTestConfigApp(t, validConfigContent)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		reloaded := a.watchConfig(ctx)
+		writeContent(t, fileName, strings.Replace(validConfigContent, "interval: 1h", "interval: 2h", 1))
+		waitReload(t, reloaded)
+
+		assert.Equal(t, 2*time.Hour, a.currentJobs()[0].interval())
+	})
+
+	t.Run("reload on SIGHUP", func(t *testing.T) {
+		a, _ := newTestConfigApp(t, validConfigContent)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		reloaded := a.watchConfig(ctx)
+		t.Setenv("SPACETRACK_INTERVAL", "3h")
+		if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+			t.Fatal(err)
+		}
+		waitReload(t, reloaded)
+
+		assert.Equal(t, 3*time.Hour, a.currentJobs()[0].interval())
+	})
+	t.Run("reload on file change and SIGHUP at the same time", func(t *testing.T) {
+		a, fileName := newTestConfigApp(t, validConfigContent)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		reloaded := a.watchConfig(ctx)
+		for i := 0; i < 5; i++ {
+			writeContent(t, fileName, strings.Replace(validConfigContent, "interval: 1h", "interval: 4h", 1))
+			if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+				t.Fatal(err)
+			}
+		}
+		waitReload(t, reloaded)
+
+		assert.Eventually(t, func() bool {
+			return a.currentJobs()[0].interval() == 4*time.Hour
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
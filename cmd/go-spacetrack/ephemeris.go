@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/MrTimeout/go-spacetrack/spacetrack/sgp4"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidEphemeris is returned when the parameters of the propagation are wrong, e.g. the stop is before the start.
+	ErrInvalidEphemeris = errors.New("invalid ephemeris")
+	// ErrOEMInput is returned when marshalling as an OEM anything but an Ephemeris.
+	ErrOEMInput = errors.New("only ephemerides can be encoded as oem")
+)
+
+// ... layout of the times of the ephemerides, in UTC, like the EPOCH of space-track.
+const ephemerisTimeLayout = "2006-01-02T15:04:05.000000"
+
+// Ephemeris are the states of an object propagated from one of its element sets, see sgp4.
+type Ephemeris struct {
+	XMLName xml.Name         `json:"-" xml:"spacetrack-ephemeris"`
+	Points  []EphemerisPoint `json:"item" xml:"item" html:"item"`
+}
+
+// EphemerisPoint is the state of an object at a time, with its position, in km, and velocity, in km/s, in the TEME
+// frame, and its geodetic coordinates, in degrees and km over the WGS84 ellipsoid.
+type EphemerisPoint struct {
+	XMLName    xml.Name `json:"-" xml:"item" csv:"-"`
+	NoradCatID string   `json:"NORAD_CAT_ID" xml:"NORAD_CAT_ID" csv:"NORAD_CAT_ID" html:"l=NORAD_CAT_ID,e=span"`
+	ObjectName string   `json:"OBJECT_NAME" xml:"OBJECT_NAME" csv:"OBJECT_NAME" html:"l=OBJECT_NAME,e=span"`
+	ObjectID   string   `json:"OBJECT_ID" xml:"OBJECT_ID" csv:"OBJECT_ID" html:"l=OBJECT_ID,e=span"`
+	Epoch      string   `json:"EPOCH" xml:"EPOCH" csv:"EPOCH" html:"l=EPOCH,e=span"`
+	X          float64  `json:"X" xml:"X" csv:"X" html:"l=X,e=span"`
+	Y          float64  `json:"Y" xml:"Y" csv:"Y" html:"l=Y,e=span"`
+	Z          float64  `json:"Z" xml:"Z" csv:"Z" html:"l=Z,e=span"`
+	XDot       float64  `json:"X_DOT" xml:"X_DOT" csv:"X_DOT" html:"l=X_DOT,e=span"`
+	YDot       float64  `json:"Y_DOT" xml:"Y_DOT" csv:"Y_DOT" html:"l=Y_DOT,e=span"`
+	ZDot       float64  `json:"Z_DOT" xml:"Z_DOT" csv:"Z_DOT" html:"l=Z_DOT,e=span"`
+	Latitude   float64  `json:"LATITUDE" xml:"LATITUDE" csv:"LATITUDE" html:"l=LATITUDE,e=span"`
+	Longitude  float64  `json:"LONGITUDE" xml:"LONGITUDE" csv:"LONGITUDE" html:"l=LONGITUDE,e=span"`
+	Altitude   float64  `json:"ALTITUDE" xml:"ALTITUDE" csv:"ALTITUDE" html:"l=ALTITUDE,e=span"`
+}
+
+// Propagation are the parameters of the ephemerides of the propagate command.
+type Propagation struct {
+	// NoradCatIDs are the objects propagated, or all of them if empty.
+	NoradCatIDs []string
+	// Start and Stop are the range of time of the ephemerides, both included.
+	Start, Stop time.Time
+	// Step is the time between the points of the ephemerides.
+	Step time.Duration
+}
+
+func (p Propagation) validate() error {
+	if !p.Stop.After(p.Start) {
+		return fmt.Errorf("%w: stop %s is not after start %s", ErrInvalidEphemeris, p.Stop, p.Start)
+	}
+	if p.Step <= 0 {
+		return fmt.Errorf("%w: step %s is not positive", ErrInvalidEphemeris, p.Step)
+	}
+	return nil
+}
+
+// ... propagate writes the ephemeris of each object selected into the folder, as ephemeris-${norad_cat_id}, returning
+// the amount of ephemerides written. Objects which can't be propagated, e.g. they decay within the range, are logged
+// and the points until then are written.
+func propagate(ctx context.Context, tles []spacetrack.SpaceTrackTleUnit, p Propagation, w persist.Writer, folder string) (int, error) {
+	if err := p.validate(); err != nil {
+		return 0, err
+	}
+
+	units := selectObjects(tles, p.NoradCatIDs)
+	if len(units) == 0 {
+		return 0, fmt.Errorf("%w: none of the objects %v have element sets", ErrInvalidEphemeris, p.NoradCatIDs)
+	}
+
+	warnMissingObjects(units, p.NoradCatIDs)
+
+	var written int
+
+	for _, u := range units {
+		eph, err := ephemeris(u, p)
+		if err != nil {
+			L().Warn("propagating element set", zap.String("norad_cat_id", u.NoradCatId), zap.Error(err))
+			if len(eph.Points) == 0 {
+				continue
+			}
+		}
+
+		if err := w.Write(ctx, filepath.Join(folder, "ephemeris-"+u.NoradCatId), eph); err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	return written, nil
+}
+
+// ... ephemeris propagates the element set, returning the points until the propagation fails, if it does.
+func ephemeris(u spacetrack.SpaceTrackTleUnit, p Propagation) (Ephemeris, error) {
+	eph := Ephemeris{Points: []EphemerisPoint{}}
+
+	e, err := sgp4.FromUnit(u)
+	if err != nil {
+		return eph, err
+	}
+
+	s, err := sgp4.New(e)
+	if err != nil {
+		return eph, err
+	}
+
+	states, err := s.Ephemeris(p.Start, p.Stop, p.Step)
+
+	for _, st := range states {
+		g := st.Geodetic()
+
+		eph.Points = append(eph.Points, EphemerisPoint{
+			NoradCatID: u.NoradCatId,
+			ObjectName: u.ObjectName,
+			ObjectID:   u.ObjectId,
+			Epoch:      st.Time.UTC().Format(ephemerisTimeLayout),
+			X:          st.Position[0],
+			Y:          st.Position[1],
+			Z:          st.Position[2],
+			XDot:       st.Velocity[0],
+			YDot:       st.Velocity[1],
+			ZDot:       st.Velocity[2],
+			Latitude:   g.Latitude,
+			Longitude:  g.Longitude,
+			Altitude:   g.Altitude,
+		})
+	}
+
+	return eph, err
+}
+
+// ... selectObjects returns the element set with the newest epoch of each object passed, or of every object if none is
+// passed, sorted by NORAD_CAT_ID.
+func selectObjects(tles []spacetrack.SpaceTrackTleUnit, ids []string) []spacetrack.SpaceTrackTleUnit {
+	var (
+		wanted = make(map[string]bool, len(ids))
+		newest = make(map[string]spacetrack.SpaceTrackTleUnit)
+		output []spacetrack.SpaceTrackTleUnit
+	)
+
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	for _, u := range tles {
+		if len(wanted) > 0 && !wanted[u.NoradCatId] {
+			continue
+		}
+		if prev, ok := newest[u.NoradCatId]; !ok || u.Epoch > prev.Epoch {
+			newest[u.NoradCatId] = u
+		}
+	}
+
+	for _, u := range newest {
+		output = append(output, u)
+	}
+
+	sort.Slice(output, func(i, j int) bool {
+		return spacetrack.CompareCursors(output[i].NoradCatId, output[j].NoradCatId) < 0
+	})
+
+	return output
+}
+
+// ... warnMissingObjects logs the objects passed which have no element set among the selected ones, so they aren't
+// silently left out when others have.
+func warnMissingObjects(units []spacetrack.SpaceTrackTleUnit, ids []string) {
+	found := make(map[string]bool, len(units))
+
+	for _, u := range units {
+		found[u.NoradCatId] = true
+	}
+
+	for _, id := range ids {
+		if !found[id] {
+			L().Warn("object without element sets", zap.String("norad_cat_id", id))
+		}
+	}
+}
+
+// OEMMarshaller encodes an Ephemeris as a CCSDS Orbit Ephemeris Message (CCSDS 502.0-B-2) in keyword = value notation,
+// with one segment in the TEME frame.
+type OEMMarshaller struct {
+	// ... now returns the creation date of the messages, time.Now if nil.
+	now func() time.Time
+}
+
+func (m OEMMarshaller) Marshal(input any) ([]byte, error) {
+	eph, ok := input.(Ephemeris)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrOEMInput, input)
+	}
+
+	now := time.Now
+	if m.now != nil {
+		now = m.now
+	}
+
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "CCSDS_OEM_VERS = 2.0\n")
+	fmt.Fprintf(&b, "CREATION_DATE = %s\n", now().UTC().Format(ephemerisTimeLayout))
+	fmt.Fprintf(&b, "ORIGINATOR = go-spacetrack\n")
+
+	if len(eph.Points) == 0 {
+		return b.Bytes(), nil
+	}
+
+	var (
+		first = eph.Points[0]
+		last  = eph.Points[len(eph.Points)-1]
+	)
+
+	fmt.Fprintf(&b, "\nMETA_START\n")
+	fmt.Fprintf(&b, "OBJECT_NAME = %s\n", oemValue(first.ObjectName, first.NoradCatID))
+	fmt.Fprintf(&b, "OBJECT_ID = %s\n", oemValue(first.ObjectID, first.NoradCatID))
+	fmt.Fprintf(&b, "CENTER_NAME = EARTH\n")
+	fmt.Fprintf(&b, "REF_FRAME = TEME\n")
+	fmt.Fprintf(&b, "TIME_SYSTEM = UTC\n")
+	fmt.Fprintf(&b, "START_TIME = %s\n", first.Epoch)
+	fmt.Fprintf(&b, "STOP_TIME = %s\n", last.Epoch)
+	fmt.Fprintf(&b, "META_STOP\n\n")
+
+	for _, p := range eph.Points {
+		fmt.Fprintf(&b, "%s %.6f %.6f %.6f %.9f %.9f %.9f\n", p.Epoch, p.X, p.Y, p.Z, p.XDot, p.YDot, p.ZDot)
+	}
+
+	return b.Bytes(), nil
+}
+
+// ... oemValue returns the first of the values which isn't empty, or UNKNOWN if all of them are, as the mandatory
+// keywords of the metadata can't be blank.
+func oemValue(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return "UNKNOWN"
+}
+
+func (m OEMMarshaller) Ext() string {
+	return "oem"
+}
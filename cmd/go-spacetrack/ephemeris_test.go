@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/MrTimeout/go-spacetrack/spacetrack/sgp4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var (
+	vanguardTle = spacetrack.SpaceTrackTleUnit{
+		NoradCatId: "5",
+		ObjectName: "VANGUARD 1",
+		ObjectId:   "1958-002B",
+		Epoch:      "2000-06-27T18:50:19.733568",
+		TleLine1:   "1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753",
+		TleLine2:   "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667",
+	}
+	issTle = spacetrack.SpaceTrackTleUnit{
+		NoradCatId: "25544",
+		ObjectName: "ISS (ZARYA)",
+		ObjectId:   "1998-067A",
+		Epoch:      "2008-09-20T12:25:40.104192",
+		TleLine1:   "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+		TleLine2:   "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+	}
+	vanguardEpoch = time.Date(2000, 6, 27, 18, 50, 19, 733568000, time.UTC)
+)
+
+// ... recordingWriter keeps the content written by file, instead of writing it.
+type recordingWriter struct {
+	files map[string]any
+}
+
+func (w *recordingWriter) Write(_ context.Context, file string, input any) error {
+	if w.files == nil {
+		w.files = map[string]any{}
+	}
+	w.files[file] = input
+	return nil
+}
+
+func TestPropagate(t *testing.T) {
+	p := Propagation{Start: vanguardEpoch, Stop: vanguardEpoch.Add(720 * time.Minute), Step: 360 * time.Minute}
+
+	t.Run("ephemeris of each object selected", func(t *testing.T) {
+		var w recordingWriter
+
+		n, err := propagate(context.Background(), []spacetrack.SpaceTrackTleUnit{vanguardTle, issTle}, Propagation{NoradCatIDs: []string{"5"}, Start: p.Start, Stop: p.Stop, Step: p.Step}, &w, "/tmp/ephemeris")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+
+		eph, ok := w.files["/tmp/ephemeris/ephemeris-5"].(Ephemeris)
+		if !assert.True(t, ok) {
+			return
+		}
+
+		assert.Len(t, eph.Points, 3)
+		assert.Equal(t, "2000-06-28T06:50:19.733568", eph.Points[2].Epoch)
+		assert.InDelta(t, -7134.59340119, eph.Points[2].X, 1e-6)
+		assert.InDelta(t, -2.557327851, eph.Points[2].ZDot, 1e-9)
+		assert.Equal(t, "1958-002B", eph.Points[2].ObjectID)
+	})
+
+	t.Run("every object if none is selected", func(t *testing.T) {
+		var w recordingWriter
+
+		n, err := propagate(context.Background(), []spacetrack.SpaceTrackTleUnit{vanguardTle, issTle}, p, &w, "/tmp/ephemeris")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+		assert.Contains(t, w.files, "/tmp/ephemeris/ephemeris-25544")
+	})
+
+	t.Run("objects without element sets", func(t *testing.T) {
+		_, err := propagate(context.Background(), []spacetrack.SpaceTrackTleUnit{vanguardTle}, Propagation{NoradCatIDs: []string{"25544"}, Start: p.Start, Stop: p.Stop, Step: p.Step}, &recordingWriter{}, "/tmp/ephemeris")
+		assert.ErrorIs(t, err, ErrInvalidEphemeris)
+	})
+
+	t.Run("invalid element sets are skipped", func(t *testing.T) {
+		var (
+			w       recordingWriter
+			invalid = issTle
+		)
+		invalid.TleLine2 = "2 25544"
+
+		n, err := propagate(context.Background(), []spacetrack.SpaceTrackTleUnit{vanguardTle, invalid}, p, &w, "/tmp/ephemeris")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+	})
+}
+
+func TestPropagationValidate(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		p           Propagation
+		wantErr     bool
+	}{
+		{description: "valid propagation", p: Propagation{Start: vanguardEpoch, Stop: vanguardEpoch.Add(time.Hour), Step: time.Minute}},
+		{description: "stop before start", p: Propagation{Start: vanguardEpoch, Stop: vanguardEpoch.Add(-time.Hour), Step: time.Minute}, wantErr: true},
+		{description: "step not positive", p: Propagation{Start: vanguardEpoch, Stop: vanguardEpoch.Add(time.Hour)}, wantErr: true},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			if err := each.p.validate(); each.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidEphemeris)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestSelectObjects(t *testing.T) {
+	older := issTle
+	older.Epoch = "2008-09-19T00:00:00.000000"
+
+	got := selectObjects([]spacetrack.SpaceTrackTleUnit{issTle, older, vanguardTle}, nil)
+
+	assert.Equal(t, []spacetrack.SpaceTrackTleUnit{vanguardTle, issTle}, got, "the newest element set of each object, by NORAD_CAT_ID")
+}
+
+func TestWarnMissingObjects(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	SetLogger(zap.New(core))
+	defer SetLogger(nil)
+
+	warnMissingObjects([]spacetrack.SpaceTrackTleUnit{vanguardTle}, []string{"5", "25544", "99999"})
+
+	var missing []string
+	for _, entry := range logs.All() {
+		missing = append(missing, entry.ContextMap()["norad_cat_id"].(string))
+	}
+
+	assert.Equal(t, []string{"25544", "99999"}, missing, "the objects passed without element set")
+}
+
+func TestOEMMarshaller(t *testing.T) {
+	m := OEMMarshaller{now: func() time.Time { return time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC) }}
+
+	t.Run("one segment in the teme frame", func(t *testing.T) {
+		eph, err := ephemeris(vanguardTle, Propagation{Start: vanguardEpoch, Stop: vanguardEpoch.Add(360 * time.Minute), Step: 360 * time.Minute})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := m.Marshal(eph)
+
+		assert.Nil(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"CCSDS_OEM_VERS = 2.0",
+			"CREATION_DATE = 2023-01-01T00:00:00.000000",
+			"ORIGINATOR = go-spacetrack",
+			"",
+			"META_START",
+			"OBJECT_NAME = VANGUARD 1",
+			"OBJECT_ID = 1958-002B",
+			"CENTER_NAME = EARTH",
+			"REF_FRAME = TEME",
+			"TIME_SYSTEM = UTC",
+			"START_TIME = 2000-06-27T18:50:19.733568",
+			"STOP_TIME = 2000-06-28T00:50:19.733568",
+			"META_STOP",
+			"",
+			"2000-06-27T18:50:19.733568 7022.465293 -1400.082968 0.039952 1.893841015 6.405893759 4.534807250",
+			"2000-06-28T00:50:19.733568 -7154.031202 -3783.176825 -3536.194123 4.741887409 -4.151817765 -2.093935425",
+			"",
+		}, "\n"), string(b))
+	})
+
+	t.Run("empty names fall back to the norad cat id", func(t *testing.T) {
+		testCases := []struct {
+			description string
+			point       EphemerisPoint
+			expected    []string
+		}{
+			{
+				description: "object id and name missing",
+				point:       EphemerisPoint{NoradCatID: "5", Epoch: "2000-06-27T18:50:19.733568"},
+				expected:    []string{"OBJECT_NAME = 5", "OBJECT_ID = 5"},
+			},
+			{
+				description: "only the object id missing",
+				point:       EphemerisPoint{NoradCatID: "5", ObjectName: "VANGUARD 1", Epoch: "2000-06-27T18:50:19.733568"},
+				expected:    []string{"OBJECT_NAME = VANGUARD 1", "OBJECT_ID = 5"},
+			},
+			{
+				description: "nothing to identify the object",
+				point:       EphemerisPoint{Epoch: "2000-06-27T18:50:19.733568"},
+				expected:    []string{"OBJECT_NAME = UNKNOWN", "OBJECT_ID = UNKNOWN"},
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.description, func(t *testing.T) {
+				b, err := m.Marshal(Ephemeris{Points: []EphemerisPoint{tc.point}})
+
+				assert.Nil(t, err)
+				for _, line := range tc.expected {
+					assert.Contains(t, strings.Split(string(b), "\n"), line)
+				}
+			})
+		}
+	})
+
+	t.Run("only ephemerides", func(t *testing.T) {
+		_, err := m.Marshal(spacetrack.SpaceTrackTle{})
+		assert.ErrorIs(t, err, ErrOEMInput)
+	})
+}
+
+func TestEphemerisDecayed(t *testing.T) {
+	// ... the iss with a drag high enough to decay within a year.
+	dragged := spacetrack.SpaceTrackTleUnit{
+		NoradCatId: "25544", Epoch: issTle.Epoch, MeanMotion: "15.72125391", Eccentricity: "0.0006703", Inclination: "51.6416",
+		RaOfAscNode: "247.4627", ArgOfPericenter: "130.5360", MeanAnomaly: "325.0288", Bstar: "0.005", MeanMotionDot: "0", MeanMotionDdot: "0",
+	}
+
+	e, err := sgp4.FromUnit(dragged)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eph, err := ephemeris(dragged, Propagation{Start: e.Epoch, Stop: e.Epoch.AddDate(1, 0, 0), Step: 24 * time.Hour})
+
+	assert.NotNil(t, err)
+	assert.NotEmpty(t, eph.Points)
+	assert.Less(t, len(eph.Points), 366)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/internal/telemetry"
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// ErrUnknownRestCall is returned when fetching a rest call which is not tle, cdm or dec.
+var ErrUnknownRestCall = errors.New("unknown rest call")
+
+// ... fetchFunc requests the query, parsing the records as the model of its class.
+type fetchFunc func(context.Context, *spacetrack.Client, spacetrack.Query, fetchOptions) (fetched, error)
+
+// ... fetchOptions are how the records of a fetch are prepared to be persisted.
+type fetchOptions struct {
+	// ... oneE wraps all the records in one element, to be persisted in one file.
+	oneE bool
+	// ... digests of the previous fetch. If not nil, only the records added or updated since then are returned.
+	digests spacetrack.Digests
+	// ... removed reports the records of the previous fetch which are missing, see spacetrack.Changes.
+	removed bool
+}
+
+// ... fetched are the records of a fetch, split as they are going to be persisted, and their newest cursor. If they
+// are deduplicated, see fetchOptions.digests, their changes and digests too.
+type fetched struct {
+	records []any
+	cursor  string
+	changes spacetrack.Changeset
+	digests spacetrack.Digests
+}
+
+// ... restCalls maps each rest call to the fetch of its records.
+var restCalls = map[spacetrack.RestCall]fetchFunc{
+	spacetrack.Tle:   fetch[spacetrack.SpaceTrackTleUnit],
+	spacetrack.Cdm:   fetch[spacetrack.SpaceTrackCdmUnit],
+	spacetrack.Decay: fetch[spacetrack.SpaceTrackDecayUnit],
+}
+
+// ... name of the changeset of each execution of a job which deduplicates the records, without extension.
+const changesetFile = "changeset"
+
+// ... restCallDirs are the folders, under the work dir, where the records of each rest call are persisted.
+var restCallDirs = map[spacetrack.RestCall]string{
+	spacetrack.Tle:   "spacetrack-tle",
+	spacetrack.Cdm:   "spacetrack-cdm",
+	spacetrack.Decay: "spacetrack-dec",
+}
+
+// Fetcher fetches the rest calls of a job and persists their records. It doesn't keep any state of its own, the session
+// and the rate limiter live in the client and the high-water marks in the state file of the work dir, so it is safe for
+// concurrent use and several fetchers can share one client.
+type Fetcher struct {
+	job       Job
+	client    *spacetrack.Client
+	persister persist.Persister
+}
+
+// NewFetcher creates a fetcher of the job, which must be already filled with the top level fields, see Config.jobs,
+// requesting space-track with the client and persisting with the persister passed.
+func NewFetcher(job Job, client *spacetrack.Client, persister persist.Persister) *Fetcher {
+	return &Fetcher{job: job, client: client, persister: persister}
+}
+
+// ... newJobFetcher creates the fetcher of the job with the persister of its format and persister mod.
+func newJobFetcher(job Job, client *spacetrack.Client) (*Fetcher, error) {
+	persister, err := persist.GetPersister(job.Persister, job.Format, persist.WithLogger(L()), persist.WithMetrics(persistMetrics))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFetcher(job, client, persister), nil
+}
+
+// Fetch requests the rest call with the query of the job, persisting the records under a folder named after the unix
+// time of the run, e.g. ${work_dir}/spacetrack-tle/1672531200. The rest calls of one execution of a job share the run,
+// so all of them land in folders with the same name. If the job is incremental, only the records newer than the
+// high-water mark of the previous fetches are requested, and the mark is moved once they are persisted.
+func (f *Fetcher) Fetch(ctx context.Context, rc spacetrack.RestCall, run time.Time) (err error) {
+	fetch, ok := restCalls[rc]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownRestCall, rc)
+	}
+
+	ctx, span := telemetry.Start(ctx, "spacetrack.fetch", attribute.String("spacetrack.job", f.job.Name), attribute.String("spacetrack.rest_call", rc.String()))
+	defer telemetry.End(span, &err)
+
+	var mark string
+	if f.job.incremental {
+		if mark, err = highWaterMark(f.job.WorkDir, f.job.Name, rc); err != nil {
+			return err
+		}
+	}
+
+	opts := fetchOptions{oneE: f.job.Persister == persist.OneFile}
+	if f.job.dedup {
+		if opts.digests, err = readDigests(f.job.WorkDir, f.job.Name, rc); err != nil {
+			return err
+		}
+		// ... incremental fetches only have the records newer than the mark, so the missing ones haven't been removed.
+		opts.removed = mark == ""
+	}
+
+	result, err := fetch(ctx, f.client, rc.Query(f.job.incrementalQuery(rc, mark)), opts)
+	if err != nil {
+		return err
+	}
+
+	dir := f.dir(rc, run)
+
+	if !f.job.dedup || result.changes.Count(spacetrack.Added)+result.changes.Count(spacetrack.Updated) > 0 {
+		if err := f.persister.Persist(ctx, dir, result.records); err != nil {
+			return err
+		}
+	}
+
+	if f.job.dedup {
+		if err := f.persistChanges(ctx, dir, rc, result); err != nil {
+			return err
+		}
+	}
+
+	if !f.job.incremental || result.cursor == "" {
+		return nil
+	}
+
+	return saveHighWaterMark(f.job.WorkDir, f.job.Name, rc, result.cursor)
+}
+
+// ... persistChanges writes the changeset of the fetch in the folder of the execution, ${dir}/changeset.${format}, and
+// keeps the digests of its records to compare the next fetch with.
+func (f *Fetcher) persistChanges(ctx context.Context, dir string, rc spacetrack.RestCall, result fetched) error {
+	m, err := persist.GetMarshaller(f.job.Format)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	if err := persist.NewWriter(m, L()).Write(ctx, filepath.Join(dir, changesetFile), result.changes); err != nil {
+		return err
+	}
+
+	Info("changes since the previous fetch", zap.String("job", f.job.Name), zap.String("rest_call", rc.String()),
+		zap.Int(spacetrack.Added, result.changes.Count(spacetrack.Added)),
+		zap.Int(spacetrack.Updated, result.changes.Count(spacetrack.Updated)),
+		zap.Int(spacetrack.Removed, result.changes.Count(spacetrack.Removed)))
+
+	return writeDigests(f.job.WorkDir, f.job.Name, rc, result.digests)
+}
+
+// ... dir returns the folder of the rest call in the run.
+func (f *Fetcher) dir(rc spacetrack.RestCall, run time.Time) string {
+	return filepath.Join(f.job.WorkDir, restCallDirs[rc], strconv.FormatInt(run.Unix(), 10))
+}
+
+// ... fetch requests the query, parsing the records as T.
+func fetch[T spacetrack.Unit](ctx context.Context, client *spacetrack.Client, q spacetrack.Query, opts fetchOptions) (fetched, error) {
+	var f fetched
+
+	arr, err := spacetrack.Fetch[T](ctx, client, q)
+	if err != nil {
+		return f, err
+	}
+
+	f.cursor = spacetrack.LatestCursor(arr)
+
+	if opts.digests != nil {
+		arr, f.changes, f.digests = spacetrack.Changes(opts.digests, arr, opts.removed)
+	}
+
+	f.records = spacetrack.Split(spacetrack.Wrap(arr), opts.oneE)
+
+	return f, nil
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetcher(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		rc          spacetrack.RestCall
+		persister   persist.PersisterMod
+		body        string
+		files       int
+	}{
+		{
+			description: "tle persisted one file per row",
+			rc:          spacetrack.Tle,
+			persister:   persist.OneFilePerRow,
+			body:        `[{"NORAD_CAT_ID":"25544"},{"NORAD_CAT_ID":"48274"},{"NORAD_CAT_ID":"20580"}]`,
+			files:       3,
+		},
+		{
+			description: "decay persisted in one file",
+			rc:          spacetrack.Decay,
+			persister:   persist.OneFile,
+			body:        `[{"NORAD_CAT_ID":"25544"},{"NORAD_CAT_ID":"48274"}]`,
+			files:       1,
+		},
+		{
+			description: "cdm persisted one file per row",
+			rc:          spacetrack.Cdm,
+			persister:   persist.OneFilePerRow,
+			body:        `[{"CDM_ID":"1"},{"CDM_ID":"2"}]`,
+			files:       2,
+		},
+	} {
+		each := each
+
+		t.Run(each.description, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				dir        = t.TempDir()
+				a, logins  = newTestApp(http.StatusOK, each.body)
+				fetcher, _ = newJobFetcher(Job{Name: each.description, RestCall: each.rc, Format: persist.Json, Persister: each.persister, WorkDir: dir}, a.client)
+			)
+
+			run := time.Unix(1672531200, 0)
+
+			assert.Nil(t, fetcher.Fetch(context.Background(), each.rc, run))
+			assert.Equal(t, int32(1), *logins)
+			assert.Len(t, readFolder(t, filepath.Join(dir, restCallDirs[each.rc])), each.files)
+			assert.DirExists(t, filepath.Join(dir, restCallDirs[each.rc], "1672531200"))
+		})
+	}
+}
+
+func TestFetcherConcurrent(t *testing.T) {
+	const fetchers = 8
+
+	var (
+		wg        sync.WaitGroup
+		dir       = t.TempDir()
+		a, logins = newTestApp(http.StatusOK, `[{"NORAD_CAT_ID":"25544"},{"NORAD_CAT_ID":"48274"}]`)
+		errs      = make(chan error, fetchers)
+	)
+
+	for i := 0; i < fetchers; i++ {
+		fetcher, err := newJobFetcher(Job{Name: fmt.Sprintf("job-%d", i), Format: persist.Json, Persister: persist.OneFilePerRow, WorkDir: filepath.Join(dir, fmt.Sprintf("job-%d", i))}, a.client)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- fetcher.Fetch(context.Background(), spacetrack.Decay, time.Now())
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, int32(1), *logins, "fetchers sharing the client must share the session")
+
+	for i := 0; i < fetchers; i++ {
+		assert.Len(t, readFolder(t, filepath.Join(dir, fmt.Sprintf("job-%d", i), restCallDirs[spacetrack.Decay])), 2)
+	}
+}
+
+func TestFetcherUnknownRestCall(t *testing.T) {
+	a, logins := newTestApp(http.StatusOK, `[]`)
+
+	err := NewFetcher(Job{WorkDir: t.TempDir()}, a.client, nil).Fetch(context.Background(), spacetrack.All, time.Now())
+
+	assert.ErrorIs(t, err, ErrUnknownRestCall)
+	assert.Equal(t, int32(0), *logins)
+}
+
+func TestFetcherIncremental(t *testing.T) {
+	var (
+		dir   = t.TempDir()
+		paths []string
+	)
+
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(r.URL.Path, "/basicspacedata/query") {
+			paths = append(paths, r.URL.Path)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Set-Cookie": []string{testCookie}, "Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`[{"GP_ID":"99"},{"GP_ID":"101"},{"GP_ID":"100"}]`)),
+			Request:    r,
+		}, nil
+	})
+
+	client := spacetrack.New(
+		spacetrack.Credentials{Identity: testIdentity, Password: testPassword},
+		spacetrack.WithHTTPClient(&http.Client{Transport: transport}),
+		spacetrack.WithRateLimit(spacetrack.NoRateLimit),
+	)
+
+	fetch := func(job Job) {
+		fetcher, err := newJobFetcher(job, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, fetcher.Fetch(context.Background(), spacetrack.Tle, time.Now()))
+	}
+
+	job := Job{Name: "tle", RestCall: spacetrack.Tle, Format: persist.Json, Persister: persist.OneFile, WorkDir: dir, incremental: true}
+	custom := Job{Name: "iss", RestCall: spacetrack.Tle, Query: "NORAD_CAT_ID/25544", Format: persist.Json, Persister: persist.OneFile, WorkDir: dir, incremental: true}
+	sorted := Job{Name: "iss-sorted", RestCall: spacetrack.Tle, Query: "NORAD_CAT_ID/25544/orderby/EPOCH desc", Format: persist.Json, Persister: persist.OneFile, WorkDir: dir, incremental: true}
+	snapshot := Job{Name: "snapshot", RestCall: spacetrack.Tle, Format: persist.Json, Persister: persist.OneFile, WorkDir: dir}
+
+	fetch(job)
+	fetch(job)
+	fetch(custom)
+	fetch(custom)
+	fetch(sorted)
+	fetch(sorted)
+	fetch(snapshot)
+	fetch(snapshot)
+
+	assert.Equal(t, []string{
+		"/basicspacedata/query" + spacetrack.Tle.Query("").Path(),
+		"/basicspacedata/query" + spacetrack.Tle.Query("DECAY_DATE/null-val/GP_ID/>101/orderby/GP_ID asc").Path(),
+		"/basicspacedata/query" + spacetrack.Tle.Query("NORAD_CAT_ID/25544").Path(),
+		"/basicspacedata/query" + spacetrack.Tle.Query("NORAD_CAT_ID/25544/GP_ID/>101").Path(),
+		"/basicspacedata/query" + spacetrack.Tle.Query("NORAD_CAT_ID/25544/orderby/EPOCH desc").Path(),
+		"/basicspacedata/query" + spacetrack.Tle.Query("NORAD_CAT_ID/25544/GP_ID/>101/orderby/EPOCH desc").Path(),
+		"/basicspacedata/query" + spacetrack.Tle.Query("").Path(),
+		"/basicspacedata/query" + spacetrack.Tle.Query("").Path(),
+	}, paths)
+
+	mark, err := highWaterMark(dir, "tle", spacetrack.Tle)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "101", mark)
+
+	mark, err = highWaterMark(dir, "snapshot", spacetrack.Tle)
+
+	assert.Nil(t, err)
+	assert.Empty(t, mark, "jobs which aren't incremental don't keep marks")
+}
+
+// ... recordingPersister keeps the records of each call to Persist instead of writing them.
+type recordingPersister struct {
+	calls [][]any
+}
+
+func (p *recordingPersister) Persist(ctx context.Context, folder string, records []any) error {
+	p.calls = append(p.calls, records)
+	return nil
+}
+
+func TestFetcherDedup(t *testing.T) {
+	var (
+		dir       = t.TempDir()
+		body      string
+		persister = &recordingPersister{}
+	)
+
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Set-Cookie": []string{testCookie}, "Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	})
+
+	client := spacetrack.New(
+		spacetrack.Credentials{Identity: testIdentity, Password: testPassword},
+		spacetrack.WithHTTPClient(&http.Client{Transport: transport}),
+		spacetrack.WithRateLimit(spacetrack.NoRateLimit),
+	)
+
+	fetcher := NewFetcher(Job{Name: "catalog", RestCall: spacetrack.Tle, Format: persist.Json, Persister: persist.OneFilePerRow, WorkDir: dir, dedup: true}, client, persister)
+
+	for _, each := range []struct {
+		description string
+		body        string
+		persisted   int
+		changes     []spacetrack.Change
+	}{
+		{
+			description: "first fetch adds every record",
+			body:        `[{"NORAD_CAT_ID":"25544","GP_ID":"1"},{"NORAD_CAT_ID":"48274","GP_ID":"2"}]`,
+			persisted:   2,
+			changes:     []spacetrack.Change{{Key: "25544", Change: spacetrack.Added}, {Key: "48274", Change: spacetrack.Added}},
+		},
+		{
+			description: "unchanged records aren't persisted",
+			body:        `[{"NORAD_CAT_ID":"25544","GP_ID":"1"},{"NORAD_CAT_ID":"48274","GP_ID":"2"}]`,
+			changes:     []spacetrack.Change{},
+		},
+		{
+			description: "only added and updated records are persisted",
+			body:        `[{"NORAD_CAT_ID":"25544","GP_ID":"3"},{"NORAD_CAT_ID":"5","GP_ID":"4"}]`,
+			persisted:   2,
+			changes: []spacetrack.Change{
+				{Key: "5", Change: spacetrack.Added},
+				{Key: "48274", Change: spacetrack.Removed},
+				{Key: "25544", Change: spacetrack.Updated},
+			},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			body, persister.calls = each.body, nil
+
+			assert.Nil(t, fetcher.Fetch(context.Background(), spacetrack.Tle, time.Now()))
+
+			if each.persisted == 0 {
+				assert.Empty(t, persister.calls)
+			} else if assert.Len(t, persister.calls, 1) {
+				assert.Len(t, persister.calls[0], each.persisted)
+			}
+
+			var cs spacetrack.Changeset
+			assert.Nil(t, json.Unmarshal(readChangeset(t, filepath.Join(dir, restCallDirs[spacetrack.Tle])), &cs))
+			assert.Equal(t, each.changes, cs.Changes)
+		})
+	}
+}
+
+// ... readChangeset returns the json changeset of the newest execution under the folder.
+func readChangeset(t *testing.T, dir string) []byte {
+	executions, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, executions[len(executions)-1].Name(), changesetFile+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return content
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	// ... number of intervals without a successful run of a job before the program is not ready.
+	defaultReadyIntervals = 3
+
+	statusOk   = "ok"
+	statusFail = "fail"
+)
+
+// ... healthCheck is the result of one of the checks of the readiness.
+type healthCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ... healthReport is the body of /healthz and /readyz.
+type healthReport struct {
+	Status string        `json:"status"`
+	Checks []healthCheck `json:"checks,omitempty"`
+}
+
+func (hr *healthReport) add(name string, err error) {
+	check := healthCheck{Name: name, Status: statusOk}
+	if err != nil {
+		check.Status, check.Error = statusFail, err.Error()
+		hr.Status = statusFail
+	}
+
+	hr.Checks = append(hr.Checks, check)
+}
+
+// ... healthz tells whether the process is alive, so it is always ok if it can answer.
+func (a *app) healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, healthReport{Status: statusOk})
+}
+
+// ... readyz tells whether the program is doing its job, see readiness.
+func (a *app) readyz(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, a.readiness(time.Now()))
+}
+
+func writeHealthReport(w http.ResponseWriter, hr healthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if hr.Status != statusOk {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	json.NewEncoder(w).Encode(hr) //nolint:errcheck
+}
+
+// ... readiness checks that the session is authenticated, that the work dir of every job is writable and that every
+// job has succeeded within the last server.ready_intervals intervals.
+func (a *app) readiness(now time.Time) healthReport {
+	var (
+		c       = a.config()
+		hr      = healthReport{Status: statusOk}
+		checked = make(map[string]bool)
+		factor  = c.Server.ReadyIntervals
+	)
+
+	if factor <= 0 {
+		factor = defaultReadyIntervals
+	}
+
+	hr.add("session", a.client.CheckSession())
+
+	for _, job := range c.jobs() {
+		if !checked[job.WorkDir] {
+			checked[job.WorkDir] = true
+			hr.add("work_dir:"+job.WorkDir, writable(job.WorkDir))
+		}
+
+		hr.add("job:"+job.Name, a.lastRunCheck(job, factor, now))
+	}
+
+	return hr
+}
+
+// ... lastRunCheck fails if the job hasn't succeeded for factor times its interval.
+func (a *app) lastRunCheck(job Job, factor int, now time.Time) error {
+	last := a.startTime
+	if t, ok := a.lastSuccess.Load(job.Name); ok {
+		last = t.(time.Time)
+	}
+
+	if maxAge := time.Duration(factor) * job.interval(); now.Sub(last) > maxAge {
+		return fmt.Errorf("no successful run since %s, more than %s ago", last.Format(time.RFC3339), maxAge)
+	}
+
+	return nil
+}
+
+// ... writable creates and removes a temporary file inside the dir, creating the dir if it doesn't exist yet.
+func writable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".readyz-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+
+	return os.Remove(f.Name())
+}
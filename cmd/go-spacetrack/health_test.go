@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func checkNames(hr healthReport, status string) []string {
+	var names []string
+
+	for _, check := range hr.Checks {
+		if check.Status == status {
+			names = append(names, check.Name)
+		}
+	}
+
+	return names
+}
+
+// ... newReadinessApp returns an app with the jobs passed, authenticated against the fake space-track if authenticated is true.
+func newReadinessApp(t *testing.T, authenticated bool, c Config) *app {
+	a, _ := newTestApp(http.StatusOK, `""`)
+	a.cfg = c
+
+	if authenticated {
+		if err := a.client.Login(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return a
+}
+
+func TestReadiness(t *testing.T) {
+	var (
+		now    = time.Now()
+		dir    = t.TempDir()
+		notDir = createFile(t, dir, "notadir", 0666).Name()
+	)
+
+	for _, each := range []struct {
+		description   string
+		authenticated bool
+		jobs          []Job
+		lastSuccess   map[string]time.Time
+		failed        []string
+	}{
+		{
+			description:   "authenticated with recent runs is ready",
+			authenticated: true,
+			jobs:          []Job{{Name: "tle", WorkDir: dir, Interval: "1h"}, {Name: "cdm", WorkDir: dir, Interval: "10m"}},
+			lastSuccess:   map[string]time.Time{"tle": now.Add(-2 * time.Hour), "cdm": now.Add(-10 * time.Minute)},
+		},
+		{
+			description: "not authenticated",
+			jobs:        []Job{{Name: "tle", WorkDir: dir, Interval: "1h"}},
+			lastSuccess: map[string]time.Time{"tle": now},
+			failed:      []string{"session"},
+		},
+		{
+			description:   "work dir which is a file",
+			authenticated: true,
+			jobs:          []Job{{Name: "tle", WorkDir: filepath.Join(notDir, "tle"), Interval: "1h"}},
+			lastSuccess:   map[string]time.Time{"tle": now},
+			failed:        []string{"work_dir:" + filepath.Join(notDir, "tle")},
+		},
+		{
+			description:   "job without successful runs for more than 3 intervals",
+			authenticated: true,
+			jobs:          []Job{{Name: "tle", WorkDir: dir, Interval: "1h"}, {Name: "cdm", WorkDir: dir, Interval: "10m"}},
+			lastSuccess:   map[string]time.Time{"tle": now, "cdm": now.Add(-31 * time.Minute)},
+			failed:        []string{"job:cdm"},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			a := newReadinessApp(t, each.authenticated, Config{Jobs: each.jobs})
+			for name, last := range each.lastSuccess {
+				a.lastSuccess.Store(name, last)
+			}
+
+			hr := a.readiness(now)
+
+			assert.Equal(t, each.failed, checkNames(hr, statusFail))
+			if len(each.failed) == 0 {
+				assert.Equal(t, statusOk, hr.Status)
+			} else {
+				assert.Equal(t, statusFail, hr.Status)
+			}
+		})
+	}
+
+	t.Run("ready intervals are configurable", func(t *testing.T) {
+		a := newReadinessApp(t, true, Config{Server: Server{ReadyIntervals: 1}, Jobs: []Job{{Name: "tle", WorkDir: dir, Interval: "1h"}}})
+		a.lastSuccess.Store("tle", now.Add(-61*time.Minute))
+
+		assert.Equal(t, []string{"job:tle"}, checkNames(a.readiness(now), statusFail))
+	})
+}
+
+func TestHealthHandlers(t *testing.T) {
+	t.Run("healthz is always ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newApp().newServeMux().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+	})
+
+	t.Run("readyz fails with service unavailable", func(t *testing.T) {
+		var hr healthReport
+
+		a := newReadinessApp(t, false, Config{Jobs: []Job{{Name: "tle", WorkDir: t.TempDir(), Interval: "1h"}}})
+
+		rec := httptest.NewRecorder()
+		a.newServeMux().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &hr))
+		assert.Equal(t, []string{"session"}, checkNames(hr, statusFail))
+	})
+}
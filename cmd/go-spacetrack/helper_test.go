@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+)
+
+const (
+	testIdentity = "someone@example.com"
+	testPassword = "sup3r-s3cr3t-passw0rd"
+	testCookie   = "chocolatechip=t0k3nv4lu3"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// ... writeSnapshot writes the records into a folder, as an execution of their rest call persisted with the persister
+// and in the format passed.
+func writeSnapshot[T spacetrack.Unit](t *testing.T, p persist.PersisterMod, f persist.Format, arr ...T) string {
+	folder := t.TempDir()
+
+	persister, err := persist.GetPersister(p, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := persister.Persist(context.Background(), folder, spacetrack.Split(spacetrack.Wrap(arr), p == persist.OneFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	return folder
+}
+
+// ... newTestApp returns an app whose client authenticates with the test credentials, without rate limit, answering
+// every request with the status code and body passed. It returns the amount of authentications too.
+func newTestApp(statusCode int, body string) (*app, *int32) {
+	var (
+		a      = newApp()
+		logins int32
+	)
+
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(r.URL.Path, "/ajaxauth/login") {
+			atomic.AddInt32(&logins, 1)
+		}
+
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+			StatusCode: statusCode,
+			Proto:      "HTTP/1.1",
+			Header:     http.Header{"Set-Cookie": []string{testCookie}, "Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	})
+
+	a.client = spacetrack.New(
+		spacetrack.Credentials{Identity: testIdentity, Password: testPassword},
+		spacetrack.WithHTTPClient(&http.Client{Transport: transport}),
+		spacetrack.WithRateLimit(int(time.Minute/time.Microsecond)),
+		spacetrack.WithMetrics(clientMetrics),
+	)
+
+	return a, &logins
+}
+
+func createFile(t *testing.T, dir, fileName string, perm os.FileMode) *os.File {
+	var err error
+
+	if strings.TrimSpace(dir) == "" {
+		if dir, err = os.Getwd(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.OpenFile(path.Join(dir, fileName), os.O_CREATE|os.O_RDWR, perm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		os.Remove(f.Name())
+	})
+
+	return f
+}
+
+func createTmpWithPerm(t *testing.T, mod os.FileMode) *os.File {
+	f, err := os.CreateTemp("./", "tmpFile")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing permissions to only read
+	if err = f.Chmod(mod); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		os.Remove(f.Name())
+	})
+	return f
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+)
+
+const (
+	// TLS10 is TLS 1.0, kept only for old proxies.
+	TLS10 = "1.0"
+	// TLS11 is TLS 1.1, kept only for old proxies.
+	TLS11 = "1.1"
+	// TLS12 is TLS 1.2, the default minimum version.
+	TLS12 = "1.2"
+	// TLS13 is TLS 1.3
+	TLS13 = "1.3"
+
+	// ... default maximum amount of time of each execution of a job.
+	defaultJobTimeout = 2 * time.Minute
+)
+
+var (
+	// ErrTLSVersionNotAllowed is used to indicate that the minimum TLS version is unknown.
+	ErrTLSVersionNotAllowed = errors.New("tls version not allowed")
+
+	// TLSVersionValues are the TLS versions allowed in the configuration.
+	TLSVersionValues = []string{TLS10, TLS11, TLS12, TLS13}
+)
+
+// HTTP configures how we connect to space-track: its url, timeouts, proxy, certificates and pool of connections.
+// It is only read at startup, because the session belongs to the url and the connections to the transport.
+type HTTP struct {
+	// BaseURL is the url of space-track, e.g. a mirror. If empty, https://www.space-track.org is used.
+	BaseURL string `json:"base_url" yaml:"base_url" mapstructure:"base_url"`
+	// UserAgent is the User-Agent header of the requests. If empty, go-spacetrack is used.
+	UserAgent string `json:"user_agent" yaml:"user_agent" mapstructure:"user_agent"`
+	// Timeout is the maximum amount of time of each request, e.g. 1m. If empty, there is no timeout per request.
+	Timeout string `json:"timeout" yaml:"timeout" mapstructure:"timeout"`
+	// JobTimeout is the maximum amount of time of each execution of a job, including all its requests and the waits
+	// of the rate limiter. If empty, 2m is used.
+	JobTimeout string `json:"job_timeout" yaml:"job_timeout" mapstructure:"job_timeout"`
+	// DialTimeout is the maximum amount of time to open each connection.
+	DialTimeout string `json:"dial_timeout" yaml:"dial_timeout" mapstructure:"dial_timeout"`
+	// TLSHandshakeTimeout is the maximum amount of time of the TLS handshake.
+	TLSHandshakeTimeout string `json:"tls_handshake_timeout" yaml:"tls_handshake_timeout" mapstructure:"tls_handshake_timeout"`
+	// ResponseHeaderTimeout is the maximum amount of time to wait for the headers of each response.
+	ResponseHeaderTimeout string `json:"response_header_timeout" yaml:"response_header_timeout" mapstructure:"response_header_timeout"`
+	// IdleConnTimeout is the maximum amount of time an idle connection is kept open.
+	IdleConnTimeout string `json:"idle_conn_timeout" yaml:"idle_conn_timeout" mapstructure:"idle_conn_timeout"`
+	// Proxy is the url of the http or https proxy. If empty, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY env vars are used.
+	Proxy string `json:"proxy" yaml:"proxy" mapstructure:"proxy"`
+	// CAFile is a bundle of PEM certificates trusted besides the ones of the system, e.g. the CA of a corporate proxy.
+	CAFile string `json:"ca_file" yaml:"ca_file" mapstructure:"ca_file"`
+	// CertFile is the PEM client certificate, which requires KeyFile.
+	CertFile string `json:"cert_file" yaml:"cert_file" mapstructure:"cert_file"`
+	// KeyFile is the PEM key of the client certificate.
+	KeyFile string `json:"key_file" yaml:"key_file" mapstructure:"key_file"`
+	// TLSMinVersion is the minimum TLS version accepted: 1.0, 1.1, 1.2 or 1.3. If empty, 1.2 is used.
+	TLSMinVersion TLSVersion `json:"tls_min_version" yaml:"tls_min_version" mapstructure:"tls_min_version"`
+	// MaxIdleConns is the maximum amount of idle connections. If zero, 100 is used.
+	MaxIdleConns int `json:"max_idle_conns" yaml:"max_idle_conns" mapstructure:"max_idle_conns"`
+	// MaxIdleConnsPerHost is the maximum amount of idle connections to each host. If zero, 2 is used.
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host" yaml:"max_idle_conns_per_host" mapstructure:"max_idle_conns_per_host"`
+	// MaxConnsPerHost is the maximum amount of connections to each host. If zero, there is no limit.
+	MaxConnsPerHost int `json:"max_conns_per_host" yaml:"max_conns_per_host" mapstructure:"max_conns_per_host"`
+}
+
+// ... transport returns the configuration of the http client of the library. Durations have already been validated.
+func (h HTTP) transport() spacetrack.Transport {
+	return spacetrack.Transport{
+		Timeout:               parseDuration(h.Timeout),
+		DialTimeout:           parseDuration(h.DialTimeout),
+		TLSHandshakeTimeout:   parseDuration(h.TLSHandshakeTimeout),
+		ResponseHeaderTimeout: parseDuration(h.ResponseHeaderTimeout),
+		IdleConnTimeout:       parseDuration(h.IdleConnTimeout),
+		Proxy:                 h.Proxy,
+		CAFile:                h.CAFile,
+		CertFile:              h.CertFile,
+		KeyFile:               h.KeyFile,
+		TLSMinVersion:         h.TLSMinVersion.version(),
+		MaxIdleConns:          h.MaxIdleConns,
+		MaxIdleConnsPerHost:   h.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       h.MaxConnsPerHost,
+	}
+}
+
+// ... jobTimeout returns the maximum amount of time of each execution of a job.
+func (h HTTP) jobTimeout() time.Duration {
+	if d := parseDuration(h.JobTimeout); d > 0 {
+		return d
+	}
+	return defaultJobTimeout
+}
+
+// ... parseDuration returns zero if the value is empty or it isn't a duration.
+func parseDuration(value string) time.Duration {
+	d, _ := time.ParseDuration(value) //nolint:errcheck
+	return d
+}
+
+// TLSVersion is the minimum TLS version accepted when connecting to space-track.
+type TLSVersion string
+
+// Set tries to set the TLSVersion returning error if the input is incorrect
+func (tv *TLSVersion) Set(input string) error {
+	switch strings.TrimPrefix(strings.ToLower(input), "tls") {
+	case TLS10:
+		*tv = TLS10
+	case TLS11:
+		*tv = TLS11
+	case TLS12:
+		*tv = TLS12
+	case TLS13:
+		*tv = TLS13
+	default:
+		return ErrTLSVersionNotAllowed
+	}
+	return nil
+}
+
+// ... version returns the crypto/tls constant of the version, e.g. 1.3 or tls1.3, being zero, the default of the library,
+// if it is empty.
+func (tv TLSVersion) version() uint16 {
+	var normalized TLSVersion
+	if err := normalized.Set(string(tv)); err != nil {
+		return 0
+	}
+
+	switch normalized {
+	case TLS10:
+		return tls.VersionTLS10
+	case TLS11:
+		return tls.VersionTLS11
+	case TLS12:
+		return tls.VersionTLS12
+	case TLS13:
+		return tls.VersionTLS13
+	}
+	return 0
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPTransport(t *testing.T) {
+	got := HTTP{
+		Timeout:             "1m",
+		DialTimeout:         "30s",
+		IdleConnTimeout:     "90s",
+		Proxy:               "http://proxy:3128",
+		TLSMinVersion:       "TLS1.3",
+		MaxIdleConnsPerHost: 4,
+	}.transport()
+
+	assert.Equal(t, spacetrack.Transport{
+		Timeout:             time.Minute,
+		DialTimeout:         30 * time.Second,
+		IdleConnTimeout:     90 * time.Second,
+		Proxy:               "http://proxy:3128",
+		TLSMinVersion:       tls.VersionTLS13,
+		MaxIdleConnsPerHost: 4,
+	}, got)
+}
+
+func TestHTTPJobTimeout(t *testing.T) {
+	for _, each := range []struct {
+		description, jobTimeout string
+		want                    time.Duration
+	}{
+		{description: "default when empty", want: defaultJobTimeout},
+		{description: "configured one", jobTimeout: "10m", want: 10 * time.Minute},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.Equal(t, each.want, HTTP{JobTimeout: each.jobTimeout}.jobTimeout())
+		})
+	}
+}
+
+func TestTLSVersion(t *testing.T) {
+	for _, each := range []struct {
+		input   string
+		want    uint16
+		wantErr bool
+	}{
+		{input: "1.0", want: tls.VersionTLS10},
+		{input: "tls1.1", want: tls.VersionTLS11},
+		{input: "1.2", want: tls.VersionTLS12},
+		{input: "TLS1.3", want: tls.VersionTLS13},
+		{input: "", wantErr: true},
+		{input: "ssl3", wantErr: true},
+	} {
+		t.Run("tls version "+each.input, func(t *testing.T) {
+			var tv TLSVersion
+
+			assert.Equal(t, each.wantErr, tv.Set(each.input) != nil)
+			assert.Equal(t, each.want, TLSVersion(each.input).version())
+		})
+	}
+}
+
+func TestConnect(t *testing.T) {
+	var userAgents []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.UserAgent())
+
+		if strings.HasSuffix(r.URL.Path, "/ajaxauth/login") {
+			w.Header().Set("Set-Cookie", testCookie)
+			return
+		}
+
+		fmt.Fprint(w, `[{"NORAD_CAT_ID":"25544","OBJECT_NAME":"ISS (ZARYA)"}]`)
+	}))
+	defer srv.Close()
+
+	var (
+		a   = newApp()
+		dir = t.TempDir()
+		cfg = Config{HTTP: HTTP{BaseURL: srv.URL, UserAgent: "go-spacetrack-test/1.0", Timeout: "5s"}, RateLimit: int(time.Minute / time.Microsecond)}
+	)
+
+	if err := a.connect(cfg); err != nil {
+		t.Fatal(err)
+	}
+	a.configureClient(cfg, spacetrack.Credentials{Identity: testIdentity, Password: testPassword})
+
+	err := a.runJob(context.Background(), Job{Name: "connect", RestCall: spacetrack.Decay, Format: persist.Json, Persister: persist.OneFile, WorkDir: dir})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"go-spacetrack-test/1.0", "go-spacetrack-test/1.0"}, userAgents)
+	assert.Len(t, readFolder(t, filepath.Join(dir, restCallDirs[spacetrack.Decay])), 1)
+
+	t.Run("invalid transport is an error", func(t *testing.T) {
+		assert.ErrorIs(t, newApp().connect(Config{HTTP: HTTP{CAFile: filepath.Join(t.TempDir(), "notexistent.pem")}}), spacetrack.ErrTransport)
+	})
+}
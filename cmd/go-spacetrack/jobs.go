@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/internal/telemetry"
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// ... name of the job built from the top level fields when the configuration has no jobs.
+const defaultJobName = "default"
+
+// ErrJobFailed is returned when some of the rest calls of a job have failed.
+var ErrJobFailed = errors.New("job failed")
+
+// Job is a fetch of one rest call, or all of them, with its own query, format, persister, output dir and interval.
+// All the jobs share the same authenticated session and rate limiter.
+type Job struct {
+	// Name identifies the job in the logs and the files of its state, so it must be unique and file name safe.
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+	// RestCall is the rest call to execute: tle, cdm, dec or all.
+	RestCall spacetrack.RestCall `json:"rest_call" yaml:"rest_call" mapstructure:"rest_call"`
+	// Query are the predicates replacing the default ones of the rest call, e.g. NORAD_CAT_ID/25544/orderby/EPOCH desc
+	Query string `json:"query" yaml:"query" mapstructure:"query"`
+	// Format is how we persist the data. If empty, the top level one is used.
+	Format persist.Format `json:"format" yaml:"format" mapstructure:"format"`
+	// Persister is how we split the data into files: one_file or one_file_per_row. If empty, the top level one is used.
+	Persister persist.PersisterMod `json:"persister" yaml:"persister" mapstructure:"persister"`
+	// WorkDir is the parent folder where the data is persisted. If empty, the top level one is used.
+	WorkDir string `json:"work_dir" yaml:"work_dir" mapstructure:"work_dir"`
+	// Interval is the time between executions of the job. If empty, the top level one is used.
+	Interval string `json:"interval" yaml:"interval" mapstructure:"interval"`
+
+	// ... timeout is the maximum amount of time of each execution of the job, see HTTP.JobTimeout.
+	timeout time.Duration
+	// ... incremental only fetches the records newer than the high-water mark, see Config.Incremental.
+	incremental bool
+	// ... dedup only persists the records added or updated since the previous fetch, see Config.Dedup.
+	dedup bool
+	// ... inheritPersister is set for the jobs decoded without persister, which can't be told apart from one_file
+	// by its value, see markPersisters.
+	inheritPersister bool
+	// ... persister is the persister as written in the configuration, checked by Job.validate, as the decoded one
+	// can't keep the unknown names.
+	persister string
+}
+
+// ... jobs returns the jobs to execute, filling their empty fields with the top level ones. If there are no jobs,
+// the top level fields are used to build the default one, keeping the behaviour of the single job configuration.
+func (c Config) jobs() []Job {
+	if len(c.Jobs) == 0 {
+		return []Job{c.defaultJob()}
+	}
+
+	jobs := make([]Job, len(c.Jobs))
+
+	for i, job := range c.Jobs {
+		if job.RestCall == "" {
+			job.RestCall = spacetrack.All
+		}
+
+		if job.Format == "" {
+			job.Format = c.Format
+		}
+
+		if job.WorkDir == "" {
+			job.WorkDir = c.WorkDir
+		}
+
+		if job.Interval == "" {
+			job.Interval = c.Interval
+		}
+
+		if job.inheritPersister {
+			job.Persister, job.inheritPersister = c.persister(), false
+		}
+
+		job.timeout = c.HTTP.jobTimeout()
+		job.incremental = c.Incremental
+		job.dedup = c.Dedup
+
+		jobs[i] = job
+	}
+
+	return jobs
+}
+
+// ... defaultJob returns the job built from the top level fields.
+func (c Config) defaultJob() Job {
+	return Job{
+		Name:        defaultJobName,
+		RestCall:    c.RestCall,
+		Format:      c.Format,
+		Persister:   c.persister(),
+		WorkDir:     c.WorkDir,
+		Interval:    c.Interval,
+		timeout:     c.HTTP.jobTimeout(),
+		incremental: c.Incremental,
+		dedup:       c.Dedup,
+	}
+}
+
+// ... persister returns the top level persister, see Config.OneFile.
+func (c Config) persister() persist.PersisterMod {
+	if c.OneFile {
+		return persist.OneFilePerRow
+	}
+	return persist.OneFile
+}
+
+// ... markPersisters flags the jobs of the configuration read by viper which have no persister, so they inherit the
+// top level one, keeping the persister written of the rest to validate it.
+func markPersisters(v *viper.Viper, c *Config) {
+	raw, _ := v.Get("jobs").([]any)
+
+	for i := range c.Jobs {
+		if i >= len(raw) {
+			return
+		}
+
+		fields, ok := raw[i].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		c.Jobs[i].inheritPersister = true
+		for k, value := range fields {
+			if strings.EqualFold(k, "persister") {
+				c.Jobs[i].inheritPersister, c.Jobs[i].persister = false, fmt.Sprint(value)
+			}
+		}
+	}
+}
+
+// ... restCalls returns the rest calls executed by the job, being all of them if the rest call is All.
+func (j Job) restCalls() []spacetrack.RestCall {
+	if _, ok := restCalls[j.RestCall]; ok {
+		return []spacetrack.RestCall{j.RestCall}
+	}
+	return []spacetrack.RestCall{spacetrack.Tle, spacetrack.Decay, spacetrack.Cdm}
+}
+
+// ... query returns the predicates of the query of the rest call.
+func (j Job) query(rc spacetrack.RestCall) string {
+	if j.Query != "" {
+		return j.Query
+	}
+	return rc.DefaultQuery()
+}
+
+// ... incrementalQuery returns the predicates of the query of the rest call fetching only the records newer than the
+// high-water mark, being the query of the job if there is no mark yet. The mark goes before the orderby and limit of
+// custom queries, see spacetrack.InsertPredicate.
+func (j Job) incrementalQuery(rc spacetrack.RestCall, mark string) string {
+	switch {
+	case mark == "":
+		return j.query(rc)
+	case j.Query != "":
+		return spacetrack.InsertPredicate(j.Query, rc.CursorField(), ">"+mark)
+	default:
+		return rc.IncrementalQuery(mark)
+	}
+}
+
+// ... interval of the job, which has already been validated.
+func (j Job) interval() time.Duration {
+	d, _ := time.ParseDuration(j.Interval) //nolint:errcheck
+	return d
+}
+
+// ... maxDuration returns the timeout of each execution, being the default one for jobs which weren't built by Config.jobs.
+func (j Job) maxDuration() time.Duration {
+	if j.timeout <= 0 {
+		return defaultJobTimeout
+	}
+	return j.timeout
+}
+
+// ... runJob executes the rest calls of the job once, persisting the data under a folder named after the current unix time.
+func (a *app) runJob(ctx context.Context, job Job) (err error) {
+	var failed int
+
+	ctx, cl := context.WithTimeout(ctx, job.maxDuration())
+	defer cl()
+
+	ctx, span := telemetry.Start(ctx, "spacetrack.job", attribute.String("spacetrack.job", job.Name), attribute.String("spacetrack.rest_call", job.RestCall.String()))
+	defer telemetry.End(span, &err)
+
+	fetcher, err := newJobFetcher(job, a.client)
+	if err != nil {
+		return err
+	}
+
+	Info("executing job", zap.String("job", job.Name), zap.String("rest_call", job.RestCall.String()))
+
+	// ... the run is taken once, so the folders of every rest call of the execution are named the same.
+	run := time.Now()
+
+	for _, rc := range job.restCalls() {
+		if err := fetcher.Fetch(ctx, rc, run); err != nil {
+			Warn("space-track "+rc.String()+" fetch", zap.String("job", job.Name), zap.Error(err))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %s, %d rest calls failed", ErrJobFailed, job.Name, failed)
+	}
+
+	a.lastSuccess.Store(job.Name, time.Now())
+	jobLastSuccess.WithLabelValues(job.Name).SetToCurrentTime()
+
+	return nil
+}
+
+// ... run executes all the jobs of the configuration once, returning the first error found, if any.
+func (a *app) run(ctx context.Context) error {
+	var firstErr error
+
+	for _, job := range a.config().jobs() {
+		if err := a.runJob(ctx, job); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
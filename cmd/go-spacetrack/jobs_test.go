@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/MrTimeout/go-spacetrack/spacetrack/spacetracktest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigJobs(t *testing.T) {
+	t.Run("top level fields are the default job when there are no jobs", func(t *testing.T) {
+		c := Config{WorkDir: "/tmp/spacetrack", Interval: "1h", OneFile: true, RestCall: spacetrack.Tle, Format: persist.Xml}
+
+		assert.Equal(t, []Job{{
+			Name:      defaultJobName,
+			RestCall:  spacetrack.Tle,
+			Format:    persist.Xml,
+			Persister: persist.OneFilePerRow,
+			WorkDir:   "/tmp/spacetrack",
+			Interval:  "1h",
+			timeout:   defaultJobTimeout,
+		}}, c.jobs())
+	})
+
+	t.Run("jobs inherit the empty fields from the top level ones", func(t *testing.T) {
+		c := Config{WorkDir: "/tmp/spacetrack", Interval: "1h", RestCall: spacetrack.Tle, Format: persist.Xml, HTTP: HTTP{JobTimeout: "5m"}, Jobs: []Job{
+			{Name: "iss", RestCall: spacetrack.Tle, Query: "NORAD_CAT_ID/25544", Format: persist.Json, WorkDir: "/tmp/iss", Interval: "5m"},
+			{Name: "everything"},
+		}}
+
+		assert.Equal(t, []Job{
+			{Name: "iss", RestCall: spacetrack.Tle, Query: "NORAD_CAT_ID/25544", Format: persist.Json, WorkDir: "/tmp/iss", Interval: "5m", timeout: 5 * time.Minute},
+			{Name: "everything", RestCall: spacetrack.All, Format: persist.Xml, WorkDir: "/tmp/spacetrack", Interval: "1h", timeout: 5 * time.Minute},
+		}, c.jobs())
+	})
+
+	t.Run("jobs without persister inherit the top level one", func(t *testing.T) {
+		a, _ := newTestConfigApp(t, validConfigContent+"one_file: true\njobs:\n  - name: inherited\n  - name: file\n    persister: one_file\n")
+
+		jobs := a.currentJobs()
+
+		if assert.Len(t, jobs, 2) {
+			assert.Equal(t, persist.OneFilePerRow, jobs[0].Persister)
+			assert.Equal(t, persist.OneFile, jobs[1].Persister)
+		}
+	})
+}
+
+func TestJob(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		job         Job
+		restCalls   []spacetrack.RestCall
+		queries     []string
+		interval    time.Duration
+	}{
+		{
+			description: "one rest call with its default query",
+			job:         Job{RestCall: spacetrack.Cdm, Interval: "30m"},
+			restCalls:   []spacetrack.RestCall{spacetrack.Cdm},
+			queries:     []string{spacetrack.Cdm.DefaultQuery()},
+			interval:    30 * time.Minute,
+		},
+		{
+			description: "one rest call with a custom query",
+			job:         Job{RestCall: spacetrack.Tle, Query: "NORAD_CAT_ID/25544", Interval: "1h"},
+			restCalls:   []spacetrack.RestCall{spacetrack.Tle},
+			queries:     []string{"NORAD_CAT_ID/25544"},
+			interval:    time.Hour,
+		},
+		{
+			description: "all the rest calls",
+			job:         Job{RestCall: spacetrack.All, Interval: "2h"},
+			restCalls:   []spacetrack.RestCall{spacetrack.Tle, spacetrack.Decay, spacetrack.Cdm},
+			queries:     []string{spacetrack.Tle.DefaultQuery(), spacetrack.Decay.DefaultQuery(), spacetrack.Cdm.DefaultQuery()},
+			interval:    2 * time.Hour,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var queries []string
+
+			for _, rc := range each.job.restCalls() {
+				queries = append(queries, each.job.query(rc))
+			}
+
+			assert.Equal(t, each.restCalls, each.job.restCalls())
+			assert.Equal(t, each.queries, queries)
+			assert.Equal(t, each.interval, each.job.interval())
+		})
+	}
+}
+
+func TestRunJobs(t *testing.T) {
+	t.Run("all the jobs share one session", func(t *testing.T) {
+		var (
+			dir  = t.TempDir()
+			body = `[{"NORAD_CAT_ID":"25544","OBJECT_NAME":"ISS (ZARYA)"},{"NORAD_CAT_ID":"48274","OBJECT_NAME":"CSS (TIANHE)"}]`
+		)
+
+		a, logins := newTestApp(http.StatusOK, body)
+		a.cfg = Config{Auth: SpaceTrackAuth{Identity: testIdentity, Password: testPassword}, Interval: "1h", Format: persist.Json, Jobs: []Job{
+			{Name: "rows", RestCall: spacetrack.Decay, Persister: persist.OneFilePerRow, WorkDir: filepath.Join(dir, "rows")},
+			{Name: "file", RestCall: spacetrack.Decay, Persister: persist.OneFile, WorkDir: filepath.Join(dir, "file")},
+		}}
+
+		assert.Nil(t, a.run(context.Background()))
+		assert.Equal(t, int32(1), *logins)
+		assert.Len(t, readFolder(t, filepath.Join(dir, "rows", "spacetrack-dec")), 2)
+		assert.Len(t, readFolder(t, filepath.Join(dir, "file", "spacetrack-dec")), 1)
+	})
+
+	t.Run("failed rest calls fail the job", func(t *testing.T) {
+		a, _ := newTestApp(http.StatusOK, `not json`)
+		a.cfg = Config{Auth: SpaceTrackAuth{Identity: testIdentity, Password: testPassword}, Interval: "1h", Format: persist.Json, Jobs: []Job{
+			{Name: "broken", WorkDir: t.TempDir()},
+		}}
+
+		assert.ErrorIs(t, a.run(context.Background()), ErrJobFailed)
+	})
+}
+
+func TestRunJobsFakeServer(t *testing.T) {
+	s := spacetracktest.NewServer()
+	defer s.Close()
+
+	var (
+		a   = newApp()
+		dir = t.TempDir()
+		cfg = Config{HTTP: HTTP{BaseURL: s.URL}, RateLimit: int(time.Minute / time.Microsecond), Interval: "1h", Format: persist.Json, Jobs: []Job{
+			{Name: "everything", RestCall: spacetrack.All, Persister: persist.OneFilePerRow, WorkDir: dir},
+		}}
+	)
+
+	if err := a.connect(cfg); err != nil {
+		t.Fatal(err)
+	}
+	a.cfg = cfg
+	a.configureClient(cfg, spacetrack.Credentials{Identity: spacetracktest.Identity, Password: spacetracktest.Password})
+
+	assert.Nil(t, a.run(context.Background()))
+	assert.Equal(t, 1, s.Logins())
+	assert.Equal(t, 3, s.Queries())
+	assert.Len(t, readFolder(t, filepath.Join(dir, "spacetrack-tle")), 2)
+	assert.Len(t, readFolder(t, filepath.Join(dir, "spacetrack-dec")), 2)
+	assert.Len(t, readFolder(t, filepath.Join(dir, "spacetrack-cdm")), 1)
+
+	runs := map[string]bool{}
+	for _, rc := range []string{"spacetrack-tle", "spacetrack-dec", "spacetrack-cdm"} {
+		executions, err := os.ReadDir(filepath.Join(dir, rc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range executions {
+			runs[e.Name()] = true
+		}
+	}
+	assert.Len(t, runs, 1, "the rest calls of one execution must share the run folder")
+}
+
+func TestRecordReplayJobs(t *testing.T) {
+	var (
+		recordings = t.TempDir()
+		recorded   = t.TempDir()
+		replayed   = t.TempDir()
+	)
+
+	// ... runJobs runs a job of every rest call, persisting into dir, with the record and replay dirs passed.
+	runJobs := func(baseURL, dir, record, replay string) {
+		a := newApp()
+		cfg := Config{HTTP: HTTP{BaseURL: baseURL}, Record: record, Replay: replay, RateLimit: int(time.Minute / time.Microsecond), Interval: "1h", Format: persist.Json, Jobs: []Job{
+			{Name: "everything", RestCall: spacetrack.All, Persister: persist.OneFilePerRow, WorkDir: dir},
+		}}
+
+		if err := a.connect(cfg); err != nil {
+			t.Fatal(err)
+		}
+		a.cfg = cfg
+		a.configureClient(cfg, spacetrack.Credentials{Identity: spacetracktest.Identity, Password: spacetracktest.Password})
+
+		assert.Nil(t, a.run(context.Background()))
+	}
+
+	s := spacetracktest.NewServer()
+	runJobs(s.URL, recorded, recordings, "")
+	s.Close()
+
+	runJobs(s.URL, replayed, "", recordings)
+
+	for _, rc := range []string{"spacetrack-tle", "spacetrack-dec", "spacetrack-cdm"} {
+		want, got := readFolder(t, filepath.Join(recorded, rc)), readFolder(t, filepath.Join(replayed, rc))
+
+		if assert.Equal(t, len(want), len(got), rc) {
+			for i := range want {
+				assert.Equal(t, readFile(t, filepath.Join(recorded, rc), want[i]), readFile(t, filepath.Join(replayed, rc), got[i]))
+			}
+		}
+	}
+}
+
+// ... readFile returns the content of a file persisted in the only execution folder under dir.
+func readFile(t *testing.T, dir string, f os.DirEntry) []byte {
+	executions, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, executions[0].Name(), f.Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+// ... readFolder returns the files persisted in the only execution folder under dir.
+func readFolder(t *testing.T, dir string) []os.DirEntry {
+	executions, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !assert.Len(t, executions, 1) {
+		return nil
+	}
+
+	files, err := os.ReadDir(filepath.Join(dir, executions[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"go.uber.org/zap"
+)
+
+var (
+	// ... logger is swapped atomically, so it can be configured again while other goroutines are logging.
+	logger atomic.Pointer[zap.Logger]
+	// ... defaultLogger is used until the logger is configured, so logging never panics, e.g. when the flags are wrong.
+	defaultLogger = newDefaultLogger()
+
+	// ... loggerSinks are the files and connections of the configured logger, closed when it is replaced. sinksMu
+	// serializes the replacements, so each sink is closed once.
+	sinksMu     sync.Mutex
+	loggerSinks sinks
+)
+
+// ... newDefaultLogger logs to stdout, in info level, which is the default configuration of the console appender.
+func newDefaultLogger() *zap.Logger {
+	l, _ := Logger{ConsoleAppender: NewConsoleAppender(InfoLevel)}.Tee() //nolint:errcheck
+	return l
+}
+
+// L returns the logger used by the package, which is the default one until it is configured or replaced.
+func L() *zap.Logger {
+	if l := logger.Load(); l != nil {
+		return l
+	}
+	return defaultLogger
+}
+
+// SetLogger replaces the logger used by the package, e.g. by programs which want to use their own one. Sensitive
+// fields are masked anyway, see spacetrack.NewRedactCore. A nil logger restores the default one.
+func SetLogger(l *zap.Logger) {
+	if l != nil {
+		l = l.WithOptions(zap.WrapCore(spacetrack.NewRedactCore))
+	}
+
+	replaceLogger(l, nil)
+}
+
+// Configure configures the zap logger from a Config structure. If some appender can't be opened,
+// the error is returned and the previous logger is kept. Otherwise, the files and connections of the previous
+// logger are closed.
+func Configure(cfg Logger) error {
+	l, s, err := cfg.open()
+	if err != nil {
+		return err
+	}
+
+	replaceLogger(l, s)
+
+	return nil
+}
+
+// ... replaceLogger stores the logger and closes the sinks of the previous one straight away, so L() returns the new
+// one from now on, while the goroutines still holding the previous one lose their writes instead of opening the files
+// again, see fileSink.
+func replaceLogger(l *zap.Logger, s sinks) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	logger.Store(l)
+
+	previous := loggerSinks
+	loggerSinks = s
+
+	if err := previous.Close(); err != nil {
+		L().Warn("closing the appenders of the previous logger", zap.Error(err))
+	}
+}
+
+// Debug will log a zap.Logger debug message
+func Debug(msg string, fields ...zap.Field) {
+	L().Debug(msg, fields...)
+}
+
+// Info will log a zap.Logger info message
+func Info(msg string, fields ...zap.Field) {
+	L().Info(msg, fields...)
+}
+
+// Warn will log a zap.Logger warn message
+func Warn(msg string, fields ...zap.Field) {
+	L().Warn(msg, fields...)
+}
+
+// Error will log a zap.Logger error message
+func Error(msg string, fields ...zap.Field) {
+	L().Error(msg, fields...)
+}
+
+// DPanic will log a zap.Logger dpanic message
+func DPanic(msg string, fields ...zap.Field) {
+	L().DPanic(msg, fields...)
+}
+
+// Panic will log a zap.Logger panic message
+func Panic(msg string, fields ...zap.Field) {
+	L().Panic(msg, fields...)
+}
+
+// Fatal will log a zap.Logger fatal message
+func Fatal(msg string, fields ...zap.Field) {
+	L().Fatal(msg, fields...)
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func fileContains(t *testing.T, fileName string, contains string) {
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Contains(t, string(b), contains)
+}
+
+func TestLoggerFuncs(t *testing.T) {
+	f := createFile(t, "", "spacetrack.log", 0666)
+	err := Configure(Logger{
+		Production: false,
+		FileAppenders: []FileLoggerAppender{
+			{
+				LoggerFileLevel: DebugLevel,
+				LoggerFileName:  f.Name(),
+				DateTimeFormat:  RFC3339,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, each := range []struct {
+		description, msg string
+		loggerLevel      LoggerLevel
+		logLevelFunc     func(string, ...zap.Field)
+	}{
+		{
+			description:  "Log into file using debug logger level",
+			msg:          "debug message here",
+			loggerLevel:  DebugLevel,
+			logLevelFunc: Debug,
+		},
+		{
+			description:  "Log into file using info logger level",
+			msg:          "info message here",
+			loggerLevel:  InfoLevel,
+			logLevelFunc: Info,
+		},
+		{
+			description:  "Log into file using warn logger level",
+			msg:          "warn message here",
+			loggerLevel:  WarnLevel,
+			logLevelFunc: Warn,
+		},
+		{
+			description:  "Log into file using error logger level",
+			msg:          "error message here",
+			loggerLevel:  ErrorLevel,
+			logLevelFunc: Error,
+		},
+		{
+			description:  "Log into file using dpanic logger level",
+			msg:          "dpanic message here",
+			loggerLevel:  DPanicLevel,
+			logLevelFunc: DPanic,
+		},
+		{
+			description:  "Log into file using panic logger level",
+			msg:          "panic message here",
+			loggerLevel:  PanicLevel,
+			logLevelFunc: Panic,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			if each.loggerLevel == PanicLevel {
+				assert.Panics(t, func() { each.logLevelFunc(each.msg) })
+			} else {
+				each.logLevelFunc(each.msg)
+			}
+
+			fileContains(t, f.Name(), each.msg)
+		})
+	}
+}
+
+func TestLoggerTee(t *testing.T) {
+	t.Run("file which can't be opened is an error", func(t *testing.T) {
+		l, err := NewLogger(false, DebugLevel, filepath.Join(t.TempDir(), "notexistentdir", "spacetrack.log")).Tee()
+
+		assert.Nil(t, l)
+		assert.ErrorIs(t, err, ErrLoggerAppender)
+	})
+
+	t.Run("rotation which can't open the file is an error", func(t *testing.T) {
+		f := createFile(t, t.TempDir(), "notadir", 0666)
+
+		l := NewLogger(false, DebugLevel, filepath.Join(f.Name(), "spacetrack.log"))
+		l.FileAppenders[0].Rotation = Rotation{MaxSize: 1}
+
+		_, err := l.Tee()
+
+		assert.ErrorIs(t, err, ErrLoggerAppender)
+	})
+
+	t.Run("configure keeps the previous logger on error", func(t *testing.T) {
+		old := logger.Load()
+
+		assert.ErrorIs(t, Configure(NewLogger(false, DebugLevel, filepath.Join(t.TempDir(), "notexistentdir", "spacetrack.log"))), ErrLoggerAppender)
+		assert.Same(t, old, logger.Load())
+	})
+
+	t.Run("each appender keeps its own level", func(t *testing.T) {
+		var (
+			dir  = t.TempDir()
+			l    = NewLogger(false, DebugLevel, filepath.Join(dir, "debug.log"), filepath.Join(dir, "info.log"))
+			info = filepath.Join(dir, "info.log")
+		)
+
+		l.FileAppenders[1].LoggerFileLevel = InfoLevel
+
+		zl, err := l.Tee()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		zl.Debug("debug message")
+		zl.Info("info message")
+
+		fileContains(t, filepath.Join(dir, "debug.log"), "debug message")
+		fileContains(t, info, "info message")
+
+		b, err := os.ReadFile(info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NotContains(t, string(b), "debug message")
+	})
+
+	t.Run("configure closes the appenders of the previous logger", func(t *testing.T) {
+		var (
+			dir  = t.TempDir()
+			prev = loggerSinks
+		)
+		t.Cleanup(func() { replaceLogger(nil, prev) })
+
+		l := NewLogger(false, DebugLevel, filepath.Join(dir, "spacetrack.log"))
+		l.FileAppenders[0].Rotation = Rotation{MaxSize: 1}
+
+		if err := Configure(l); err != nil {
+			t.Fatal(err)
+		}
+		first := loggerSinks
+
+		if err := Configure(l); err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, first, 1) {
+			_, err := first[0].(*fileSink).Write([]byte("late write"))
+			assert.ErrorIs(t, err, os.ErrClosed, "the sink of the previous logger doesn't open the file again")
+		}
+		assert.NotSame(t, first[0], loggerSinks[0])
+	})
+
+	t.Run("file is rotated every interval", func(t *testing.T) {
+		var (
+			dir      = t.TempDir()
+			fileName = filepath.Join(dir, "spacetrack.log")
+			l        = NewLogger(false, DebugLevel, fileName)
+		)
+
+		l.FileAppenders[0].Rotation = Rotation{Interval: "50ms"}
+		l.ConsoleAppender = NewConsoleAppender(ErrorLevel)
+
+		zl, s, err := l.open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		zl.Info("before rotation")
+
+		assert.Eventually(t, func() bool {
+			files, err := os.ReadDir(dir)
+			return err == nil && len(files) >= 2
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("file is rotated when it reaches the max size", func(t *testing.T) {
+		var (
+			dir      = t.TempDir()
+			fileName = filepath.Join(dir, "spacetrack.log")
+			l        = NewLogger(false, DebugLevel, fileName)
+		)
+
+		l.FileAppenders[0].Rotation = Rotation{MaxSize: 1, MaxBackups: 1}
+		l.ConsoleAppender = NewConsoleAppender(ErrorLevel)
+
+		zl, err := l.Tee()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// ... 3 megabytes of logs, so the file is rotated twice and the oldest backup is removed.
+		for i := 0; i < 3*1024; i++ {
+			zl.Info(strings.Repeat("x", 1024))
+		}
+
+		// ... lumberjack removes the old backups in background.
+		assert.Eventually(t, func() bool {
+			files, err := os.ReadDir(dir)
+			return err == nil && len(files) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+		fileContains(t, fileName, "xxx")
+	})
+}
+
+func TestConsoleAppender(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		appender    ConsoleAppender
+		stderr      bool
+		want        string
+	}{
+		{
+			description: "console encoding to stdout by default",
+			appender:    ConsoleAppender{LoggerFileLevel: InfoLevel, DateTimeFormat: RFC3339},
+			want:        "INFO\t",
+		},
+		{
+			description: "json encoding to stderr",
+			appender:    ConsoleAppender{LoggerFileLevel: InfoLevel, DateTimeFormat: RFC3339, Target: Stderr, Encoding: JsonEncoding},
+			stderr:      true,
+			want:        `"console message"`,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var (
+				stdout = createFile(t, t.TempDir(), "stdout", 0666)
+				stderr = createFile(t, t.TempDir(), "stderr", 0666)
+			)
+
+			oldStdout, oldStderr := os.Stdout, os.Stderr
+			os.Stdout, os.Stderr = stdout, stderr
+			defer func() {
+				os.Stdout, os.Stderr = oldStdout, oldStderr
+			}()
+
+			zl, err := Logger{ConsoleAppender: each.appender}.Tee()
+			if err != nil {
+				t.Fatal(err)
+			}
+			zl.Info("console message")
+
+			written, empty := stdout.Name(), stderr.Name()
+			if each.stderr {
+				written, empty = empty, written
+			}
+
+			fileContains(t, written, each.want)
+
+			b, err := os.ReadFile(empty)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Empty(t, b)
+		})
+	}
+}
+
+func TestDefaultLogger(t *testing.T) {
+	old := logger.Load()
+	t.Cleanup(func() {
+		logger.Store(old)
+	})
+
+	t.Run("logging before configuring the logger doesn't panic", func(t *testing.T) {
+		logger.Store(nil)
+
+		assert.NotPanics(t, func() { Info("not configured yet") })
+		assert.Same(t, defaultLogger, L())
+	})
+
+	t.Run("injected logger is used masking sensitive fields", func(t *testing.T) {
+		core, logs := observer.New(zapcore.InfoLevel)
+		SetLogger(zap.New(core))
+
+		Info("injected", zap.String("password", testPassword))
+
+		if assert.Equal(t, 1, logs.Len()) {
+			assert.Equal(t, spacetrack.RedactedValue, logs.All()[0].ContextMap()["password"])
+		}
+	})
+
+	t.Run("nil logger restores the default one", func(t *testing.T) {
+		SetLogger(nil)
+
+		assert.Same(t, defaultLogger, L())
+	})
+}
@@ -0,0 +1,10 @@
+package main
+
+import "os"
+
+func main() {
+	// ... cobra has already printed the error, we only need to exit with non-zero code.
+	if err := execute(); err != nil {
+		os.Exit(1)
+	}
+}
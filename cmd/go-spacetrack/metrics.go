@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+var (
+	// ... metricsRegistry has only our metrics and the go runtime ones, instead of the global registry of prometheus.
+	metricsRegistry = prometheus.NewRegistry()
+
+	// ... clientMetrics are the metrics of the requests to space-track.
+	clientMetrics = spacetrack.NewMetrics()
+	// ... persistMetrics are the metrics of the files written by the jobs.
+	persistMetrics = persist.NewMetrics()
+
+	jobLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "spacetrack",
+		Name:      "job_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful execution of each job.",
+	}, []string{"job"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		clientMetrics,
+		persistMetrics,
+		jobLastSuccess,
+	)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Run("successful job", func(t *testing.T) {
+		var (
+			body = `[{"NORAD_CAT_ID":"25544","OBJECT_NAME":"ISS (ZARYA)"},{"NORAD_CAT_ID":"48274","OBJECT_NAME":"CSS (TIANHE)"}]`
+			job  = Job{Name: "metrics", RestCall: spacetrack.Decay, Persister: persist.OneFilePerRow, Format: persist.Json, WorkDir: filepath.Join(t.TempDir(), "rows")}
+		)
+
+		a, _ := newTestApp(http.StatusOK, body)
+
+		assert.Nil(t, a.runJob(context.Background(), job))
+
+		assert.NotZero(t, testutil.ToFloat64(jobLastSuccess.WithLabelValues(job.Name)))
+	})
+
+	t.Run("all the metrics are registered", func(t *testing.T) {
+		problems, err := testutil.GatherAndLint(metricsRegistry)
+
+		assert.Nil(t, err)
+		assert.Empty(t, problems)
+	})
+
+	t.Run("metrics of the client and the persister are exposed", func(t *testing.T) {
+		count, err := testutil.GatherAndCount(metricsRegistry, "spacetrack_requests_total", "spacetrack_records_parsed_total", "spacetrack_files_written_total", "spacetrack_job_last_success_timestamp_seconds")
+
+		assert.Nil(t, err)
+		assert.NotZero(t, count)
+	})
+}
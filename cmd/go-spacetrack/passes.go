@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/MrTimeout/go-spacetrack/spacetrack/sgp4"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidObserver is returned when the coordinates of the observer or its minimum elevation are out of range.
+var ErrInvalidObserver = errors.New("invalid observer")
+
+const (
+	// Sunlit is the illumination of the points of the passes when the object is illuminated by the sun.
+	Sunlit = "sunlit"
+	// Eclipse is the illumination of the points of the passes when the object is within the shadow of the earth.
+	Eclipse = "eclipse"
+)
+
+// Passes are the passes of some objects over an observer, sorted by their acquisition of signal, see sgp4.Pass.
+type Passes struct {
+	XMLName xml.Name  `json:"-" xml:"spacetrack-passes"`
+	Items   []PassRow `json:"item" xml:"item" html:"item"`
+}
+
+// PassRow is a pass of an object over an observer, with the time, azimuth and elevation, in degrees, range, in km, and
+// illumination, Sunlit or Eclipse, of its acquisition of signal, AOS, maximum elevation, MAX, and loss of signal, LOS,
+// and its DURATION in seconds.
+type PassRow struct {
+	XMLName         xml.Name `json:"-" xml:"item" csv:"-"`
+	NoradCatID      string   `json:"NORAD_CAT_ID" xml:"NORAD_CAT_ID" csv:"NORAD_CAT_ID" html:"l=NORAD_CAT_ID,e=span"`
+	ObjectName      string   `json:"OBJECT_NAME" xml:"OBJECT_NAME" csv:"OBJECT_NAME" html:"l=OBJECT_NAME,e=span"`
+	ObjectID        string   `json:"OBJECT_ID" xml:"OBJECT_ID" csv:"OBJECT_ID" html:"l=OBJECT_ID,e=span"`
+	AOS             string   `json:"AOS" xml:"AOS" csv:"AOS" html:"l=AOS,e=span"`
+	AOSAzimuth      float64  `json:"AOS_AZIMUTH" xml:"AOS_AZIMUTH" csv:"AOS_AZIMUTH" html:"l=AOS_AZIMUTH,e=span"`
+	AOSElevation    float64  `json:"AOS_ELEVATION" xml:"AOS_ELEVATION" csv:"AOS_ELEVATION" html:"l=AOS_ELEVATION,e=span"`
+	AOSRange        float64  `json:"AOS_RANGE" xml:"AOS_RANGE" csv:"AOS_RANGE" html:"l=AOS_RANGE,e=span"`
+	AOSIllumination string   `json:"AOS_ILLUMINATION" xml:"AOS_ILLUMINATION" csv:"AOS_ILLUMINATION" html:"l=AOS_ILLUMINATION,e=span"`
+	Max             string   `json:"MAX" xml:"MAX" csv:"MAX" html:"l=MAX,e=span"`
+	MaxAzimuth      float64  `json:"MAX_AZIMUTH" xml:"MAX_AZIMUTH" csv:"MAX_AZIMUTH" html:"l=MAX_AZIMUTH,e=span"`
+	MaxElevation    float64  `json:"MAX_ELEVATION" xml:"MAX_ELEVATION" csv:"MAX_ELEVATION" html:"l=MAX_ELEVATION,e=span"`
+	MaxRange        float64  `json:"MAX_RANGE" xml:"MAX_RANGE" csv:"MAX_RANGE" html:"l=MAX_RANGE,e=span"`
+	MaxIllumination string   `json:"MAX_ILLUMINATION" xml:"MAX_ILLUMINATION" csv:"MAX_ILLUMINATION" html:"l=MAX_ILLUMINATION,e=span"`
+	LOS             string   `json:"LOS" xml:"LOS" csv:"LOS" html:"l=LOS,e=span"`
+	LOSAzimuth      float64  `json:"LOS_AZIMUTH" xml:"LOS_AZIMUTH" csv:"LOS_AZIMUTH" html:"l=LOS_AZIMUTH,e=span"`
+	LOSElevation    float64  `json:"LOS_ELEVATION" xml:"LOS_ELEVATION" csv:"LOS_ELEVATION" html:"l=LOS_ELEVATION,e=span"`
+	LOSRange        float64  `json:"LOS_RANGE" xml:"LOS_RANGE" csv:"LOS_RANGE" html:"l=LOS_RANGE,e=span"`
+	LOSIllumination string   `json:"LOS_ILLUMINATION" xml:"LOS_ILLUMINATION" csv:"LOS_ILLUMINATION" html:"l=LOS_ILLUMINATION,e=span"`
+	Duration        float64  `json:"DURATION" xml:"DURATION" csv:"DURATION" html:"l=DURATION,e=span"`
+}
+
+// Observation are the parameters of the passes command.
+type Observation struct {
+	// NoradCatIDs are the objects observed, or all of them if empty.
+	NoradCatIDs []string
+	// Observer is the location of the ground station over the WGS84 ellipsoid.
+	Observer sgp4.Geodetic
+	// MinElevation is the elevation, in degrees, over which an object is in a pass.
+	MinElevation float64
+	// Start and Stop are the window of time of the passes.
+	Start, Stop time.Time
+}
+
+func (o Observation) validate() error {
+	if o.Observer.Latitude < -90 || o.Observer.Latitude > 90 {
+		return fmt.Errorf("%w: latitude %g is not within [-90, 90]", ErrInvalidObserver, o.Observer.Latitude)
+	}
+	if o.Observer.Longitude < -180 || o.Observer.Longitude > 180 {
+		return fmt.Errorf("%w: longitude %g is not within [-180, 180]", ErrInvalidObserver, o.Observer.Longitude)
+	}
+	if o.MinElevation < -90 || o.MinElevation >= 90 {
+		return fmt.Errorf("%w: minimum elevation %g is not within [-90, 90)", ErrInvalidObserver, o.MinElevation)
+	}
+	if !o.Stop.After(o.Start) {
+		return fmt.Errorf("%w: stop %s is not after start %s", sgp4.ErrPassWindow, o.Stop, o.Start)
+	}
+	return nil
+}
+
+// ... predictPasses returns the passes over the observer of each object selected, with the newest of its element sets.
+// Objects which can't be propagated, e.g. they decay within the window, are logged and their passes until then are
+// returned.
+func predictPasses(tles []spacetrack.SpaceTrackTleUnit, o Observation) (Passes, error) {
+	passes := Passes{Items: []PassRow{}}
+
+	if err := o.validate(); err != nil {
+		return passes, err
+	}
+
+	units := selectObjects(tles, o.NoradCatIDs)
+	if len(units) == 0 {
+		return passes, fmt.Errorf("%w: none of the objects %v have element sets", ErrInvalidObserver, o.NoradCatIDs)
+	}
+
+	warnMissingObjects(units, o.NoradCatIDs)
+
+	for _, u := range units {
+		arr, err := objectPasses(u, o)
+		if err != nil {
+			L().Warn("predicting passes", zap.String("norad_cat_id", u.NoradCatId), zap.Error(err))
+		}
+
+		for _, p := range arr {
+			passes.Items = append(passes.Items, passRow(u, p))
+		}
+	}
+
+	sort.SliceStable(passes.Items, func(i, j int) bool {
+		return passes.Items[i].AOS < passes.Items[j].AOS
+	})
+
+	return passes, nil
+}
+
+func objectPasses(u spacetrack.SpaceTrackTleUnit, o Observation) ([]sgp4.Pass, error) {
+	e, err := sgp4.FromUnit(u)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := sgp4.New(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Passes(o.Observer, o.MinElevation, o.Start, o.Stop)
+}
+
+func passRow(u spacetrack.SpaceTrackTleUnit, p sgp4.Pass) PassRow {
+	return PassRow{
+		NoradCatID:      u.NoradCatId,
+		ObjectName:      u.ObjectName,
+		ObjectID:        u.ObjectId,
+		AOS:             p.AOS.Time.UTC().Format(ephemerisTimeLayout),
+		AOSAzimuth:      p.AOS.Azimuth,
+		AOSElevation:    p.AOS.Elevation,
+		AOSRange:        p.AOS.Range,
+		AOSIllumination: illumination(p.AOS),
+		Max:             p.Max.Time.UTC().Format(ephemerisTimeLayout),
+		MaxAzimuth:      p.Max.Azimuth,
+		MaxElevation:    p.Max.Elevation,
+		MaxRange:        p.Max.Range,
+		MaxIllumination: illumination(p.Max),
+		LOS:             p.LOS.Time.UTC().Format(ephemerisTimeLayout),
+		LOSAzimuth:      p.LOS.Azimuth,
+		LOSElevation:    p.LOS.Elevation,
+		LOSRange:        p.LOS.Range,
+		LOSIllumination: illumination(p.LOS),
+		Duration:        p.Duration().Seconds(),
+	}
+}
+
+func illumination(p sgp4.PassPoint) string {
+	if p.Sunlit {
+		return Sunlit
+	}
+	return Eclipse
+}
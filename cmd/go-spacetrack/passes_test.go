@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/MrTimeout/go-spacetrack/spacetrack/sgp4"
+	"github.com/stretchr/testify/assert"
+)
+
+var madrid = sgp4.Geodetic{Latitude: 40.4168, Longitude: -3.7038, Altitude: 0.667}
+
+func TestPredictPasses(t *testing.T) {
+	var (
+		issEpoch = time.Date(2008, 9, 20, 12, 25, 40, 104192000, time.UTC)
+		o        = Observation{Observer: madrid, MinElevation: 10, Start: issEpoch, Stop: issEpoch.Add(24 * time.Hour)}
+	)
+
+	t.Run("passes of each object sorted by aos", func(t *testing.T) {
+		got, err := predictPasses([]spacetrack.SpaceTrackTleUnit{vanguardTle, issTle}, o)
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, got.Items)
+
+		ids := map[string]bool{}
+		for i, p := range got.Items {
+			ids[p.NoradCatID] = true
+
+			assert.True(t, p.AOS < p.Max && p.Max < p.LOS, "pass %d", i)
+			assert.Greater(t, p.Duration, 0.0, "pass %d", i)
+			assert.GreaterOrEqual(t, p.MaxElevation, 10.0, "pass %d", i)
+			assert.Contains(t, []string{Sunlit, Eclipse}, p.MaxIllumination, "pass %d", i)
+
+			if i > 0 {
+				assert.LessOrEqual(t, got.Items[i-1].AOS, p.AOS, "pass %d", i)
+			}
+		}
+
+		assert.Equal(t, map[string]bool{"5": true, "25544": true}, ids)
+	})
+
+	t.Run("objects selected", func(t *testing.T) {
+		got, err := predictPasses([]spacetrack.SpaceTrackTleUnit{vanguardTle, issTle}, Observation{NoradCatIDs: []string{"25544"}, Observer: o.Observer, MinElevation: o.MinElevation, Start: o.Start, Stop: o.Stop})
+
+		assert.Nil(t, err)
+		for _, p := range got.Items {
+			assert.Equal(t, "25544", p.NoradCatID)
+			assert.Equal(t, "ISS (ZARYA)", p.ObjectName)
+		}
+	})
+
+	t.Run("no element sets of the objects", func(t *testing.T) {
+		_, err := predictPasses([]spacetrack.SpaceTrackTleUnit{vanguardTle}, Observation{NoradCatIDs: []string{"25544"}, Observer: o.Observer, Start: o.Start, Stop: o.Stop})
+		assert.ErrorIs(t, err, ErrInvalidObserver)
+	})
+}
+
+func TestObservationValidate(t *testing.T) {
+	var (
+		start = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		stop  = start.Add(24 * time.Hour)
+	)
+
+	for _, each := range []struct {
+		description string
+		o           Observation
+		wantErr     error
+	}{
+		{description: "valid observation", o: Observation{Observer: madrid, MinElevation: 10, Start: start, Stop: stop}},
+		{description: "latitude out of range", o: Observation{Observer: sgp4.Geodetic{Latitude: 91}, Start: start, Stop: stop}, wantErr: ErrInvalidObserver},
+		{description: "longitude out of range", o: Observation{Observer: sgp4.Geodetic{Longitude: -181}, Start: start, Stop: stop}, wantErr: ErrInvalidObserver},
+		{description: "minimum elevation out of range", o: Observation{Observer: madrid, MinElevation: 90, Start: start, Stop: stop}, wantErr: ErrInvalidObserver},
+		{description: "stop before start", o: Observation{Observer: madrid, Start: stop, Stop: start}, wantErr: sgp4.ErrPassWindow},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.ErrorIs(t, each.o.validate(), each.wantErr)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+)
+
+// ErrNoRecords is returned when reading the records of a folder which has none of the files written by the persisters.
+var ErrNoRecords = errors.New("no records found")
+
+// ... root is the root element of the records of any class, like spacetrack.SpaceTrackTle, whatever its xml name is.
+type root[T spacetrack.Unit] struct {
+	XMLName xml.Name
+	Items   []T `json:"item" xml:"item" html:"item"`
+}
+
+// ... readRecords reads the records of a file, or of all the files of the folder of an execution, e.g.
+// ${work_dir}/spacetrack-tle/1672531200, in any of the formats written by the persisters, by their extension. If the
+// format is passed, the files are read in it, and only the files of the folder with its extension are read.
+func readRecords[T spacetrack.Unit](path string, f persist.Format) ([]T, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+
+	if info.IsDir() {
+		if files, err = persist.RecordFiles(path); err != nil {
+			return nil, err
+		}
+
+		if f != "" {
+			files = withExt(files, f)
+		}
+
+		if len(files) == 0 {
+			return nil, fmt.Errorf("%w in %s", ErrNoRecords, path)
+		}
+	}
+
+	output := []T{}
+
+	for _, file := range files {
+		var r root[T]
+
+		if f == "" {
+			err = persist.ReadFile(file, &r)
+		} else {
+			err = persist.ReadFileAs(file, f, &r)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, r.Items...)
+	}
+
+	return output, nil
+}
+
+// ... withExt returns the files with the extension of the format.
+func withExt(files []string, f persist.Format) []string {
+	var output []string
+
+	for _, file := range files {
+		if filepath.Ext(file) == "."+f.String() {
+			output = append(output, file)
+		}
+	}
+
+	return output
+}
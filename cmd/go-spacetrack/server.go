@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// ... maximum amount of time to wait for the in-flight requests when the server is stopped.
+const serverShutdownTimeout = 5 * time.Second
+
+// Server is the http listener which exposes the metrics and the health of the program.
+type Server struct {
+	// Address is where the server listens, e.g. :9090 or 127.0.0.1:9090
+	Address string `json:"address" yaml:"address" mapstructure:"address"`
+	// ReadyIntervals is the number of intervals a job can go without a successful run before /readyz fails. If zero, 3 is used.
+	ReadyIntervals int `json:"ready_intervals" yaml:"ready_intervals" mapstructure:"ready_intervals"`
+}
+
+// ... newServeMux returns the handlers exposed by the server.
+func (a *app) newServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{Registry: metricsRegistry}))
+	mux.HandleFunc("/healthz", a.healthz)
+	mux.HandleFunc("/readyz", a.readyz)
+
+	return mux
+}
+
+// ... startServer listens on the address, returning an error if it can't, and serves until the context is done.
+// It returns the address it is listening on, which is useful when the port is 0.
+func (a *app) startServer(ctx context.Context, address string) (net.Addr, error) {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	srv := &http.Server{Handler: a.newServeMux(), ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			Warn("stopping server", zap.Error(err))
+		}
+	}()
+
+	go func() {
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			Error("server stopped", zap.Error(err))
+		}
+	}()
+
+	Info("server listening", zap.String("address", l.Addr().String()))
+
+	return l.Addr(), nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	t.Run("metrics are exposed until the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		addr, err := newApp().startServer(ctx, "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := http.Get("http://" + addr.String() + "/metrics")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Contains(t, string(body), "spacetrack_rate_limiter_wait_seconds")
+		assert.Contains(t, string(body), "go_goroutines")
+
+		cancel()
+		assert.Eventually(t, func() bool {
+			res, err := http.Get("http://" + addr.String() + "/metrics")
+			if err == nil {
+				res.Body.Close()
+			}
+			return err != nil
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("address in use is an error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		addr, err := newApp().startServer(ctx, "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = newApp().startServer(ctx, addr.String())
+
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+)
+
+// ... name of the file, inside the work dir of each job, which keeps the high-water marks of the jobs.
+const stateFile = ".spacetrack-state.json"
+
+// ErrState is returned when the state file can't be read or written, e.g. because it is corrupt.
+var ErrState = errors.New("state file")
+
+// ... stateMu serializes the read, modify and write of the state files, which may be shared by several jobs.
+var stateMu sync.Mutex
+
+// State are the high-water marks of the jobs persisting under a work dir: the newest cursor, see
+// spacetrack.RestCall.CursorField, fetched by each rest call of each job, e.g. {"jobs":{"iss":{"tle":"235813705"}}}
+type State struct {
+	Jobs map[string]map[spacetrack.RestCall]string `json:"jobs"`
+}
+
+// ... statePath returns the path of the state file of the work dir.
+func statePath(workDir string) string {
+	return filepath.Join(workDir, stateFile)
+}
+
+// ... readState reads the state file of the work dir, being empty if it doesn't exist yet.
+func readState(workDir string) (State, error) {
+	s := State{Jobs: make(map[string]map[spacetrack.RestCall]string)}
+
+	content, err := os.ReadFile(statePath(workDir))
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("%w: %v", ErrState, err)
+	}
+
+	if err := json.Unmarshal(content, &s); err != nil {
+		return s, fmt.Errorf("%w: %s is corrupt, fix or remove it, or run without --incremental: %v", ErrState, statePath(workDir), err)
+	}
+
+	if s.Jobs == nil {
+		s.Jobs = make(map[string]map[spacetrack.RestCall]string)
+	}
+
+	return s, nil
+}
+
+// ... write replaces the state file of the work dir, see writeFileAtomic.
+func (s State) write(workDir string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrState, err)
+	}
+
+	if err := writeFileAtomic(statePath(workDir), content); err != nil {
+		return fmt.Errorf("%w: %v", ErrState, err)
+	}
+
+	return nil
+}
+
+// ... writeFileAtomic replaces the file through a temporary one in the same dir, so it is never left half written.
+func writeFileAtomic(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close() //nolint:errcheck
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// ... highWaterMark returns the newest cursor fetched by the rest call of the job, being empty if there is none.
+func highWaterMark(workDir, job string, rc spacetrack.RestCall) (string, error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s, err := readState(workDir)
+	if err != nil {
+		return "", err
+	}
+
+	return s.Jobs[job][rc], nil
+}
+
+// ... saveHighWaterMark stores the cursor as the high-water mark of the rest call of the job, unless the stored one is
+// newer, so the marks never move backwards.
+func saveHighWaterMark(workDir, job string, rc spacetrack.RestCall, mark string) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s, err := readState(workDir)
+	if err != nil {
+		return err
+	}
+
+	if spacetrack.CompareCursors(mark, s.Jobs[job][rc]) <= 0 {
+		return nil
+	}
+
+	if s.Jobs[job] == nil {
+		s.Jobs[job] = make(map[spacetrack.RestCall]string)
+	}
+	s.Jobs[job][rc] = mark
+
+	return s.write(workDir)
+}
+
+// ... digestsPath returns the path of the digests of the last records fetched by the rest call of the job.
+func digestsPath(workDir, job string, rc spacetrack.RestCall) string {
+	return filepath.Join(workDir, ".spacetrack-digests-"+job+"-"+rc.String()+".json")
+}
+
+// ... readDigests reads the digests of the last records fetched by the rest call of the job, being empty, but not nil,
+// if there are none yet.
+func readDigests(workDir, job string, rc spacetrack.RestCall) (spacetrack.Digests, error) {
+	d := make(spacetrack.Digests)
+
+	content, err := os.ReadFile(digestsPath(workDir, job, rc))
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+	if err != nil {
+		return d, fmt.Errorf("%w: %v", ErrState, err)
+	}
+
+	if err := json.Unmarshal(content, &d); err != nil {
+		return d, fmt.Errorf("%w: %s is corrupt, fix or remove it: %v", ErrState, digestsPath(workDir, job, rc), err)
+	}
+
+	if d == nil {
+		d = make(spacetrack.Digests)
+	}
+
+	return d, nil
+}
+
+// ... writeDigests replaces the digests of the last records fetched by the rest call of the job.
+func writeDigests(workDir, job string, rc spacetrack.RestCall, d spacetrack.Digests) error {
+	content, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrState, err)
+	}
+
+	if err := writeFileAtomic(digestsPath(workDir, job, rc), content); err != nil {
+		return fmt.Errorf("%w: %v", ErrState, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState(t *testing.T) {
+	t.Run("missing state file has no marks", func(t *testing.T) {
+		mark, err := highWaterMark(t.TempDir(), "iss", spacetrack.Tle)
+
+		assert.Nil(t, err)
+		assert.Empty(t, mark)
+	})
+
+	t.Run("marks are kept per job and rest call", func(t *testing.T) {
+		dir := t.TempDir()
+
+		assert.Nil(t, saveHighWaterMark(dir, "iss", spacetrack.Tle, "235813705"))
+		assert.Nil(t, saveHighWaterMark(dir, "iss", spacetrack.Decay, "2022-07-30 17:22:00"))
+		assert.Nil(t, saveHighWaterMark(dir, "conjunctions", spacetrack.Cdm, "436178920"))
+
+		s, err := readState(dir)
+
+		assert.Nil(t, err)
+		assert.Equal(t, State{Jobs: map[string]map[spacetrack.RestCall]string{
+			"iss":          {spacetrack.Tle: "235813705", spacetrack.Decay: "2022-07-30 17:22:00"},
+			"conjunctions": {spacetrack.Cdm: "436178920"},
+		}}, s)
+	})
+
+	t.Run("marks never move backwards", func(t *testing.T) {
+		dir := t.TempDir()
+
+		assert.Nil(t, saveHighWaterMark(dir, "iss", spacetrack.Tle, "1000"))
+		assert.Nil(t, saveHighWaterMark(dir, "iss", spacetrack.Tle, "999"))
+
+		mark, err := highWaterMark(dir, "iss", spacetrack.Tle)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "1000", mark)
+	})
+
+	t.Run("no temporary files are left", func(t *testing.T) {
+		dir := t.TempDir()
+
+		assert.Nil(t, saveHighWaterMark(dir, "iss", spacetrack.Tle, "1"))
+
+		files, err := os.ReadDir(dir)
+		assert.Nil(t, err)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("corrupt state file is an error", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, stateFile), []byte("{\"jobs\":"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := highWaterMark(dir, "iss", spacetrack.Tle)
+		assert.ErrorIs(t, err, ErrState)
+		assert.ErrorIs(t, saveHighWaterMark(dir, "iss", spacetrack.Tle, "1"), ErrState)
+	})
+}
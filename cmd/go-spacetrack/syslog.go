@@ -0,0 +1,358 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// UDP sends each log entry in one datagram.
+	UDP = "udp"
+	// TCP sends the log entries framed by their length, see RFC 6587.
+	TCP = "tcp"
+	// Unix sends the log entries to a local socket, e.g. /dev/log
+	Unix = "unix"
+
+	// ... default APP-NAME of the syslog messages.
+	defaultSyslogTag = "go-spacetrack"
+	// ... TIMESTAMP of the syslog messages, RFC 3339 with microseconds, see RFC 5424 section 6.2.3.
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+	// ... the APP-NAME field can't be longer than this, see RFC 5424 section 6.
+	syslogTagMaxLen = 48
+	// ... maximum time to connect to the server, or to send a message to it, so a server which doesn't answer never
+	// blocks the logs for long.
+	syslogTimeout = 5 * time.Second
+	// ... the writer doesn't dial again a server which has gone away until this time has passed, doubling it after each
+	// failed dial up to syslogMaxBackoff, so the logs aren't blocked dialing on each entry.
+	syslogMinBackoff = time.Second
+	syslogMaxBackoff = time.Minute
+)
+
+var (
+	// ErrSyslogNetworkNotAllowed is used to indicate that the syslog appender only supports udp, tcp and unix sockets.
+	ErrSyslogNetworkNotAllowed = errors.New("syslog network not allowed")
+	// ErrSyslogFacilityNotAllowed is used to indicate that the syslog facility is unknown.
+	ErrSyslogFacilityNotAllowed = errors.New("syslog facility not allowed")
+	// ErrSyslogUnavailable is returned when the messages are dropped because the server has gone away and the writer
+	// is waiting before dialing it again.
+	ErrSyslogUnavailable = errors.New("syslog server unavailable")
+
+	// SyslogNetworkValues are the networks allowed in the configuration.
+	SyslogNetworkValues = []string{UDP, TCP, Unix}
+	// SyslogFacilityValues are the facilities allowed in the configuration.
+	SyslogFacilityValues = []string{"user", "daemon", "local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7"}
+
+	// ... codes of the facilities, see RFC 5424 section 6.2.1.
+	syslogFacilities = map[string]int{
+		"user": 1, "daemon": 3,
+		"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+	}
+	// ... severities of the zap levels, see RFC 5424 section 6.2.1.
+	syslogSeverities = map[zapcore.Level]int{
+		zapcore.FatalLevel:  0,
+		zapcore.PanicLevel:  1,
+		zapcore.DPanicLevel: 2,
+		zapcore.ErrorLevel:  3,
+		zapcore.WarnLevel:   4,
+		zapcore.InfoLevel:   6,
+		zapcore.DebugLevel:  7,
+	}
+)
+
+// SyslogAppender is the struct that allows to send the logs to a syslog server using the RFC 5424 format.
+type SyslogAppender struct {
+	LoggerFileLevel LoggerLevel `json:"level" yaml:"level" mapstructure:"level"`
+	// Network is the transport to the server: udp, tcp or unix.
+	Network SyslogNetwork `json:"network" yaml:"network" mapstructure:"network"`
+	// Address is host:port for udp and tcp, or the path of the socket for unix, e.g. /dev/log
+	Address string `json:"address" yaml:"address" mapstructure:"address"`
+	// Facility of the messages: user, daemon or local0 to local7. If empty, user is used.
+	Facility SyslogFacility `json:"facility" yaml:"facility" mapstructure:"facility"`
+	// Tag is the APP-NAME of the messages. If empty, go-spacetrack is used.
+	Tag string `json:"tag" yaml:"tag" mapstructure:"tag"`
+}
+
+// NewSyslogAppender returns a SyslogAppender with values passed as parameters
+func NewSyslogAppender(loggerLevel LoggerLevel, network SyslogNetwork, address string) SyslogAppender {
+	return SyslogAppender{
+		LoggerFileLevel: loggerLevel,
+		Network:         network,
+		Address:         address,
+	}
+}
+
+// ... core encodes each entry as json, leaving the time and the level to the header of the syslog message.
+func (sa SyslogAppender) core(config zapcore.EncoderConfig) (zapcore.Core, io.Closer, error) {
+	w, err := newSyslogWriter(sa)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: syslog %s %s: %v", ErrLoggerAppender, sa.Network, sa.Address, err)
+	}
+
+	config.TimeKey, config.LevelKey = "", ""
+
+	return &syslogCore{
+		LevelEnabler: sa.LoggerFileLevel.ToZapLevel(),
+		enc:          zapcore.NewJSONEncoder(config),
+		w:            w,
+	}, w, nil
+}
+
+func (sa SyslogAppender) tag() string {
+	if sa.Tag == "" {
+		return defaultSyslogTag
+	}
+	return sa.Tag
+}
+
+// SyslogNetwork is the transport used to reach the syslog server.
+type SyslogNetwork string
+
+// Set tries to set the SyslogNetwork returning error if the input is incorrect
+func (sn *SyslogNetwork) Set(input string) error {
+	switch strings.ToLower(input) {
+	case UDP:
+		*sn = UDP
+	case TCP:
+		*sn = TCP
+	case Unix:
+		*sn = Unix
+	default:
+		return ErrSyslogNetworkNotAllowed
+	}
+	return nil
+}
+
+// UnmarshalText lowercases the network of the configuration, leaving its validation to Config.Validate, so it is
+// reported along with the rest of problems.
+func (sn *SyslogNetwork) UnmarshalText(input []byte) error {
+	*sn = SyslogNetwork(strings.ToLower(string(input)))
+	return nil
+}
+
+// SyslogFacility is the facility of the syslog messages, which tells the server what kind of program is logging.
+type SyslogFacility string
+
+// Set tries to set the SyslogFacility returning error if the input is incorrect
+func (sf *SyslogFacility) Set(input string) error {
+	if _, ok := syslogFacilities[strings.ToLower(input)]; !ok {
+		return ErrSyslogFacilityNotAllowed
+	}
+	*sf = SyslogFacility(strings.ToLower(input))
+	return nil
+}
+
+// UnmarshalText lowercases the facility of the configuration, leaving its validation to Config.Validate.
+func (sf *SyslogFacility) UnmarshalText(input []byte) error {
+	*sf = SyslogFacility(strings.ToLower(string(input)))
+	return nil
+}
+
+func (sf SyslogFacility) code() int {
+	if code, ok := syslogFacilities[string(sf)]; ok {
+		return code
+	}
+	return syslogFacilities["user"]
+}
+
+// ... syslogCore is a zapcore.Core which writes each entry as one syslog message.
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	w   *syslogWriter
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for i := range fields {
+		fields[i].AddTo(enc)
+	}
+
+	return &syslogCore{LevelEnabler: c.LevelEnabler, enc: enc, w: c.w}
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	return c.w.write(ent.Level, ent.Time, bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
+
+// ... syslogWriter keeps the connection to the syslog server, dialing again if the server has gone away.
+type syslogWriter struct {
+	mu       sync.Mutex
+	appender SyslogAppender
+	network  string
+	conn     net.Conn
+	hostname string
+	pid      int
+	// ... closed is set when the logger is replaced, so the writer doesn't dial again.
+	closed bool
+	// ... backoff is the time to wait after the last failed dial, and retryAt when the server is dialed again.
+	backoff time.Duration
+	retryAt time.Time
+}
+
+func newSyslogWriter(sa SyslogAppender) (*syslogWriter, error) {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	// ... appenders built in code, instead of decoded, may have uppercase values.
+	sa.Network = SyslogNetwork(strings.ToLower(string(sa.Network)))
+	sa.Facility = SyslogFacility(strings.ToLower(string(sa.Facility)))
+
+	w := &syslogWriter{appender: sa, hostname: hostname, pid: os.Getpid()}
+	if err := w.dial(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// ... dial connects to the server. Local syslog daemons usually listen on datagram sockets, so unix tries them first,
+// like the log/syslog package does.
+func (w *syslogWriter) dial() error {
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	switch w.appender.Network {
+	case Unix:
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err = net.DialTimeout(network, w.appender.Address, syslogTimeout); err == nil {
+				w.network = network
+				break
+			}
+		}
+	default:
+		w.network = string(w.appender.Network)
+		conn, err = net.DialTimeout(w.network, w.appender.Address, syslogTimeout)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.conn = conn
+	return nil
+}
+
+// ... write sends the message, retrying once with a new connection if it fails. While the server is unavailable, see
+// redial, the message is dropped.
+func (w *syslogWriter) write(level zapcore.Level, t time.Time, msg []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+
+	frame := w.frame(level, t, msg)
+
+	if w.conn != nil {
+		if err := w.send(frame); err == nil {
+			return nil
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+
+	if err := w.redial(); err != nil {
+		return err
+	}
+
+	return w.send(frame)
+}
+
+func (w *syslogWriter) send(frame []byte) error {
+	if err := w.conn.SetWriteDeadline(time.Now().Add(syslogTimeout)); err != nil {
+		return err
+	}
+
+	_, err := w.conn.Write(frame)
+	return err
+}
+
+// ... redial dials the server again, unless the last dial failed less than backoff ago.
+func (w *syslogWriter) redial() error {
+	if time.Now().Before(w.retryAt) {
+		return ErrSyslogUnavailable
+	}
+
+	if err := w.dial(); err != nil {
+		w.backoff *= 2
+		if w.backoff < syslogMinBackoff {
+			w.backoff = syslogMinBackoff
+		}
+		if w.backoff > syslogMaxBackoff {
+			w.backoff = syslogMaxBackoff
+		}
+		w.retryAt = time.Now().Add(w.backoff)
+
+		return err
+	}
+
+	w.backoff, w.retryAt = 0, time.Time{}
+
+	return nil
+}
+
+func (w *syslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
+
+// ... frame builds the RFC 5424 message, <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG, framing it
+// by its length on tcp (RFC 6587) and by a new line on unix stream sockets.
+func (w *syslogWriter) frame(level zapcore.Level, t time.Time, msg []byte) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("<" + strconv.Itoa(w.appender.Facility.code()*8+syslogSeverities[level]) + ">1 ")
+	buf.WriteString(t.Format(syslogTimeFormat) + " " + w.hostname + " " + w.appender.tag() + " " + strconv.Itoa(w.pid) + " - - ")
+	buf.Write(msg)
+
+	switch w.network {
+	case TCP:
+		return append([]byte(strconv.Itoa(buf.Len())+" "), buf.Bytes()...)
+	case Unix:
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ... syslogMessage matches the RFC 5424 messages, capturing PRI, APP-NAME and MSG.
+var syslogMessage = regexp.MustCompile(`^<(\d+)>1 \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}(?:Z|[+-]\d{2}:\d{2}) \S+ (\S+) \d+ - - (.*)$`)
+
+// ... listenSyslog starts a local syslog server, returning its address and a function which reads the next message.
+func listenSyslog(t *testing.T, network SyslogNetwork) (string, func() string) {
+	switch network {
+	case TCP:
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { l.Close() })
+
+		conns := make(chan net.Conn, 1)
+		go func() {
+			if conn, err := l.Accept(); err == nil {
+				conns <- conn
+			}
+		}()
+
+		var r *bufio.Reader
+		return l.Addr().String(), func() string {
+			if r == nil {
+				conn := <-conns
+				t.Cleanup(func() { conn.Close() })
+				r = bufio.NewReader(conn)
+			}
+
+			// ... octet counting framing, MSG-LEN SP SYSLOG-MSG
+			length, err := r.ReadString(' ')
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			n, err := strconv.Atoi(strings.TrimSpace(length))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				t.Fatal(err)
+			}
+
+			return string(msg)
+		}
+	default:
+		var (
+			pc  net.PacketConn
+			err error
+		)
+
+		if network == Unix {
+			// ... unix socket paths are short, so we don't use t.TempDir, which is named after the test.
+			dir, tmpErr := os.MkdirTemp("", "syslog")
+			if tmpErr != nil {
+				t.Fatal(tmpErr)
+			}
+			t.Cleanup(func() { os.RemoveAll(dir) })
+
+			pc, err = net.ListenPacket("unixgram", filepath.Join(dir, "log.sock"))
+		} else {
+			pc, err = net.ListenPacket("udp", "127.0.0.1:0")
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { pc.Close() })
+
+		return pc.LocalAddr().String(), func() string {
+			buf := make([]byte, 64*1024)
+
+			if err := pc.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+				t.Fatal(err)
+			}
+
+			n, _, err := pc.ReadFrom(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return string(buf[:n])
+		}
+	}
+}
+
+func TestSyslogAppender(t *testing.T) {
+	for _, network := range []SyslogNetwork{UDP, TCP, Unix} {
+		t.Run("messages are sent over "+string(network), func(t *testing.T) {
+			address, read := listenSyslog(t, network)
+
+			l := Logger{
+				ConsoleAppender: NewConsoleAppender(FatalLevel),
+				SyslogAppenders: []SyslogAppender{NewSyslogAppender(InfoLevel, network, address)},
+			}
+
+			zl, err := l.Tee()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			zl.Debug("discarded by the level")
+			zl.Warn("fetching tle", zap.String("rest_call", "tle"), zap.String("password", testPassword))
+
+			matches := syslogMessage.FindStringSubmatch(read())
+			if assert.Len(t, matches, 4) {
+				// ... facility user (1) * 8 + severity warning (4)
+				assert.Equal(t, "12", matches[1])
+				assert.Equal(t, defaultSyslogTag, matches[2])
+				assert.Contains(t, matches[3], `"fetching tle"`)
+				assert.Contains(t, matches[3], `"rest_call":"tle"`)
+				assert.NotContains(t, matches[3], testPassword)
+			}
+		})
+	}
+
+	t.Run("facility and tag are configurable", func(t *testing.T) {
+		address, read := listenSyslog(t, UDP)
+
+		sa := NewSyslogAppender(DebugLevel, "UDP", address)
+		sa.Facility, sa.Tag = "LOCAL3", "spacetrack-iss"
+
+		zl, err := Logger{ConsoleAppender: NewConsoleAppender(FatalLevel), SyslogAppenders: []SyslogAppender{sa}}.Tee()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		zl.Error("something went wrong")
+
+		matches := syslogMessage.FindStringSubmatch(read())
+		if assert.Len(t, matches, 4) {
+			// ... facility local3 (19) * 8 + severity error (3)
+			assert.Equal(t, "155", matches[1])
+			assert.Equal(t, "spacetrack-iss", matches[2])
+		}
+	})
+
+	t.Run("server which can't be reached is an error", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		address := l.Addr().String()
+		l.Close()
+
+		_, err = Logger{SyslogAppenders: []SyslogAppender{NewSyslogAppender(InfoLevel, TCP, address)}}.Tee()
+
+		assert.ErrorIs(t, err, ErrLoggerAppender)
+	})
+}
+
+func TestSyslogWriterBackoff(t *testing.T) {
+	address, _ := listenSyslog(t, UDP)
+
+	w, err := newSyslogWriter(NewSyslogAppender(InfoLevel, TCP, "127.0.0.1:1"))
+	assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+	assert.Nil(t, w)
+
+	w = &syslogWriter{appender: NewSyslogAppender(InfoLevel, TCP, "127.0.0.1:1")}
+
+	assert.NotNil(t, w.write(zapcore.InfoLevel, time.Now(), []byte("first")))
+	assert.Equal(t, syslogMinBackoff, w.backoff)
+
+	assert.ErrorIs(t, w.write(zapcore.InfoLevel, time.Now(), []byte("second")), ErrSyslogUnavailable, "the server isn't dialed again until the backoff has passed")
+
+	w.retryAt = time.Now()
+	assert.NotNil(t, w.write(zapcore.InfoLevel, time.Now(), []byte("third")))
+	assert.Equal(t, 2*syslogMinBackoff, w.backoff)
+
+	w.appender.Network, w.appender.Address, w.retryAt = UDP, address, time.Now()
+	assert.Nil(t, w.write(zapcore.InfoLevel, time.Now(), []byte("fourth")))
+	assert.Zero(t, w.backoff, "the backoff is reset once the server is dialed")
+}
+
+func TestSyslogUnmarshalText(t *testing.T) {
+	var (
+		network  SyslogNetwork
+		facility SyslogFacility
+	)
+
+	assert.Nil(t, network.UnmarshalText([]byte("TCP")))
+	assert.Nil(t, facility.UnmarshalText([]byte("LOCAL0")))
+
+	assert.Equal(t, SyslogNetwork(TCP), network)
+	assert.Equal(t, SyslogFacility("local0"), facility)
+	assert.Equal(t, 16, facility.code())
+}
+
+func TestSyslogSeverities(t *testing.T) {
+	for level := zapcore.DebugLevel; level <= zapcore.FatalLevel; level++ {
+		_, ok := syslogSeverities[level]
+		assert.True(t, ok, "level %s has no severity", level)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+const (
+	// NoneExporter disables the tracing, the default one.
+	NoneExporter = "none"
+	// StdoutExporter prints the spans to stdout, useful to debug.
+	StdoutExporter = "stdout"
+	// OtlpHttpExporter sends the spans to an OTLP collector over http.
+	OtlpHttpExporter = "otlp_http"
+	// OtlpGrpcExporter sends the spans to an OTLP collector over grpc.
+	OtlpGrpcExporter = "otlp_grpc"
+
+	// ... default name of the service of the spans.
+	defaultServiceName = "go-spacetrack"
+)
+
+var (
+	// ErrTracingExporterNotAllowed is used to indicate that the exporter of the spans is unknown.
+	ErrTracingExporterNotAllowed = errors.New("tracing exporter not allowed")
+
+	// TracingExporterValues are the exporters allowed in the configuration.
+	TracingExporterValues = []string{NoneExporter, StdoutExporter, OtlpHttpExporter, OtlpGrpcExporter}
+)
+
+// Tracing configures the OpenTelemetry spans of each run: jobs, authentication, requests, parsing and persistence.
+type Tracing struct {
+	// Exporter of the spans: none, stdout, otlp_http or otlp_grpc. If empty, tracing is disabled.
+	Exporter TracingExporter `json:"exporter" yaml:"exporter" mapstructure:"exporter"`
+	// Endpoint of the OTLP collector, e.g. localhost:4318 for http or localhost:4317 for grpc. If empty, the
+	// OTEL_EXPORTER_OTLP_ENDPOINT env var or the default endpoint of the exporter is used.
+	Endpoint string `json:"endpoint" yaml:"endpoint" mapstructure:"endpoint"`
+	// Insecure disables TLS with the OTLP collector.
+	Insecure bool `json:"insecure" yaml:"insecure" mapstructure:"insecure"`
+	// SampleRatio is the fraction of runs which are traced, from 0 to 1.
+	SampleRatio float64 `json:"sample_ratio" yaml:"sample_ratio" mapstructure:"sample_ratio"`
+	// ServiceName is the name of the service of the spans. If empty, go-spacetrack is used.
+	ServiceName string `json:"service_name" yaml:"service_name" mapstructure:"service_name"`
+}
+
+// TracingExporter is where the spans are sent.
+type TracingExporter string
+
+// Set tries to set the TracingExporter returning error if the input is incorrect
+func (te *TracingExporter) Set(input string) error {
+	switch strings.ToLower(input) {
+	case NoneExporter:
+		*te = NoneExporter
+	case StdoutExporter:
+		*te = StdoutExporter
+	case OtlpHttpExporter:
+		*te = OtlpHttpExporter
+	case OtlpGrpcExporter:
+		*te = OtlpGrpcExporter
+	default:
+		return ErrTracingExporterNotAllowed
+	}
+	return nil
+}
+
+// ... exporter returns the exporter of the spans, being nil if tracing is disabled.
+func (t Tracing) exporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	switch t.Exporter {
+	case StdoutExporter:
+		return stdouttrace.New()
+	case OtlpHttpExporter:
+		var opts []otlptracehttp.Option
+		if t.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(t.Endpoint))
+		}
+		if t.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case OtlpGrpcExporter:
+		var opts []otlptracegrpc.Option
+		if t.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(t.Endpoint))
+		}
+		if t.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	}
+
+	return nil, nil
+}
+
+// ... setupTracing registers the global tracer provider with the exporter of the configuration, returning the function
+// which flushes the pending spans. The exporter is only read at startup.
+func setupTracing(ctx context.Context, t Tracing) (func(context.Context) error, error) {
+	exporter, err := t.exporter(ctx)
+	if err != nil || exporter == nil {
+		return func(context.Context) error { return nil }, err
+	}
+
+	serviceName := t.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(t.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
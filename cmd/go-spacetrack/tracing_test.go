@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// ... recordSpans replaces the global tracer provider with one which keeps the spans in memory.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	var (
+		exporter = tracetest.NewInMemoryExporter()
+		old      = otel.GetTracerProvider()
+	)
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(old)
+	})
+
+	return exporter
+}
+
+// ... spanParents maps the name of each span to the name of its parent, being empty for the root ones.
+func spanParents(spans tracetest.SpanStubs) map[string]string {
+	var (
+		names   = make(map[string]string, len(spans))
+		parents = make(map[string]string, len(spans))
+	)
+
+	for _, span := range spans {
+		names[span.SpanContext.SpanID().String()] = span.Name
+	}
+
+	for _, span := range spans {
+		parents[span.Name] = names[span.Parent.SpanID().String()]
+	}
+
+	return parents
+}
+
+func TestTracing(t *testing.T) {
+	t.Run("spans of a job are nested under the job", func(t *testing.T) {
+		var (
+			body = `[{"NORAD_CAT_ID":"25544","OBJECT_NAME":"ISS (ZARYA)"}]`
+			job  = Job{Name: "traced", RestCall: spacetrack.Decay, Persister: persist.OneFile, Format: persist.Xml, WorkDir: filepath.Join(t.TempDir(), "traced")}
+		)
+
+		exporter := recordSpans(t)
+		a, _ := newTestApp(http.StatusOK, body)
+
+		assert.Nil(t, a.runJob(context.Background(), job))
+
+		assert.Equal(t, map[string]string{
+			"spacetrack.job":     "",
+			"spacetrack.fetch":   "spacetrack.job",
+			"spacetrack.auth":    "spacetrack.fetch",
+			"spacetrack.request": "spacetrack.fetch",
+			"spacetrack.parse":   "spacetrack.fetch",
+			"spacetrack.persist": "spacetrack.fetch",
+			"spacetrack.marshal": "spacetrack.persist",
+		}, spanParents(exporter.GetSpans()))
+	})
+
+	t.Run("failed jobs are recorded as errors", func(t *testing.T) {
+		exporter := recordSpans(t)
+		a, _ := newTestApp(http.StatusInternalServerError, `oops`)
+
+		err := a.runJob(context.Background(), Job{Name: "broken", RestCall: spacetrack.Tle, Format: persist.Json, WorkDir: t.TempDir()})
+		assert.ErrorIs(t, err, ErrJobFailed)
+
+		for _, span := range exporter.GetSpans() {
+			if span.Name != "spacetrack.parse" && span.Name != "spacetrack.persist" {
+				assert.Equal(t, codes.Error, span.Status.Code, span.Name)
+			}
+		}
+	})
+
+	t.Run("none exporter doesn't register a tracer provider", func(t *testing.T) {
+		old := otel.GetTracerProvider()
+
+		shutdown, err := setupTracing(context.Background(), Tracing{Exporter: NoneExporter})
+
+		assert.Nil(t, err)
+		assert.Nil(t, shutdown(context.Background()))
+		assert.Equal(t, old, otel.GetTracerProvider())
+	})
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+)
+
+// ErrInvalidConfig is the error matched by ValidationError, so callers can use errors.Is without caring about the details.
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// FieldError is a problem found in one field of the configuration, located by its yaml path, e.g. logger.file_appenders[0].level
+type FieldError struct {
+	Path string
+	Msg  string
+}
+
+func (fe FieldError) Error() string {
+	return fe.Path + ": " + fe.Msg
+}
+
+// ValidationError aggregates all the problems found while validating the configuration, so the user can fix all of them at once.
+type ValidationError []FieldError
+
+func (ve ValidationError) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString(ErrInvalidConfig.Error() + ":")
+	for i := range ve {
+		sb.WriteString("\n  - " + ve[i].Error())
+	}
+
+	return sb.String()
+}
+
+// Is allows to use errors.Is(err, ErrInvalidConfig)
+func (ve ValidationError) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+// ... validator collects field errors while walking the configuration.
+type validator struct {
+	errs ValidationError
+}
+
+func (v *validator) add(path, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(path, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(path, "is required")
+	}
+}
+
+// ... oneOf checks the value using the Set method of the flag types, which are the ones parsing the user input.
+// Empty values are allowed, because they mean that the default one must be used.
+func (v *validator) oneOf(path, value string, set func(string) error, allowed []string) {
+	if value == "" {
+		return
+	}
+
+	if err := set(value); err != nil {
+		v.add(path, "unknown value %q, allowed values are %s", value, strings.Join(allowed, ", "))
+	}
+}
+
+// ... duration checks that the value is a positive duration, e.g. 30m, 1h
+func (v *validator) duration(path, value string) {
+	if d, err := time.ParseDuration(value); err != nil {
+		v.add(path, "invalid duration %q, e.g. 30m, 1h", value)
+	} else if d <= 0 {
+		v.add(path, "must be greater than zero")
+	}
+}
+
+// ... optionalDuration checks that the value, if any, is a positive duration.
+func (v *validator) optionalDuration(path, value string) {
+	if value != "" {
+		v.duration(path, value)
+	}
+}
+
+// ... url checks that the value, if any, is an absolute url with one of the schemes passed.
+func (v *validator) url(path, value string, schemes ...string) {
+	if value == "" {
+		return
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		v.add(path, "invalid url %q, e.g. %s://example.com", value, schemes[0])
+		return
+	}
+
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return
+		}
+	}
+	v.add(path, "unknown scheme %q, allowed schemes are %s", u.Scheme, strings.Join(schemes, ", "))
+}
+
+// ... file checks that the file, if any, can be read.
+func (v *validator) file(path, value string) {
+	if value != "" && !fileExists(value) {
+		v.add(path, "file %q can't be read", value)
+	}
+}
+
+func (v *validator) notNegative(path string, value int) {
+	if value < 0 {
+		v.add(path, "must be zero or greater")
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+var (
+	// ... allowed values of the logger levels, used only to print them when validating.
+	loggerLevelValues = []string{DebugLevel, InfoLevel, WarnLevel, ErrorLevel, DPanicLevel, PanicLevel, FatalLevel}
+	// ... allowed values of the date time formats, used only to print them when validating.
+	dateTimeFormatValues = []string{ANSIC, UnixDate, RubyDate, RFC822, RFC822Z, RFC850, RFC1123, RFC1123Z, RFC3339, RFC3339Nano, Kitchen, Stamp, StampMilli, StampMicro, StampNano}
+)
+
+// Validate checks every field of the configuration, returning a ValidationError with all the problems found or nil.
+func (c Config) Validate() error {
+	var v validator
+
+	// ... replays never reach space-track, so they don't need credentials.
+	if c.Replay == "" {
+		v.required("auth.identity", c.Auth.Identity)
+		v.required("auth.password", c.Auth.Password)
+	}
+
+	// ... the top level work dir is only required when there are no jobs, otherwise each job must have its own.
+	if len(c.Jobs) == 0 {
+		v.required("work_dir", c.WorkDir)
+	}
+
+	v.duration("interval", c.Interval)
+
+	if c.Server.Address != "" {
+		if _, _, err := net.SplitHostPort(c.Server.Address); err != nil {
+			v.add("server.address", "invalid address %q, e.g. :9090, 127.0.0.1:9090", c.Server.Address)
+		}
+	}
+
+	v.notNegative("server.ready_intervals", c.Server.ReadyIntervals)
+
+	v.oneOf("tracing.exporter", string(c.Tracing.Exporter), new(TracingExporter).Set, TracingExporterValues)
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.add("tracing.sample_ratio", "must be between 0 and 1")
+	}
+
+	c.HTTP.validate(&v, "http")
+
+	v.file("replay", c.Replay)
+	if c.Record != "" && c.Replay != "" {
+		v.add("replay", "can't be used together with record")
+	}
+
+	// ... zero means the default rate limit of space-track.
+	v.notNegative("rate_limit", c.RateLimit)
+
+	v.file("secret_file", c.SecretFile)
+
+	v.oneOf("rest_call", string(c.RestCall), new(spacetrack.RestCall).Set, spacetrack.RestCallValues)
+	v.oneOf("format", string(c.Format), new(persist.Format).Set, persist.FormatValues)
+
+	names := make(map[string]int, len(c.Jobs))
+	for i, job := range c.Jobs {
+		p := fmt.Sprintf("jobs[%d]", i)
+
+		if first, ok := names[job.Name]; ok && job.Name != "" {
+			v.add(p+".name", "duplicated name %q, already used by jobs[%d]", job.Name, first)
+		} else {
+			names[job.Name] = i
+		}
+
+		job.validate(&v, p, c.WorkDir)
+	}
+
+	c.Logger.validate(&v, "logger")
+
+	return v.err()
+}
+
+func (h HTTP) validate(v *validator, path string) {
+	v.url(path+".base_url", h.BaseURL, "https", "http")
+	v.url(path+".proxy", h.Proxy, "http", "https", "socks5")
+
+	v.optionalDuration(path+".timeout", h.Timeout)
+	v.optionalDuration(path+".job_timeout", h.JobTimeout)
+	v.optionalDuration(path+".dial_timeout", h.DialTimeout)
+	v.optionalDuration(path+".tls_handshake_timeout", h.TLSHandshakeTimeout)
+	v.optionalDuration(path+".response_header_timeout", h.ResponseHeaderTimeout)
+	v.optionalDuration(path+".idle_conn_timeout", h.IdleConnTimeout)
+
+	v.file(path+".ca_file", h.CAFile)
+	v.file(path+".cert_file", h.CertFile)
+	v.file(path+".key_file", h.KeyFile)
+	if (h.CertFile == "") != (h.KeyFile == "") {
+		v.add(path+".key_file", "cert_file and key_file must be set together")
+	}
+
+	v.oneOf(path+".tls_min_version", string(h.TLSMinVersion), new(TLSVersion).Set, TLSVersionValues)
+
+	v.notNegative(path+".max_idle_conns", h.MaxIdleConns)
+	v.notNegative(path+".max_idle_conns_per_host", h.MaxIdleConnsPerHost)
+	v.notNegative(path+".max_conns_per_host", h.MaxConnsPerHost)
+}
+
+func (l Logger) validate(v *validator, path string) {
+	v.oneOf(path+".console_appender.level", string(l.ConsoleAppender.LoggerFileLevel), new(LoggerLevel).Set, loggerLevelValues)
+	v.oneOf(path+".console_appender.date_format", string(l.ConsoleAppender.DateTimeFormat), new(DateTimeFormat).Set, dateTimeFormatValues)
+	v.oneOf(path+".console_appender.target", string(l.ConsoleAppender.Target), new(ConsoleTarget).Set, ConsoleTargetValues)
+	v.oneOf(path+".console_appender.encoding", string(l.ConsoleAppender.Encoding), new(LoggerEncoding).Set, LoggerEncodingValues)
+
+	for i, fa := range l.FileAppenders {
+		p := fmt.Sprintf("%s.file_appenders[%d]", path, i)
+
+		v.required(p+".file", fa.LoggerFileName)
+		v.oneOf(p+".level", string(fa.LoggerFileLevel), new(LoggerLevel).Set, loggerLevelValues)
+		v.oneOf(p+".date_format", string(fa.DateTimeFormat), new(DateTimeFormat).Set, dateTimeFormatValues)
+
+		v.notNegative(p+".rotation.max_size", fa.Rotation.MaxSize)
+		v.notNegative(p+".rotation.max_age", fa.Rotation.MaxAge)
+		v.notNegative(p+".rotation.max_backups", fa.Rotation.MaxBackups)
+		v.optionalDuration(p+".rotation.interval", fa.Rotation.Interval)
+	}
+
+	for i, sa := range l.SyslogAppenders {
+		p := fmt.Sprintf("%s.syslog_appenders[%d]", path, i)
+
+		v.required(p+".network", string(sa.Network))
+		v.oneOf(p+".network", string(sa.Network), new(SyslogNetwork).Set, SyslogNetworkValues)
+		v.required(p+".address", sa.Address)
+		v.oneOf(p+".level", string(sa.LoggerFileLevel), new(LoggerLevel).Set, loggerLevelValues)
+		v.oneOf(p+".facility", string(sa.Facility), new(SyslogFacility).Set, SyslogFacilityValues)
+
+		if !validSyslogTag(sa.Tag) {
+			v.add(p+".tag", "must be up to %d printable ascii characters without spaces", syslogTagMaxLen)
+		}
+	}
+
+	v.notNegative(path+".body_dump.limit", l.BodyDump.Limit)
+}
+
+func (j Job) validate(v *validator, path, workDir string) {
+	v.required(path+".name", j.Name)
+	if j.Name != "" && !validJobName(j.Name) {
+		v.add(path+".name", "must be letters, digits, ., - or _, other than . and ..")
+	}
+	v.oneOf(path+".rest_call", string(j.RestCall), new(spacetrack.RestCall).Set, spacetrack.RestCallValues)
+	v.oneOf(path+".format", string(j.Format), new(persist.Format).Set, persist.FormatValues)
+	v.oneOf(path+".persister", j.persister, new(persist.PersisterMod).Set, persist.PersisterModValues)
+
+	if workDir == "" {
+		v.required(path+".work_dir", j.WorkDir)
+	}
+
+	if j.Interval != "" {
+		v.duration(path+".interval", j.Interval)
+	}
+
+	// ... the predicates of a query belong to the class of one rest call, so they can't be shared by all of them.
+	if j.Query != "" && (j.RestCall == "" || j.RestCall == spacetrack.All) {
+		v.add(path+".query", "requires a rest_call other than %s", spacetrack.All)
+	}
+}
+
+// ... validJobName checks the name can be part of the file names of the state of the job, e.g. the digests, without
+// pointing outside the work dir, see digestsPath.
+func validJobName(name string) bool {
+	if name == "." || name == ".." {
+		return false
+	}
+
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ... validSyslogTag checks the APP-NAME field, see RFC 5424 section 6. Empty tags mean the default one.
+func validSyslogTag(tag string) bool {
+	if len(tag) > syslogTagMaxLen {
+		return false
+	}
+
+	for i := 0; i < len(tag); i++ {
+		if tag[i] < '!' || tag[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MrTimeout/go-spacetrack/persist"
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+func validConfig() Config {
+	return Config{
+		Auth:     SpaceTrackAuth{Identity: "identity", Password: "password"},
+		WorkDir:  "/tmp/spacetrack",
+		Interval: "1h",
+		RestCall: spacetrack.Tle,
+		Format:   persist.Json,
+		Logger:   NewLogger(true, DebugLevel, "/tmp/spacetrack.json"),
+	}
+}
+
+func errorPaths(err error) []string {
+	var paths []string
+
+	if ve, ok := err.(ValidationError); ok {
+		for _, fe := range ve {
+			paths = append(paths, fe.Path)
+		}
+	}
+
+	return paths
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Run("valid config returns nil", func(t *testing.T) {
+		assert.Nil(t, validConfig().Validate())
+	})
+
+	t.Run("empty values which have defaults are allowed", func(t *testing.T) {
+		c := validConfig()
+		c.RestCall, c.Format, c.Logger = "", "", Logger{}
+
+		assert.Nil(t, c.Validate())
+	})
+
+	t.Run("empty config reports all the required fields at once", func(t *testing.T) {
+		err := Config{}.Validate()
+
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.Equal(t, []string{"auth.identity", "auth.password", "work_dir", "interval"}, errorPaths(err))
+	})
+
+	for _, each := range []struct {
+		description string
+		modify      func(*Config)
+		want        []string
+	}{
+		{
+			description: "invalid interval",
+			modify:      func(c *Config) { c.Interval = "hourly" },
+			want:        []string{"interval"},
+		},
+		{
+			description: "negative interval",
+			modify:      func(c *Config) { c.Interval = "-1h" },
+			want:        []string{"interval"},
+		},
+		{
+			description: "unknown format and rest call",
+			modify:      func(c *Config) { c.Format, c.RestCall = "yaml", "gp" },
+			want:        []string{"rest_call", "format"},
+		},
+		{
+			description: "secret file which doesn't exist",
+			modify:      func(c *Config) { c.SecretFile = "./notexistentfile" },
+			want:        []string{"secret_file"},
+		},
+		{
+			description: "invalid logger values are located by their path",
+			modify: func(c *Config) {
+				c.Logger.ConsoleAppender.LoggerFileLevel = "verbose"
+				c.Logger.FileAppenders = append(c.Logger.FileAppenders, FileLoggerAppender{DateTimeFormat: "iso", Rotation: Rotation{MaxAge: -1, Interval: "daily"}})
+				c.Logger.ConsoleAppender.Target, c.Logger.ConsoleAppender.Encoding = "stdin", "xml"
+				c.Logger.SyslogAppenders = []SyslogAppender{{Network: "http", Facility: "mail", Tag: "go spacetrack"}}
+				c.Logger.BodyDump.Limit = -1
+			},
+			want: []string{
+				"logger.console_appender.level",
+				"logger.console_appender.target",
+				"logger.console_appender.encoding",
+				"logger.file_appenders[1].file",
+				"logger.file_appenders[1].date_format",
+				"logger.file_appenders[1].rotation.max_age",
+				"logger.file_appenders[1].rotation.interval",
+				"logger.syslog_appenders[0].network",
+				"logger.syslog_appenders[0].address",
+				"logger.syslog_appenders[0].facility",
+				"logger.syslog_appenders[0].tag",
+				"logger.body_dump.limit",
+			},
+		},
+		{
+			description: "invalid server address",
+			modify:      func(c *Config) { c.Server.Address = "9090" },
+			want:        []string{"server.address"},
+		},
+		{
+			description: "unknown tracing exporter and sample ratio out of range",
+			modify:      func(c *Config) { c.Tracing = Tracing{Exporter: "jaeger", SampleRatio: 1.5} },
+			want:        []string{"tracing.exporter", "tracing.sample_ratio"},
+		},
+		{
+			description: "invalid http values are located by their path",
+			modify: func(c *Config) {
+				c.HTTP = HTTP{
+					BaseURL:         "www.space-track.org",
+					Proxy:           "ftp://proxy:21",
+					Timeout:         "1 minute",
+					JobTimeout:      "-2m",
+					CAFile:          "./notexistentfile",
+					CertFile:        "./notexistentfile",
+					TLSMinVersion:   "1.4",
+					MaxConnsPerHost: -1,
+				}
+			},
+			want: []string{
+				"http.base_url",
+				"http.proxy",
+				"http.timeout",
+				"http.job_timeout",
+				"http.ca_file",
+				"http.cert_file",
+				"http.key_file",
+				"http.tls_min_version",
+				"http.max_conns_per_host",
+			},
+		},
+		{
+			description: "http values which are set",
+			modify: func(c *Config) {
+				c.HTTP = HTTP{BaseURL: "http://localhost:8080", Proxy: "socks5://proxy:1080", Timeout: "30s", TLSMinVersion: "tls1.3"}
+			},
+		},
+		{
+			description: "replay doesn't need credentials",
+			modify: func(c *Config) {
+				c.Auth, c.Replay = SpaceTrackAuth{}, t.TempDir()
+			},
+		},
+		{
+			description: "record and replay together",
+			modify: func(c *Config) {
+				c.Record, c.Replay = t.TempDir(), "./notexistentdir"
+			},
+			want: []string{"replay", "replay"},
+		},
+		{
+			description: "negative rate limit",
+			modify:      func(c *Config) { c.RateLimit = -1 },
+			want:        []string{"rate_limit"},
+		},
+		{
+			description: "jobs inherit the top level work dir",
+			modify: func(c *Config) {
+				c.Jobs = []Job{{Name: "tle", RestCall: spacetrack.Tle}, {Name: "cdm", Interval: "5m"}}
+			},
+		},
+		{
+			description: "job names which aren't file name safe",
+			modify: func(c *Config) {
+				c.Jobs = []Job{{Name: "../x"}, {Name: "a/b"}, {Name: ".."}, {Name: "tle 1h"}, {Name: "tle_1h.v2-beta"}}
+			},
+			want: []string{"jobs[0].name", "jobs[1].name", "jobs[2].name", "jobs[3].name"},
+		},
+		{
+			description: "unknown job persister",
+			modify: func(c *Config) {
+				c.Jobs = []Job{{Name: "tle", persister: "one_file_per_row"}, {Name: "cdm", persister: "weird"}}
+			},
+			want: []string{"jobs[1].persister"},
+		},
+		{
+			description: "invalid jobs are located by their index",
+			modify: func(c *Config) {
+				c.WorkDir = ""
+				c.Jobs = []Job{
+					{Name: "tle", RestCall: spacetrack.Tle, WorkDir: "/tmp/tle"},
+					{Name: "tle", RestCall: "gp", Format: "yaml", WorkDir: "/tmp/tle", Interval: "hourly"},
+					{Query: "NORAD_CAT_ID/25544"},
+				}
+			},
+			want: []string{
+				"jobs[1].name",
+				"jobs[1].rest_call",
+				"jobs[1].format",
+				"jobs[1].interval",
+				"jobs[2].name",
+				"jobs[2].work_dir",
+				"jobs[2].query",
+			},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			c := validConfig()
+			each.modify(&c)
+
+			assert.Equal(t, each.want, errorPaths(c.Validate()))
+		})
+	}
+}
+
+func TestConfigValidateCmd(t *testing.T) {
+	for _, each := range []struct {
+		description, content string
+		wantErr              bool
+	}{
+		{
+			description: "valid config file",
+			content:     "auth:\n  identity: identity\n  password: password\nwork_dir: /tmp/spacetrack\ninterval: 1h\nformat: xml\n",
+		},
+		{
+			description: "invalid config file",
+			content:     "auth:\n  identity: identity\nwork_dir: /tmp/spacetrack\ninterval: 1h\nformat: yaml\njobs:\n  - name: tle\n    persister: weird\n",
+			wantErr:     true,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			f := createFile(t, t.TempDir(), "spacetrack.yml", 0666)
+			if _, err := f.WriteString(each.content); err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			root := newRootCmd()
+			root.SetOut(&out)
+			root.SetErr(&out)
+			root.SetArgs([]string{"config", "validate", "--config-file", f.Name()})
+
+			err := root.Execute()
+
+			if each.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidConfig)
+				assert.Contains(t, out.String(), "auth.password: is required")
+				assert.Contains(t, out.String(), "format: unknown value \"yaml\"")
+				assert.Contains(t, out.String(), "jobs[0].persister: unknown value \"weird\"")
+			} else {
+				assert.Nil(t, err)
+				assert.Contains(t, out.String(), "configuration is valid")
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+// Package telemetry has the helpers shared by the packages of go-spacetrack to trace their work with OpenTelemetry.
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer of every span, which is the import path of the module.
+const TracerName = "github.com/MrTimeout/go-spacetrack"
+
+// Start starts a span using the current global tracer provider, which is a no-op one until the program registers its own.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error, if any, before ending the span. It is meant to be deferred with a pointer to the
+// named error of the function.
+func End(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
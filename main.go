@@ -1,7 +0,0 @@
-package main
-
-func main() {
-	if err := execute(); err != nil {
-		panic(err)
-	}
-}
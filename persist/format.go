@@ -1,10 +1,12 @@
-package main
+// Package persist writes the records fetched from space-track to files, encoded with the marshaller of a format.
+package persist
 
 import (
 	"errors"
 	"strings"
 )
 
+// ErrParsingFormatType is returned when the input is not the name of a format.
 var ErrParsingFormatType = errors.New("parsing input to format type")
 
 const (
@@ -12,11 +14,17 @@ const (
 	Xml  Format = "xml"
 	Csv  Format = "csv"
 	Html Format = "html"
+	// Ndjson is newline delimited json, one record per line.
+	Ndjson Format = "ndjson"
+	// Parquet is the columnar format of Apache Parquet, one row per record.
+	Parquet Format = "parquet"
 )
 
+// Format is the encoding of the files persisted.
 type Format string
 
-var FormatValues []string = []string{Json.String(), Xml.String(), Csv.String(), Html.String()}
+// FormatValues are the names of the formats allowed.
+var FormatValues []string = []string{Json.String(), Xml.String(), Csv.String(), Html.String(), Ndjson.String(), Parquet.String()}
 
 func (f Format) String() string {
 	var result = ""
@@ -30,6 +38,10 @@ func (f Format) String() string {
 		result = "csv"
 	case Html:
 		result = "html"
+	case Ndjson:
+		result = "ndjson"
+	case Parquet:
+		result = "parquet"
 	}
 
 	return result
@@ -46,7 +58,12 @@ func (f *Format) Set(input string) error {
 	return nil
 }
 
+// ToPath returns the predicate of the format of the response of space-track. Ndjson and parquet are not served by
+// space-track, so json is requested instead.
 func (f Format) ToPath() string {
+	if f == Ndjson || f == Parquet {
+		return "/format/" + Json.String()
+	}
 	return "/format/" + f.String()
 }
 
@@ -60,6 +77,10 @@ func (f *Format) unmarshalText(input string) bool {
 		*f = Csv
 	case "html", "HTML":
 		*f = Html
+	case "ndjson", "NDJSON":
+		*f = Ndjson
+	case "parquet", "PARQUET":
+		*f = Parquet
 	default:
 		return false
 	}
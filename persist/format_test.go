@@ -1,4 +1,4 @@
-package main
+package persist
 
 import (
 	"testing"
@@ -31,6 +31,16 @@ var formatTestCases = []struct {
 		str:    "xml",
 		path:   "/format/xml",
 	},
+	{
+		format: Ndjson,
+		str:    "ndjson",
+		path:   "/format/json",
+	},
+	{
+		format: Parquet,
+		str:    "parquet",
+		path:   "/format/json",
+	},
 }
 
 func TestFormat(t *testing.T) {
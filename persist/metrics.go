@@ -0,0 +1,54 @@
+package persist
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics are the prometheus metrics of the files persisted. It is a prometheus.Collector, so it must be registered
+// by the program, see WithMetrics.
+type Metrics struct {
+	filesWrittenTotal *prometheus.CounterVec
+	filesFailedTotal  *prometheus.CounterVec
+}
+
+// NewMetrics returns the metrics of the files persisted, which can be shared by several persisters.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		filesWrittenTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "spacetrack",
+			Name:      "files_written_total",
+			Help:      "Files persisted by persister.",
+		}, []string{"persister"}),
+		filesFailedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "spacetrack",
+			Name:      "files_failed_total",
+			Help:      "Files which couldn't be persisted by persister.",
+		}, []string{"persister"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.filesWrittenTotal.Describe(ch)
+	m.filesFailedTotal.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.filesWrittenTotal.Collect(ch)
+	m.filesFailedTotal.Collect(ch)
+}
+
+// ... the methods below do nothing if the metrics are nil, which is the default of the persisters.
+
+func (m *Metrics) written(pm PersisterMod) {
+	if m == nil {
+		return
+	}
+	m.filesWrittenTotal.WithLabelValues(pm.String()).Inc()
+}
+
+func (m *Metrics) failed(pm PersisterMod) {
+	if m == nil {
+		return
+	}
+	m.filesFailedTotal.WithLabelValues(pm.String()).Inc()
+}
@@ -0,0 +1,167 @@
+package persist
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// ErrParquetType is returned when the records can't be the rows of a parquet file, e.g. they have a slice field.
+var ErrParquetType = errors.New("type not supported by parquet")
+
+// ... parquetTypes are the physical types of the columns of the kinds of fields allowed.
+var parquetTypes = map[reflect.Kind]string{
+	reflect.String:  "type=BYTE_ARRAY, convertedtype=UTF8",
+	reflect.Bool:    "type=BOOLEAN",
+	reflect.Int:     "type=INT64",
+	reflect.Int32:   "type=INT64",
+	reflect.Int64:   "type=INT64",
+	reflect.Float32: "type=DOUBLE",
+	reflect.Float64: "type=DOUBLE",
+}
+
+// ParquetMarshaller encodes the records as the rows of a parquet file, with one optional column per field named after
+// its json name. Root elements are encoded as their records, see CSVMarshaller.
+type ParquetMarshaller struct{}
+
+func (p ParquetMarshaller) Marshal(input any) ([]byte, error) {
+	rows := reflect.ValueOf(records(input))
+	if rows.Kind() != reflect.Slice {
+		rows = reflect.Append(reflect.MakeSlice(reflect.SliceOf(rows.Type()), 0, 1), rows)
+	}
+
+	schema, err := parquetSchema(rows.Type().Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+
+	pw, err := writer.NewJSONWriterFromWriter(schema, &b, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < rows.Len(); i++ {
+		row, err := json.Marshal(rows.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		if err := pw.Write(string(row)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := pw.WriteStop(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func (p ParquetMarshaller) Ext() string {
+	return Parquet.String()
+}
+
+// Unmarshal decodes the rows into the output, or the first row if the output is not a slice, matching the columns with
+// the json names of its fields. Root elements are decoded into their records, see CSVMarshaller.
+func (p ParquetMarshaller) Unmarshal(input []byte, output any) error {
+	f, err := buffer.NewBufferFile(input)
+	if err != nil {
+		return err
+	}
+
+	pr, err := reader.NewParquetReader(f, nil, 1)
+	if err != nil {
+		return err
+	}
+	defer pr.ReadStop()
+
+	var (
+		n    = pr.GetNumRows()
+		rows = make([]map[string]any, n)
+	)
+
+	for i := range rows {
+		rows[i] = map[string]any{}
+	}
+
+	for _, path := range pr.SchemaHandler.ValueColumns {
+		values, _, _, err := pr.ReadColumnByPath(path, n)
+		if err != nil {
+			return err
+		}
+
+		exPath := strings.Split(pr.SchemaHandler.InPathToExPath[path], common.PAR_GO_PATH_DELIMITER)
+		name := exPath[len(exPath)-1]
+
+		for i, v := range values {
+			if i < len(rows) && v != nil {
+				rows[i][name] = v
+			}
+		}
+	}
+
+	target := recordsOf(output)
+
+	var b []byte
+
+	if reflect.TypeOf(target).Elem().Kind() == reflect.Slice {
+		b, err = json.Marshal(rows)
+	} else if len(rows) > 0 {
+		b, err = json.Marshal(rows[0])
+	} else {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, target)
+}
+
+// ... parquetSchema returns the json schema of the parquet writer for the records of the type passed: one optional column
+// per exported field, named after its json name, skipping the ones whose name is -, like XMLName.
+func parquetSchema(t reflect.Type) (string, error) {
+	if t.Kind() != reflect.Struct {
+		return "", fmt.Errorf("%w: %s", ErrParquetType, t)
+	}
+
+	fields := []map[string]string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		columnType, ok := parquetTypes[f.Type.Kind()]
+		if !ok {
+			return "", fmt.Errorf("%w: field %s of %s is %s", ErrParquetType, f.Name, t, f.Type)
+		}
+
+		fields = append(fields, map[string]string{"Tag": "name=" + name + ", " + columnType + ", repetitiontype=OPTIONAL"})
+	}
+
+	schema, err := json.Marshal(map[string]any{"Tag": "name=parquet_go_root, repetitiontype=REQUIRED", "Fields": fields})
+
+	return string(schema), err
+}
@@ -0,0 +1,72 @@
+package persist
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parquetItem struct {
+	XMLName  xml.Name `json:"-" xml:"item"`
+	Name     string   `json:"NAME"`
+	Altitude float64  `json:"ALTITUDE"`
+	Revs     int      `json:"REVS"`
+	Sunlit   bool     `json:"SUNLIT"`
+	internal string
+}
+
+func TestParquetMarshaller(t *testing.T) {
+	var (
+		m    = ParquetMarshaller{}
+		iss  = parquetItem{Name: "ISS (ZARYA)", Altitude: 418.2, Revs: 15, Sunlit: true}
+		css  = parquetItem{Name: "CSS (TIANHE)", Altitude: 389.5, Revs: 15}
+		root = struct{ Items []parquetItem }{Items: []parquetItem{iss, css}}
+	)
+
+	t.Run("records of a root element", func(t *testing.T) {
+		b, err := m.Marshal(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []parquetItem
+
+		assert.Nil(t, m.Unmarshal(b, &got))
+		assert.Equal(t, []parquetItem{iss, css}, got)
+	})
+
+	t.Run("one record", func(t *testing.T) {
+		b, err := m.Marshal(iss)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got parquetItem
+
+		assert.Nil(t, m.Unmarshal(b, &got))
+		assert.Equal(t, iss, got)
+	})
+
+	t.Run("no records", func(t *testing.T) {
+		b, err := m.Marshal(struct{ Items []parquetItem }{Items: []parquetItem{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []parquetItem
+
+		assert.Nil(t, m.Unmarshal(b, &got))
+		assert.Empty(t, got)
+	})
+
+	t.Run("fields which can't be columns", func(t *testing.T) {
+		_, err := m.Marshal([]struct{ Names []string }{{Names: []string{"ISS", "CSS"}}})
+
+		assert.ErrorIs(t, err, ErrParquetType)
+	})
+
+	t.Run("not parquet", func(t *testing.T) {
+		assert.NotNil(t, m.Unmarshal([]byte(`[{"NAME":"ISS"}]`), &[]parquetItem{}))
+	})
+}
@@ -0,0 +1,247 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// FileName is the template file name to write the content of space track to it
+const (
+	FileName   = "Spacetrack_record_"
+	FileFormat = "%016d"
+)
+
+var (
+	// ErrInvalidPersisterMod is returned when the persister mod passed as a parameter is incorrect
+	ErrInvalidPersisterMod = errors.New("invalid persister mod")
+	// ErrInvalidInputLen is returned when the input of data to persist is not the correct one
+	ErrInvalidInputLen = errors.New("invalid input len")
+)
+
+// Persister is a type which is responsible of dumping information to one/multiple files
+type Persister interface {
+	Persist(context.Context, string, []any) error
+}
+
+// PersisterMod represents the mod in which to persist the response from SpaceTrack.
+type PersisterMod int
+
+const (
+	// OneFile is the persister that will persist the spacetrack information into a single file.
+	OneFile PersisterMod = iota
+	// OneFilePerRow is the persister that will persist the spacetrack information into multiple files,
+	// each of them will be a row of the response.
+	OneFilePerRow
+
+	// ... invalidPersisterMod is decoded from the unknown names, so it is rejected by GetPersister if it isn't validated.
+	invalidPersisterMod PersisterMod = -1
+)
+
+// PersisterModValues are the names of the persister mods allowed in the configuration.
+var PersisterModValues = []string{OneFile.String(), OneFilePerRow.String()}
+
+func (pm PersisterMod) String() string {
+	var result = ""
+
+	switch pm {
+	case OneFile:
+		result = "one_file"
+	case OneFilePerRow:
+		result = "one_file_per_row"
+	}
+
+	return result
+}
+
+// Set parses the name of the persister mod, e.g. one_file_per_row, returning ErrInvalidPersisterMod if it is unknown.
+func (pm *PersisterMod) Set(input string) error {
+	switch strings.ToLower(input) {
+	case "one_file":
+		*pm = OneFile
+	case "one_file_per_row":
+		*pm = OneFilePerRow
+	default:
+		return ErrInvalidPersisterMod
+	}
+
+	return nil
+}
+
+// UnmarshalText allows to use the name of the persister mod in the configuration file, e.g. one_file_per_row. Unknown
+// names don't fail the decoding, leaving their report to the validation of the configuration, but they aren't valid
+// persister mods either, see GetPersister.
+func (pm *PersisterMod) UnmarshalText(input []byte) error {
+	if err := pm.Set(string(input)); err != nil {
+		*pm = invalidPersisterMod
+	}
+
+	return nil
+}
+
+// Option configures a Persister, see GetPersister.
+type Option func(*options)
+
+type options struct {
+	logger  *zap.Logger
+	metrics *Metrics
+}
+
+// WithLogger sets the logger of the files written. By default, nothing is logged.
+func WithLogger(l *zap.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithMetrics counts the files written and failed in the metrics passed, which must be registered by the caller.
+func WithMetrics(m *Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+func (o options) log() *zap.Logger {
+	if o.logger == nil {
+		return zap.NewNop()
+	}
+	return o.logger
+}
+
+// GetPersister returns a persister depending on the persisterMod passed as a parameter. If no
+// persister is found, error is returned.
+func GetPersister(pm PersisterMod, mFormat Format, opts ...Option) (Persister, error) {
+	var (
+		p   Persister
+		err error
+		o   options
+	)
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch pm {
+	case OneFile:
+		p, err = oneFile(mFormat, o)
+	case OneFilePerRow:
+		p, err = oneFilePerRow(mFormat, o)
+	default:
+		err = ErrInvalidPersisterMod
+	}
+
+	return p, err
+}
+
+// OneFilePersister is the persister that will persist the spacetrack information into a single file.
+type oneFilePersister struct {
+	Writer
+	options
+}
+
+func oneFile(mFormat Format, o options) (Persister, error) {
+	m, err := GetMarshaller(mFormat)
+	return oneFilePersister{NewWriter(m, o.logger), o}, err
+}
+
+func (o oneFilePersister) Persist(ctx context.Context, folder string, input []any) (err error) {
+	ctx, span := telemetry.Start(ctx, "spacetrack.persist", attribute.String("spacetrack.persister", OneFile.String()), attribute.String("spacetrack.folder", folder))
+	defer telemetry.End(span, &err)
+
+	if len(input) != 1 {
+		return ErrInvalidInputLen
+	}
+
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return err
+	}
+
+	if err := o.Write(ctx, buildFilepath(time.Now().Unix(), folder), input[0]); err != nil {
+		o.metrics.failed(OneFile)
+		return err
+	}
+
+	o.metrics.written(OneFile)
+	return nil
+}
+
+// OneFilePerRowPersister
+type oneFilePerRowPersister struct {
+	Writer
+	options
+}
+
+func oneFilePerRow(mFormat Format, o options) (Persister, error) {
+	m, err := GetMarshaller(mFormat)
+	return oneFilePerRowPersister{NewWriter(m, o.logger), o}, err
+}
+
+// Persist is used to persist all the data fetched from SpaceTrack
+func (o oneFilePerRowPersister) Persist(ctx context.Context, folder string, arr []any) (err error) {
+	ctx, span := telemetry.Start(ctx, "spacetrack.persist", attribute.String("spacetrack.persister", OneFilePerRow.String()), attribute.String("spacetrack.folder", folder), attribute.Int("spacetrack.records", len(arr)))
+	defer telemetry.End(span, &err)
+
+	var (
+		wg      sync.WaitGroup
+		counter int32
+	)
+
+	defer func() {
+		wg.Wait()
+		o.log().Info("We have successfully persist files into system", zap.Int32("amount", counter))
+	}()
+
+	if err := o.cleanUp(folder); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(arr); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := o.Write(ctx, buildFilepath(int64(i), folder), arr[i]); err != nil {
+				o.metrics.failed(OneFilePerRow)
+				o.log().Error("trying to write file to system", zap.Error(err))
+			} else {
+				o.metrics.written(OneFilePerRow)
+				atomic.AddInt32(&counter, 1)
+			}
+		}(i)
+	}
+
+	return nil
+}
+
+func buildFilepath(i int64, folder string) string {
+	return filepath.Join(folder, FileName+fmt.Sprintf(FileFormat, i))
+}
+
+// TODO here we are removing all the files that match a filename ending, but we can have an html, a json...
+func (o options) cleanUp(folder string) error {
+	files, err := filepath.Glob(filepath.Join(folder, FileName+"*"))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := os.Remove(f); err != nil {
+			o.log().Warn("trying to delete a file while cleaning up the folder", zap.String("folder_name", folder), zap.String("file_name", f))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package persist
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type record struct {
+	Name string `json:"name"`
+}
+
+func TestPersister(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		pm          PersisterMod
+		input       []any
+		files       int
+		wantErr     error
+	}{
+		{
+			description: "one file",
+			pm:          OneFile,
+			input:       []any{[]record{{"ISS"}, {"CSS"}}},
+			files:       1,
+		},
+		{
+			description: "one file with several inputs",
+			pm:          OneFile,
+			input:       []any{record{"ISS"}, record{"CSS"}},
+			wantErr:     ErrInvalidInputLen,
+		},
+		{
+			description: "one file per row",
+			pm:          OneFilePerRow,
+			input:       []any{record{"ISS"}, record{"CSS"}},
+			files:       2,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var (
+				m   = NewMetrics()
+				dir = filepath.Join(t.TempDir(), "records")
+			)
+
+			p, err := GetPersister(each.pm, Json, WithMetrics(m))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = p.Persist(context.Background(), dir, each.input)
+
+			assert.ErrorIs(t, err, each.wantErr)
+			assert.Equal(t, float64(each.files), testutil.ToFloat64(m.filesWrittenTotal.WithLabelValues(each.pm.String())))
+
+			files, _ := os.ReadDir(dir)
+			assert.Len(t, files, each.files)
+		})
+	}
+
+	t.Run("unknown persister mod", func(t *testing.T) {
+		_, err := GetPersister(PersisterMod(42), Json)
+
+		assert.ErrorIs(t, err, ErrInvalidPersisterMod)
+	})
+
+	t.Run("unknown names are decoded as invalid persister mods", func(t *testing.T) {
+		var pm PersisterMod
+
+		assert.ErrorIs(t, pm.Set("weird"), ErrInvalidPersisterMod)
+		assert.Nil(t, pm.UnmarshalText([]byte("weird")))
+
+		_, err := GetPersister(pm, Json)
+		assert.ErrorIs(t, err, ErrInvalidPersisterMod)
+
+		assert.Nil(t, pm.UnmarshalText([]byte("ONE_FILE_PER_ROW")))
+		assert.Equal(t, OneFilePerRow, pm)
+	})
+}
@@ -0,0 +1,232 @@
+package persist
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"golang.org/x/net/html"
+)
+
+// ErrUnknownExtension is returned when reading a file whose extension is not the one of any format.
+var ErrUnknownExtension = errors.New("unknown extension")
+
+// Unmarshaller is responsible of decoding the content encoded by the Marshaller of the same format.
+type Unmarshaller interface {
+	// Unmarshal decodes the input into the output, which must be a pointer.
+	Unmarshal([]byte, any) error
+	// Ext is the extension of the files read with the unmarshaller, without the dot.
+	Ext() string
+}
+
+// GetUnmarshaller returns the unmarshaller of the format, or ErrParsingFormatType if the format is unknown.
+func GetUnmarshaller(mFormat Format) (Unmarshaller, error) {
+	var (
+		u   Unmarshaller
+		err error
+	)
+
+	switch mFormat {
+	case Json:
+		u = JSONMarshaller{}
+	case Xml:
+		u = XMLMarshaller{}
+	case Csv:
+		u = CSVMarshaller{}
+	case Html:
+		u = HTMLMarshaller{}
+	case Ndjson:
+		u = NDJSONMarshaller{}
+	case Parquet:
+		u = ParquetMarshaller{}
+	default:
+		err = ErrParsingFormatType
+	}
+
+	return u, err
+}
+
+// FormatOf returns the format of the file by its extension, e.g. xml for Spacetrack_record_0000000000000001.xml
+func FormatOf(file string) (Format, error) {
+	f, err := ParseFormat(strings.TrimPrefix(filepath.Ext(file), "."))
+	if err != nil {
+		return f, fmt.Errorf("%w: %s", ErrUnknownExtension, file)
+	}
+	return f, nil
+}
+
+// ReadFile decodes the file into the output, which must be a pointer, with the unmarshaller of the format of its
+// extension, so any file written by a Writer can be read back.
+func ReadFile(file string, output any) error {
+	f, err := FormatOf(file)
+	if err != nil {
+		return err
+	}
+
+	return ReadFileAs(file, f, output)
+}
+
+// ReadFileAs decodes the file into the output, which must be a pointer, with the unmarshaller of the format passed,
+// whatever the extension of the file is.
+func ReadFileAs(file string, f Format, output any) error {
+	u, err := GetUnmarshaller(f)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if err := u.Unmarshal(content, output); err != nil {
+		return fmt.Errorf("reading %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// RecordFiles returns the files written by the persisters in the folder of an execution, sorted by name, so the
+// records keep the order in which they were fetched.
+func RecordFiles(folder string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(folder, FileName+"*"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func (m XMLMarshaller) Unmarshal(input []byte, output any) error {
+	return xml.Unmarshal(input, output)
+}
+
+func (m JSONMarshaller) Unmarshal(input []byte, output any) error {
+	return json.Unmarshal(input, output)
+}
+
+// Unmarshal decodes the rows into the output. Root elements are decoded into their records, see CSVMarshaller.
+func (m CSVMarshaller) Unmarshal(input []byte, output any) error {
+	return gocsv.UnmarshalBytes(input, recordsOf(output))
+}
+
+// Unmarshal decodes one record per line into the output, or the first line if the output is not a slice. Root elements
+// are decoded into their records, see CSVMarshaller.
+func (n NDJSONMarshaller) Unmarshal(input []byte, output any) error {
+	var (
+		target = recordsOf(output)
+		v      = reflect.ValueOf(target).Elem()
+		dec    = json.NewDecoder(bytes.NewReader(input))
+	)
+
+	if v.Kind() != reflect.Slice {
+		if !dec.More() {
+			return nil
+		}
+		return dec.Decode(target)
+	}
+
+	for dec.More() {
+		item := reflect.New(v.Type().Elem())
+		if err := dec.Decode(item.Interface()); err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, item.Elem()))
+	}
+
+	return nil
+}
+
+// Unmarshal decodes the fields encoded by HTMLMarshaller, pairs of spans with the label and the value of each field,
+// into the output, as if they were json whose names are the labels. A record ends when one of its labels is repeated.
+// Root elements are decoded into their records, see CSVMarshaller.
+func (h HTMLMarshaller) Unmarshal(input []byte, output any) error {
+	var (
+		records []map[string]string
+		current = map[string]string{}
+		spans   []string
+		inSpan  bool
+		text    strings.Builder
+	)
+
+	z := html.NewTokenizer(bytes.NewReader(input))
+
+	for tt := z.Next(); tt != html.ErrorToken; tt = z.Next() {
+		name, _ := z.TagName()
+
+		switch {
+		case tt == html.StartTagToken && string(name) == "div":
+			spans = nil
+		case tt == html.StartTagToken && string(name) == "span":
+			inSpan = true
+			text.Reset()
+		case tt == html.TextToken && inSpan:
+			text.Write(z.Text())
+		case tt == html.EndTagToken && string(name) == "span":
+			inSpan = false
+			spans = append(spans, text.String())
+		case tt == html.EndTagToken && string(name) == "div":
+			if len(spans) == 2 {
+				if _, ok := current[spans[0]]; ok {
+					records = append(records, current)
+					current = map[string]string{}
+				}
+				current[spans[0]] = spans[1]
+			}
+			spans = nil
+		}
+	}
+
+	if len(current) > 0 {
+		records = append(records, current)
+	}
+
+	target := recordsOf(output)
+
+	var (
+		b   []byte
+		err error
+	)
+
+	if reflect.TypeOf(target).Elem().Kind() == reflect.Slice {
+		b, err = json.Marshal(records)
+	} else if len(records) > 0 {
+		b, err = json.Marshal(records[0])
+	} else {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, target)
+}
+
+// ... recordsOf returns a pointer to the first slice field of the output if it is a pointer to a struct, or the output
+// otherwise, see records.
+func recordsOf(output any) any {
+	v := reflect.ValueOf(output)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return output
+	}
+
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() == reflect.Slice && v.Type().Field(i).IsExported() {
+			return v.Field(i).Addr().Interface()
+		}
+	}
+
+	return output
+}
@@ -0,0 +1,95 @@
+package persist
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type readerItem struct {
+	Name    string `json:"NAME" xml:"NAME" csv:"NAME" html:"l=NAME,e=span"`
+	NoradID string `json:"NORAD_CAT_ID" xml:"NORAD_CAT_ID" csv:"NORAD_CAT_ID" html:"l=NORAD_CAT_ID,e=span"`
+}
+
+type readerRoot struct {
+	XMLName xml.Name     `json:"-" xml:"root"`
+	Items   []readerItem `json:"item" xml:"item" html:"item"`
+}
+
+func TestReadFile(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		want = []readerItem{{Name: "ISS (ZARYA)", NoradID: "25544"}, {Name: "", NoradID: "5"}, {Name: "CSS (TIANHE)", NoradID: "48274"}}
+	)
+
+	for _, f := range []Format{Json, Xml, Csv, Html, Ndjson, Parquet} {
+		t.Run(f.String(), func(t *testing.T) {
+			m, err := GetMarshaller(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			file := filepath.Join(dir, FileName+"0")
+			if err := NewWriter(m, nil).Write(context.Background(), file, readerRoot{Items: want}); err != nil {
+				t.Fatal(err)
+			}
+
+			var got readerRoot
+
+			assert.Nil(t, ReadFile(file+"."+f.String(), &got))
+			assert.Equal(t, want, got.Items)
+		})
+	}
+
+	t.Run("html record", func(t *testing.T) {
+		var got readerItem
+
+		assert.Nil(t, HTMLMarshaller{}.Unmarshal([]byte("<div><span>NAME</span><span>ISS (ZARYA)</span></div>"), &got))
+		assert.Equal(t, readerItem{Name: "ISS (ZARYA)"}, got)
+	})
+
+	t.Run("ndjson record", func(t *testing.T) {
+		var got readerItem
+
+		assert.Nil(t, NDJSONMarshaller{}.Unmarshal([]byte("{\"NAME\":\"ISS (ZARYA)\"}\n{\"NAME\":\"CSS (TIANHE)\"}\n"), &got))
+		assert.Equal(t, readerItem{Name: "ISS (ZARYA)"}, got)
+	})
+
+	t.Run("format passed instead of the one of the extension", func(t *testing.T) {
+		file := filepath.Join(dir, "records.txt")
+		if err := os.WriteFile(file, []byte("<root><item><NAME>ISS (ZARYA)</NAME></item></root>"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var got readerRoot
+
+		assert.Nil(t, ReadFileAs(file, Xml, &got))
+		assert.Equal(t, []readerItem{{Name: "ISS (ZARYA)"}}, got.Items)
+	})
+
+	t.Run("unknown extension", func(t *testing.T) {
+		assert.ErrorIs(t, ReadFile(filepath.Join(dir, "records.yaml"), &readerRoot{}), ErrUnknownExtension)
+	})
+}
+
+func TestRecordFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := GetPersister(OneFilePerRow, Json)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Persist(context.Background(), dir, []any{record{"ISS"}, record{"CSS"}, record{"VANGUARD 1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := RecordFiles(dir)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{buildFilepath(0, dir) + ".json", buildFilepath(1, dir) + ".json", buildFilepath(2, dir) + ".json"}, files)
+}
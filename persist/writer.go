@@ -0,0 +1,198 @@
+package persist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"reflect"
+
+	"github.com/DrGrimshaw/gohtml"
+	"github.com/MrTimeout/go-spacetrack/internal/telemetry"
+	"github.com/gocarina/gocsv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Marshaller is responsible of converting the golang struct to a string encoded with the marshal method
+type Marshaller interface {
+	// Marshal encodes the input.
+	Marshal(any) ([]byte, error)
+	// Ext is the extension of the files written with the marshaller, without the dot.
+	Ext() string
+}
+
+// Writer is reponsible of writing the content marshalled by the marshaller to the file passed as parameter
+type Writer interface {
+	Write(context.Context, string, any) error
+}
+
+// WriterImpl writes the files encoded with its Marshaller, appending the extension of the marshaller to their name.
+type WriterImpl struct {
+	Marshaller
+	logger *zap.Logger
+}
+
+// NewWriter returns a writer which encodes the files with the marshaller, logging each file written. If the logger
+// is nil, nothing is logged.
+func NewWriter(m Marshaller, l *zap.Logger) WriterImpl {
+	return WriterImpl{Marshaller: m, logger: l}
+}
+
+func (w WriterImpl) Write(ctx context.Context, file string, input any) error {
+	b, err := w.traceMarshal(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	file = file + "." + w.Ext()
+
+	w.log().Info("writing to file", zap.String("file_name", file), zap.Int("content_size", len(b)))
+
+	if err := os.WriteFile(file, b, 0666); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w WriterImpl) log() *zap.Logger {
+	if w.logger == nil {
+		return zap.NewNop()
+	}
+	return w.logger
+}
+
+// ... traceMarshal marshals the input inside a span, so slow formats can be told apart from slow disks.
+func (w WriterImpl) traceMarshal(ctx context.Context, input any) (b []byte, err error) {
+	_, span := telemetry.Start(ctx, "spacetrack.marshal", attribute.String("spacetrack.format", w.Ext()))
+	defer telemetry.End(span, &err)
+
+	b, err = w.Marshal(input)
+	span.SetAttributes(attribute.Int("spacetrack.bytes", len(b)))
+
+	return b, err
+}
+
+// GetMarshaller returns the marshaller of the format, or ErrParsingFormatType if the format is unknown.
+func GetMarshaller(mFormat Format) (Marshaller, error) {
+	var (
+		m   Marshaller
+		err error
+	)
+
+	switch mFormat {
+	case Json:
+		m = JSONMarshaller{}
+	case Xml:
+		m = XMLMarshaller{}
+	case Csv:
+		m = CSVMarshaller{}
+	case Html:
+		m = HTMLMarshaller{}
+	case Ndjson:
+		m = NDJSONMarshaller{}
+	case Parquet:
+		m = ParquetMarshaller{}
+	default:
+		err = ErrParsingFormatType
+	}
+
+	return m, err
+}
+
+// XMLMarshaller encodes with encoding/xml.
+type XMLMarshaller struct{}
+
+func (m XMLMarshaller) Marshal(input any) ([]byte, error) {
+	return xml.Marshal(input)
+}
+
+func (m XMLMarshaller) Ext() string {
+	return Xml.String()
+}
+
+// JSONMarshaller encodes with encoding/json.
+type JSONMarshaller struct{}
+
+func (m JSONMarshaller) Marshal(input any) ([]byte, error) {
+	return json.Marshal(input)
+}
+
+func (m JSONMarshaller) Ext() string {
+	return Json.String()
+}
+
+// CSVMarshaller encodes with a header row and one row per record. Root elements, structs holding the records in a
+// slice, like spacetrack.SpaceTrackTle, are encoded as their records.
+type CSVMarshaller struct{}
+
+func (m CSVMarshaller) Marshal(input any) ([]byte, error) {
+	var b bytes.Buffer
+
+	err := gocsv.Marshal(records(input), &b)
+
+	return b.Bytes(), err
+}
+
+func (m CSVMarshaller) Ext() string {
+	return Csv.String()
+}
+
+// ... records returns the first slice field of the input if it is a struct, or the input otherwise.
+func records(input any) any {
+	v := reflect.ValueOf(input)
+	if v.Kind() != reflect.Struct {
+		return input
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() == reflect.Slice && v.Type().Field(i).IsExported() {
+			return v.Field(i).Interface()
+		}
+	}
+
+	return input
+}
+
+// HTMLMarshaller encodes each field as a span inside a div.
+type HTMLMarshaller struct{}
+
+func (h HTMLMarshaller) Marshal(input any) ([]byte, error) {
+	str, err := gohtml.Encode(input)
+	return []byte(str), err
+}
+
+func (h HTMLMarshaller) Ext() string {
+	return Html.String()
+}
+
+// NDJSONMarshaller encodes each record as json in its own line. Root elements are encoded as their records, see
+// CSVMarshaller.
+type NDJSONMarshaller struct{}
+
+func (n NDJSONMarshaller) Marshal(input any) ([]byte, error) {
+	var (
+		b   bytes.Buffer
+		enc = json.NewEncoder(&b)
+		v   = reflect.ValueOf(records(input))
+	)
+
+	if v.Kind() != reflect.Slice {
+		err := enc.Encode(input)
+		return b.Bytes(), err
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := enc.Encode(v.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+func (n NDJSONMarshaller) Ext() string {
+	return Ndjson.String()
+}
@@ -1,6 +1,7 @@
-package main
+package persist
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -11,11 +12,11 @@ var errDumbMarshaller = errors.New("dumb marshaller not working")
 
 type dumbMarshaller struct{}
 
-func (d dumbMarshaller) marshal(input any) ([]byte, error) {
+func (d dumbMarshaller) Marshal(input any) ([]byte, error) {
 	return nil, errDumbMarshaller
 }
 
-func (d dumbMarshaller) ext() string {
+func (d dumbMarshaller) Ext() string {
 	return "dumb"
 }
 
@@ -46,6 +47,16 @@ func TestGetMarshaller(t *testing.T) {
 			mFormat:     Html,
 			want:        HTMLMarshaller{},
 		},
+		{
+			description: "ndjson marshaller",
+			mFormat:     Ndjson,
+			want:        NDJSONMarshaller{},
+		},
+		{
+			description: "parquet marshaller",
+			mFormat:     Parquet,
+			want:        ParquetMarshaller{},
+		},
 		{
 			description: "invalid marshaller",
 			mFormat:     "invalid",
@@ -53,7 +64,7 @@ func TestGetMarshaller(t *testing.T) {
 		},
 	} {
 		t.Run(each.description, func(t *testing.T) {
-			got, gotErr := getMarshaller(each.mFormat)
+			got, gotErr := GetMarshaller(each.mFormat)
 
 			assert.Equal(t, each.want, got)
 			assert.ErrorIs(t, each.wantErr, gotErr)
@@ -94,15 +105,33 @@ func TestMarshal(t *testing.T) {
 			input:       []dumb{d},
 			want:        "value1,value2\nhere some value,2\n",
 		},
+		{
+			description: "csv marshalling of the records of a root element",
+			m:           CSVMarshaller{},
+			input:       struct{ Items []dumb }{Items: []dumb{d, d}},
+			want:        "value1,value2\nhere some value,2\nhere some value,2\n",
+		},
 		{
 			description: "html marshalling",
 			m:           HTMLMarshaller{},
 			input:       d,
 			want:        "<div><span>Value1</span><span>here some value</span></div><div><span>Value2</span><span>2</span></div>",
 		},
+		{
+			description: "ndjson marshalling of the records of a root element",
+			m:           NDJSONMarshaller{},
+			input:       struct{ Items []dumb }{Items: []dumb{d, d}},
+			want:        "{\"value1\":\"here some value\",\"value2\":2}\n{\"value1\":\"here some value\",\"value2\":2}\n",
+		},
+		{
+			description: "ndjson marshalling of a record",
+			m:           NDJSONMarshaller{},
+			input:       d,
+			want:        "{\"value1\":\"here some value\",\"value2\":2}\n",
+		},
 	} {
 		t.Run(each.description, func(t *testing.T) {
-			b, err := each.m.marshal(each.input)
+			b, err := each.m.Marshal(each.input)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -138,18 +167,28 @@ func TestMarshallerExt(t *testing.T) {
 			input:       HTMLMarshaller{},
 			want:        Html.String(),
 		},
+		{
+			description: "ndjson ext",
+			input:       NDJSONMarshaller{},
+			want:        Ndjson.String(),
+		},
+		{
+			description: "parquet ext",
+			input:       ParquetMarshaller{},
+			want:        Parquet.String(),
+		},
 	} {
 		t.Run(each.description, func(t *testing.T) {
-			assert.Equal(t, each.want, each.input.ext())
+			assert.Equal(t, each.want, each.input.Ext())
 		})
 	}
 }
 
 func TestWriter(t *testing.T) {
 	t.Run("writer with marshal not working", func(t *testing.T) {
-		w := WriterImpl{dumbMarshaller{}}
+		w := NewWriter(dumbMarshaller{}, nil)
 
-		gotErr := w.Write("./not_existent_file", struct{}{})
+		gotErr := w.Write(context.Background(), "./not_existent_file", struct{}{})
 
 		assert.ErrorIs(t, errDumbMarshaller, gotErr)
 	})
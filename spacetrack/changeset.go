@@ -0,0 +1,133 @@
+package spacetrack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"sort"
+)
+
+const (
+	// Added is the change of the records whose key wasn't in the previous fetch.
+	Added = "added"
+	// Updated is the change of the records whose content is different from the previous fetch.
+	Updated = "updated"
+	// Removed is the change of the keys of the previous fetch which are missing.
+	Removed = "removed"
+)
+
+// Change is an object added, updated or removed between two fetches, identified by its key, see RecordKey.
+type Change struct {
+	Key    string `json:"KEY" xml:"KEY" csv:"KEY" html:"l=KEY,e=span"`
+	Change string `json:"CHANGE" xml:"CHANGE" csv:"CHANGE" html:"l=CHANGE,e=span"`
+}
+
+// Changeset are the changes of the objects between two fetches, sorted by change and key.
+type Changeset struct {
+	XMLName xml.Name `json:"-" xml:"spacetrack-changeset"`
+	Changes []Change `json:"item" xml:"item" html:"item"`
+}
+
+// Count returns the amount of changes of the kind passed, e.g. Added.
+func (cs Changeset) Count(change string) int {
+	var n int
+
+	for _, c := range cs.Changes {
+		if c.Change == change {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Digests are the hashes of the content of the records of a fetch, by their key, see RecordKey and Digest.
+type Digests map[string]string
+
+// RecordKey returns the key which identifies the object of the record between fetches: the NORAD_CAT_ID of tle, or
+// its GP_ID if empty, and the CDM_ID of cdm. Decay has several messages of each object, predictions and the historical
+// one, so its key is the NORAD_CAT_ID, MSG_EPOCH and MSG_TYPE of the message joined by slashes, e.g.
+// 48274/2022-07-30 17:22:00/Prediction
+func RecordKey[T Unit](u T) string {
+	switch t := any(u).(type) {
+	case SpaceTrackTleUnit:
+		if t.NoradCatId == "" {
+			return t.GpId
+		}
+		return t.NoradCatId
+	case SpaceTrackDecayUnit:
+		return t.NoradCatID + "/" + t.MsgEpoch + "/" + t.MsgType
+	case SpaceTrackCdmUnit:
+		return t.CdmID
+	}
+	return ""
+}
+
+// Digest returns the sha256, in hex, of the content of the record.
+func Digest[T Unit](u T) string {
+	// ... the fields of the models are strings, so they can't fail to be encoded.
+	b, _ := json.Marshal(u) //nolint:errcheck
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Changes compares the records fetched with the digests of the previous fetch, returning the records added or updated,
+// the changeset and the digests to compare the next fetch with. If removed is true, the keys of the previous fetch
+// which are missing are reported as removed and dropped from the digests. Otherwise, they are kept, which is what
+// fetches of only some of the records, like incremental ones, need.
+func Changes[T Unit](prev Digests, arr []T, removed bool) ([]T, Changeset, Digests) {
+	var (
+		changed []T
+		cs      = Changeset{Changes: []Change{}}
+		next    = make(Digests, len(prev)+len(arr))
+		seen    = make(map[string]bool, len(arr))
+	)
+
+	if !removed {
+		for k, v := range prev {
+			next[k] = v
+		}
+	}
+
+	for _, u := range arr {
+		key, digest := RecordKey(u), Digest(u)
+		next[key] = digest
+
+		old, ok := prev[key]
+		if ok && old == digest {
+			continue
+		}
+
+		changed = append(changed, u)
+
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if ok {
+			cs.Changes = append(cs.Changes, Change{Key: key, Change: Updated})
+		} else {
+			cs.Changes = append(cs.Changes, Change{Key: key, Change: Added})
+		}
+	}
+
+	if removed {
+		for key := range prev {
+			if _, ok := next[key]; !ok {
+				cs.Changes = append(cs.Changes, Change{Key: key, Change: Removed})
+			}
+		}
+	}
+
+	sort.Slice(cs.Changes, func(i, j int) bool {
+		if cs.Changes[i].Change != cs.Changes[j].Change {
+			return cs.Changes[i].Change < cs.Changes[j].Change
+		}
+		return CompareCursors(cs.Changes[i].Key, cs.Changes[j].Key) < 0
+	})
+
+	return changed, cs, next
+}
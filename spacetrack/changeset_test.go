@@ -0,0 +1,101 @@
+package spacetrack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordKey(t *testing.T) {
+	assert.Equal(t, "25544", RecordKey(SpaceTrackTleUnit{NoradCatId: "25544", GpId: "235813705"}))
+	assert.Equal(t, "235813705", RecordKey(SpaceTrackTleUnit{GpId: "235813705"}))
+	assert.Equal(t, "48274/2022-07-30 17:22:00/Prediction", RecordKey(SpaceTrackDecayUnit{NoradCatID: "48274", MsgEpoch: "2022-07-30 17:22:00", MsgType: "Prediction"}))
+	assert.Equal(t, "436178920", RecordKey(SpaceTrackCdmUnit{CdmID: "436178920"}))
+}
+
+func TestChanges(t *testing.T) {
+	var (
+		iss      = SpaceTrackTleUnit{NoradCatId: "25544", Epoch: "2022-07-30T12:00:00"}
+		issNext  = SpaceTrackTleUnit{NoradCatId: "25544", Epoch: "2022-07-31T12:00:00"}
+		css      = SpaceTrackTleUnit{NoradCatId: "48274", Epoch: "2022-07-30T12:00:00"}
+		vanguard = SpaceTrackTleUnit{NoradCatId: "5", Epoch: "2022-07-30T12:00:00"}
+		prev     = Digests{iss.NoradCatId: Digest(iss), css.NoradCatId: Digest(css)}
+	)
+
+	for _, each := range []struct {
+		description string
+		records     []SpaceTrackTleUnit
+		removed     bool
+		changed     []SpaceTrackTleUnit
+		changes     []Change
+		keys        []string
+	}{
+		{
+			description: "nothing changed",
+			records:     []SpaceTrackTleUnit{iss, css},
+			changes:     []Change{},
+			keys:        []string{"25544", "48274"},
+		},
+		{
+			description: "added and updated records",
+			records:     []SpaceTrackTleUnit{issNext, vanguard, css},
+			changed:     []SpaceTrackTleUnit{issNext, vanguard},
+			changes:     []Change{{Key: "5", Change: Added}, {Key: "25544", Change: Updated}},
+			keys:        []string{"25544", "48274", "5"},
+		},
+		{
+			description: "missing records are kept if they aren't removed",
+			records:     []SpaceTrackTleUnit{vanguard},
+			changed:     []SpaceTrackTleUnit{vanguard},
+			changes:     []Change{{Key: "5", Change: Added}},
+			keys:        []string{"25544", "48274", "5"},
+		},
+		{
+			description: "missing records are removed",
+			records:     []SpaceTrackTleUnit{issNext},
+			removed:     true,
+			changed:     []SpaceTrackTleUnit{issNext},
+			changes:     []Change{{Key: "48274", Change: Removed}, {Key: "25544", Change: Updated}},
+			keys:        []string{"25544"},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			changed, cs, next := Changes(prev, each.records, each.removed)
+
+			var keys []string
+			for k := range next {
+				keys = append(keys, k)
+			}
+
+			assert.Equal(t, each.changed, changed)
+			assert.Equal(t, each.changes, cs.Changes)
+			assert.ElementsMatch(t, each.keys, keys)
+		})
+	}
+
+	t.Run("first fetch adds everything", func(t *testing.T) {
+		changed, cs, next := Changes(nil, []SpaceTrackTleUnit{iss, css}, true)
+
+		assert.Equal(t, []SpaceTrackTleUnit{iss, css}, changed)
+		assert.Equal(t, 2, cs.Count(Added))
+		assert.Equal(t, prev, next)
+	})
+
+	t.Run("several decay messages of one object", func(t *testing.T) {
+		var (
+			prediction = SpaceTrackDecayUnit{NoradCatID: "48274", MsgEpoch: "2022-07-29 10:00:00", MsgType: "Prediction", DecayEpoch: "2022-07-31"}
+			historical = SpaceTrackDecayUnit{NoradCatID: "48274", MsgEpoch: "2022-07-30 17:22:00", MsgType: "Historical", DecayEpoch: "2022-07-30"}
+		)
+
+		changed, cs, next := Changes(nil, []SpaceTrackDecayUnit{prediction, historical}, true)
+
+		assert.Equal(t, []SpaceTrackDecayUnit{prediction, historical}, changed)
+		assert.Equal(t, 2, cs.Count(Added))
+		assert.Len(t, next, 2)
+
+		changed, cs, _ = Changes(next, []SpaceTrackDecayUnit{prediction, historical}, true)
+
+		assert.Empty(t, changed)
+		assert.Empty(t, cs.Changes, "messages which haven't changed must not be updated")
+	})
+}
@@ -0,0 +1,402 @@
+// Package spacetrack is a client of the API of https://www.space-track.org: it authenticates, keeps the session,
+// respects the rate limit of space-track and decodes its classes into typed models.
+package spacetrack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBaseURL is the url of space-track.
+	DefaultBaseURL = "https://www.space-track.org"
+	// DefaultUserAgent is the User-Agent header of the requests.
+	DefaultUserAgent = "go-spacetrack"
+
+	authPath   = "/ajaxauth/login"
+	logoutPath = "/ajaxauth/logout"
+	queryPath  = "/basicspacedata/query"
+)
+
+// Client queries space-track, authenticating the first time and each time the session expires. All the requests of
+// a client share its session and rate limiter, so it is safe for concurrent use and must be shared by the callers.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	userAgent  string
+	metrics    *Metrics
+	logger     atomic.Pointer[zap.Logger]
+	bodyDump   atomic.Pointer[BodyDump]
+	session    session
+	limiter    *rateLimiter
+}
+
+// Option configures a Client, see New.
+type Option func(*Client)
+
+// WithHTTPClient sets the http client of the requests. By default, http.DefaultClient is used.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the url of space-track, e.g. a mirror or a fake server in tests. If empty, DefaultBaseURL is used.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL = strings.TrimSuffix(baseURL, "/"); baseURL != "" {
+			c.baseURL = baseURL
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests. If empty, DefaultUserAgent is used.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		if userAgent != "" {
+			c.userAgent = userAgent
+		}
+	}
+}
+
+// WithLogger sets the logger of the requests, masking the sensitive fields. By default, nothing is logged.
+func WithLogger(l *zap.Logger) Option {
+	return func(c *Client) {
+		c.SetLogger(l)
+	}
+}
+
+// WithRateLimit sets the maximum amount of requests per minute. If zero, DefaultRateLimit is used, see NoRateLimit.
+func WithRateLimit(perMinute int) Option {
+	return func(c *Client) {
+		c.SetRateLimit(perMinute)
+	}
+}
+
+// WithBodyDump logs the bodies of the requests and responses in debug level, see BodyDump.
+func WithBodyDump(bd BodyDump) Option {
+	return func(c *Client) {
+		c.SetBodyDump(bd)
+	}
+}
+
+// WithMetrics records the requests in the metrics passed, which must be registered by the caller. By default,
+// no metrics are recorded.
+func WithMetrics(m *Metrics) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// New returns a client which authenticates with the credentials passed.
+func New(credentials Credentials, opts ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		baseURL:    DefaultBaseURL,
+		userAgent:  DefaultUserAgent,
+		limiter:    newRateLimiter(DefaultRateLimit),
+	}
+	c.session.credentials = credentials
+	c.SetLogger(nil)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// SetCredentials replaces the credentials of the client. The session is kept unless they have changed.
+func (c *Client) SetCredentials(credentials Credentials) {
+	c.session.setCredentials(credentials)
+}
+
+// SetRateLimit replaces the maximum amount of requests per minute. If zero, DefaultRateLimit is used.
+func (c *Client) SetRateLimit(perMinute int) {
+	c.limiter.setRate(perMinute)
+}
+
+// SetLogger replaces the logger of the client, masking the sensitive fields. A nil logger disables the logs.
+func (c *Client) SetLogger(l *zap.Logger) {
+	if l == nil {
+		l = zap.NewNop()
+	}
+	c.logger.Store(l.WithOptions(zap.WrapCore(NewRedactCore)))
+}
+
+// SetBodyDump replaces the configuration of the body dump, see BodyDump.
+func (c *Client) SetBodyDump(bd BodyDump) {
+	c.bodyDump.Store(&bd)
+}
+
+// CheckSession returns ErrNotAuthenticated if there is no authenticated session, e.g. it has expired or the
+// credentials are wrong.
+func (c *Client) CheckSession() error {
+	return c.session.check()
+}
+
+// Login authenticates against space-track, unless there is already an authenticated session.
+func (c *Client) Login(ctx context.Context) error {
+	_, err := c.session.get(ctx, c.authRequest)
+	return err
+}
+
+// Logout ends the session in space-track, if there is one, so the next query authenticates again.
+func (c *Client) Logout(ctx context.Context) error {
+	cookie := c.session.invalidate()
+	if cookie == "" {
+		return nil
+	}
+
+	_, err := c.request(ctx, logoutClass, c.baseURL+logoutPath, cookie)
+	return err
+}
+
+// Query returns the json body of the query, authenticating first if needed. If space-track answers 401, the session
+// is dropped, because it has expired, so the next query authenticates again. Other failures, like timeouts or 5xx,
+// keep the session.
+func (c *Client) Query(ctx context.Context, q Query) ([]byte, error) {
+	cookie, err := c.session.get(ctx, c.authRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.request(ctx, q.Class(), c.baseURL+queryPath+q.Path(), cookie)
+	if err != nil {
+		var se *StatusError
+		if errors.As(err, &se) && se.StatusCode == http.StatusUnauthorized {
+			c.session.invalidate()
+		}
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// Fetch returns the records of the query decoded into the model of its class.
+func Fetch[T Unit](ctx context.Context, c *Client, q Query) ([]T, error) {
+	body, err := c.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := Parse[T](ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	c.metrics.recordsParsed(q.Class(), len(output))
+
+	return output, nil
+}
+
+// Parse decodes the json body of a query into the model of its class.
+func Parse[T Unit](ctx context.Context, input []byte) (output []T, err error) {
+	_, span := telemetry.Start(ctx, "spacetrack.parse", attribute.Int("spacetrack.bytes", len(input)))
+	defer telemetry.End(span, &err)
+
+	if err := json.Unmarshal(input, &output); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("spacetrack.records", len(output)))
+
+	return output, nil
+}
+
+func (c *Client) log() *zap.Logger {
+	return c.logger.Load()
+}
+
+// ... wait blocks until the rate limiter allows the request or the context is done.
+func (c *Client) wait(ctx context.Context) error {
+	delay := c.limiter.reserve()
+	c.metrics.waited(delay)
+
+	if delay > 0 {
+		c.log().Debug("waiting for rate limiter", zap.Duration("delay", delay))
+	}
+
+	return sleep(ctx, delay)
+}
+
+// ... dumpBody logs the body in debug level only if the user has opted in.
+// Callers are responsible of masking credentials inside the body, see redactForm.
+func (c *Client) dumpBody(msg, body string) {
+	bd := c.bodyDump.Load()
+	if bd == nil || !bd.Enabled {
+		return
+	}
+
+	c.log().Debug(msg, zap.Int("body_size", len(body)), zap.String("body", truncateBody(body, bd.limit())))
+}
+
+func (c *Client) authRequest(ctx context.Context, credentials Credentials) (cookie string, err error) {
+	ctx, span := telemetry.Start(ctx, "spacetrack.auth")
+	defer telemetry.End(span, &err)
+
+	defer func() {
+		if err != nil {
+			c.metrics.authFailed()
+		}
+	}()
+
+	form := credentials.encode()
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+authPath, strings.NewReader(form))
+	if err != nil {
+		return "", err
+	}
+
+	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("User-Agent", c.userAgent)
+
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	c.log().Info(strReq(r))
+	c.dumpBody("authentication request body", redactForm(form))
+	res, err := c.doRequest(r, authClass)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	c.metrics.responseBytes(authClass, len(resBody))
+
+	if res.StatusCode != http.StatusOK {
+		c.log().Warn(strRes(res), zap.String("body", truncateBody(string(resBody), DefaultBodyDumpLimit)))
+		return "", &StatusError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+
+	c.log().Info(strRes(res))
+	c.dumpBody("authentication response body", string(resBody))
+
+	// ... space-track answers wrong credentials with 200 and {"Login":"Failed"}, without cookie.
+	cookie = res.Header.Get("set-cookie")
+	if cookie == "" {
+		return "", fmt.Errorf("%w: login failed, check the credentials", ErrNotAuthenticated)
+	}
+
+	return cookie, nil
+}
+
+// ... request fetches the url, which queries the class passed, using the cookie of the session.
+func (c *Client) request(ctx context.Context, class, url, cookie string) (body []byte, err error) {
+	ctx, span := telemetry.Start(ctx, "spacetrack.request", attribute.String("spacetrack.class", class))
+	defer telemetry.End(span, &err)
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header.Add("Cookie", cookie)
+	r.Header.Add("Accept", "application/json")
+	r.Header.Set("User-Agent", c.userAgent)
+
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	c.log().Info(strReq(r))
+	res, err := c.doRequest(r, class)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.metrics.responseBytes(class, len(resBody))
+
+	if res.StatusCode != http.StatusOK {
+		c.log().Warn(strRes(res), zap.String("body", truncateBody(string(resBody), DefaultBodyDumpLimit)))
+		return nil, &StatusError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+
+	c.log().Info(strRes(res), zap.Int("body_size", len(resBody)))
+	c.dumpBody("response body", string(resBody))
+
+	return resBody, nil
+}
+
+// ... doRequest sends the request, measuring its latency and counting it by class and status code. The status code
+// is also added to the span of the request, if any.
+func (c *Client) doRequest(r *http.Request, class string) (*http.Response, error) {
+	var (
+		start = time.Now()
+		span  = trace.SpanFromContext(r.Context())
+	)
+
+	span.SetAttributes(semconv.HTTPMethodKey.String(r.Method), semconv.HTTPURLKey.String(r.URL.String()))
+
+	res, err := c.httpClient.Do(r)
+	if err != nil {
+		c.metrics.request(class, "error", time.Since(start))
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+
+	c.metrics.request(class, strconv.Itoa(res.StatusCode), time.Since(start))
+
+	return res, nil
+}
+
+// ... strRes is the string representation of the response status and headers. Bodies are never included,
+// see dumpBody.
+func strRes(res *http.Response) string {
+	if res == nil {
+		return "error reading response"
+	}
+
+	return fmt.Sprintf("\n%s %s\n\n", res.Status, res.Proto) + strHeaders(res.Header)
+}
+
+// ... strReq is the string representation of the request line and headers. Bodies are never included,
+// see dumpBody.
+func strReq(r *http.Request) string {
+	return fmt.Sprintf("\n%s %s %s\n\n", r.Method, r.URL.Path, r.Proto) + strHeaders(r.Header)
+}
+
+// ... strHeaders prints the headers masking the sensitive ones, like cookies.
+func strHeaders(headers http.Header) string {
+	var sb strings.Builder
+
+	for headerKey, headerValues := range redactHeaders(headers) {
+		var headerValuesLen = len(headerValues)
+		sb.WriteString(headerKey + ": ")
+		for i := 0; i < headerValuesLen; i++ {
+			sb.WriteString(headerValues[i])
+			if i+1 < headerValuesLen {
+				sb.WriteString("; ")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n\n")
+
+	return sb.String()
+}
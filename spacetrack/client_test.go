@@ -0,0 +1,238 @@
+package spacetrack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack/spacetracktest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+const (
+	testIdentity = "someone@example.com"
+	testPassword = "sup3r-s3cr3t-passw0rd"
+	testCookie   = "chocolatechip=t0k3nv4lu3"
+)
+
+var testCredentials = Credentials{Identity: testIdentity, Password: testPassword}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// ... newTestClient returns a client without rate limit whose requests are always answered with the status code and
+// body passed, counting the authentications.
+func newTestClient(statusCode int, body string, opts ...Option) (*Client, *int32) {
+	var logins int32
+
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == authPath {
+			atomic.AddInt32(&logins, 1)
+		}
+
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+			StatusCode: statusCode,
+			Proto:      "HTTP/1.1",
+			Header:     http.Header{"Set-Cookie": []string{testCookie}, "Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	})
+
+	opts = append([]Option{
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithRateLimit(int(time.Minute / time.Microsecond)),
+	}, opts...)
+
+	return New(testCredentials, opts...), &logins
+}
+
+// ... recordSpans replaces the global tracer provider with one which keeps the spans in memory.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	var (
+		exporter = tracetest.NewInMemoryExporter()
+		old      = otel.GetTracerProvider()
+	)
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(old)
+	})
+
+	return exporter
+}
+
+func TestFetch(t *testing.T) {
+	t.Run("records are decoded into the model of the class", func(t *testing.T) {
+		c, logins := newTestClient(http.StatusOK, `[{"NORAD_CAT_ID":"25544","OBJECT_NAME":"ISS (ZARYA)"}]`)
+
+		got, err := Fetch[SpaceTrackDecayUnit](context.Background(), c, Decay.Query(""))
+
+		assert.Nil(t, err)
+		assert.Equal(t, []SpaceTrackDecayUnit{{NoradCatID: "25544", ObjectName: "ISS (ZARYA)"}}, got)
+		assert.Equal(t, int32(1), *logins)
+		assert.Nil(t, c.CheckSession())
+	})
+
+	t.Run("failed queries keep the session", func(t *testing.T) {
+		c, _ := newTestClient(http.StatusInternalServerError, `oops`)
+		c.session.setCookie(testCookie)
+
+		_, err := Fetch[SpaceTrackTleUnit](context.Background(), c, Tle.Query(""))
+
+		var se *StatusError
+		assert.ErrorIs(t, err, ErrUnexpectedStatus)
+		if assert.ErrorAs(t, err, &se) {
+			assert.Equal(t, http.StatusInternalServerError, se.StatusCode)
+		}
+		assert.Nil(t, c.CheckSession(), "only 401 means the session has expired")
+	})
+
+	t.Run("malformed body is an error", func(t *testing.T) {
+		c, _ := newTestClient(http.StatusOK, `not json`)
+
+		_, err := Fetch[SpaceTrackCdmUnit](context.Background(), c, Cdm.Query(""))
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestClientSession(t *testing.T) {
+	s := spacetracktest.NewServer()
+	defer s.Close()
+
+	c := New(Credentials{Identity: spacetracktest.Identity, Password: spacetracktest.Password},
+		WithBaseURL(s.URL),
+		WithRateLimit(int(time.Minute/time.Microsecond)),
+	)
+
+	_, err := c.Query(context.Background(), Tle.Query(""))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, s.Logins())
+
+	t.Run("expired session is dropped and the next query logs in again", func(t *testing.T) {
+		s.ExpireSessions()
+
+		_, err := c.Query(context.Background(), Tle.Query(""))
+		var se *StatusError
+		if assert.ErrorAs(t, err, &se) {
+			assert.Equal(t, http.StatusUnauthorized, se.StatusCode)
+		}
+		assert.ErrorIs(t, c.CheckSession(), ErrNotAuthenticated)
+
+		_, err = c.Query(context.Background(), Tle.Query(""))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, s.Logins())
+	})
+
+	t.Run("logout ends the session", func(t *testing.T) {
+		assert.Nil(t, c.Logout(context.Background()))
+		assert.Equal(t, 1, s.Logouts())
+		assert.ErrorIs(t, c.CheckSession(), ErrNotAuthenticated)
+
+		assert.Nil(t, c.Logout(context.Background()), "logout without session does nothing")
+		assert.Equal(t, 1, s.Logouts())
+	})
+
+	t.Run("wrong credentials are a failed login", func(t *testing.T) {
+		c.SetCredentials(Credentials{Identity: spacetracktest.Identity, Password: "wrong"})
+
+		_, err := c.Query(context.Background(), Tle.Query(""))
+
+		assert.ErrorIs(t, err, ErrNotAuthenticated)
+		assert.Equal(t, 2, s.Logins())
+	})
+}
+
+func TestClientOptions(t *testing.T) {
+	var userAgents []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.UserAgent())
+
+		if r.URL.Path == authPath {
+			http.SetCookie(w, &http.Cookie{Name: "chocolatechip", Value: "t0k3nv4lu3"})
+			return
+		}
+
+		assert.Equal(t, queryPath+Decay.Query("").Path(), r.URL.Path)
+		fmt.Fprint(w, `[]`)
+	}))
+	defer srv.Close()
+
+	c := New(testCredentials, WithBaseURL(srv.URL+"/"), WithUserAgent("go-spacetrack-test/1.0"), WithRateLimit(int(time.Minute/time.Microsecond)))
+
+	_, err := Fetch[SpaceTrackDecayUnit](context.Background(), c, Decay.Query(""))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"go-spacetrack-test/1.0", "go-spacetrack-test/1.0"}, userAgents)
+}
+
+func TestClientMetrics(t *testing.T) {
+	t.Run("successful query", func(t *testing.T) {
+		var (
+			m     = NewMetrics()
+			body  = `[{"NORAD_CAT_ID":"25544"},{"NORAD_CAT_ID":"48274"}]`
+			class = Decay.Class()
+		)
+
+		c, _ := newTestClient(http.StatusOK, body, WithMetrics(m))
+
+		_, err := Fetch[SpaceTrackDecayUnit](context.Background(), c, Decay.Query(""))
+
+		assert.Nil(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.requestsTotal.WithLabelValues(class, "200")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.requestsTotal.WithLabelValues(authClass, "200")))
+		assert.Equal(t, float64(len(body)), testutil.ToFloat64(m.responseBytesTotal.WithLabelValues(class)))
+		assert.Equal(t, float64(2), testutil.ToFloat64(m.recordsParsedTotal.WithLabelValues(class)))
+	})
+
+	t.Run("failed authentication", func(t *testing.T) {
+		m := NewMetrics()
+		c, _ := newTestClient(http.StatusUnauthorized, `{"Login":"Failed"}`, WithMetrics(m))
+
+		assert.ErrorIs(t, c.Login(context.Background()), ErrUnexpectedStatus)
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.authFailuresTotal))
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.requestsTotal.WithLabelValues(authClass, "401")))
+	})
+
+	t.Run("metrics are well formed", func(t *testing.T) {
+		problems, err := testutil.CollectAndLint(NewMetrics())
+
+		assert.Nil(t, err)
+		assert.Empty(t, problems)
+	})
+}
+
+func TestClientTracing(t *testing.T) {
+	t.Run("failed requests are recorded as errors", func(t *testing.T) {
+		exporter := recordSpans(t)
+		c, _ := newTestClient(http.StatusInternalServerError, `oops`)
+
+		_, err := c.request(context.Background(), ClassGP, DefaultBaseURL+queryPath+Tle.Query("").Path(), testCookie)
+		assert.ErrorIs(t, err, ErrUnexpectedStatus)
+
+		spans := exporter.GetSpans()
+		if assert.Len(t, spans, 1) {
+			assert.Equal(t, codes.Error, spans[0].Status.Code)
+			assert.Contains(t, spans[0].Attributes, semconv.HTTPStatusCodeKey.Int(http.StatusInternalServerError))
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package spacetrack
+
+import "strings"
+
+// LatestCursor returns the greatest cursor of the records, see RestCall.CursorField and CompareCursors, being empty if
+// there are no records.
+func LatestCursor[T Unit](arr []T) string {
+	var latest string
+
+	for _, u := range arr {
+		if c := cursor(u); CompareCursors(c, latest) > 0 {
+			latest = c
+		}
+	}
+
+	return latest
+}
+
+// CompareCursors returns -1, 0 or 1 if the cursor a is less, equal or greater than b. Numeric cursors, like GP_ID, are
+// compared as numbers of any size, and the others, like MSG_EPOCH, as strings. Empty cursors are the least ones.
+func CompareCursors(a, b string) int {
+	if isNumeric(a) && isNumeric(b) {
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return strings.Compare(a, b)
+}
+
+// ... cursor returns the value of the cursor field of the record.
+func cursor(u any) string {
+	switch t := u.(type) {
+	case SpaceTrackTleUnit:
+		return t.GpId
+	case SpaceTrackCdmUnit:
+		return t.CdmID
+	case SpaceTrackDecayUnit:
+		return t.MsgEpoch
+	}
+	return ""
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
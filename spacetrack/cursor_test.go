@@ -0,0 +1,33 @@
+package spacetrack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareCursors(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		a, b        string
+		want        int
+	}{
+		{description: "numbers are compared by value", a: "99", b: "100", want: -1},
+		{description: "leading zeros are ignored", a: "00100", b: "100", want: 0},
+		{description: "numbers greater than int64", a: "99999999999999999999", b: "100000000000000000000", want: -1},
+		{description: "dates are compared as strings", a: "2022-07-30 17:22:00", b: "2021-05-09 03:14:00", want: 1},
+		{description: "empty cursor is the least one", a: "", b: "1", want: -1},
+		{description: "empty cursors are equal", want: 0},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.Equal(t, each.want, CompareCursors(each.a, each.b))
+			assert.Equal(t, -each.want, CompareCursors(each.b, each.a))
+		})
+	}
+}
+
+func TestLatestCursor(t *testing.T) {
+	assert.Equal(t, "101293011", LatestCursor([]SpaceTrackTleUnit{{GpId: "99293010"}, {GpId: "101293011"}, {GpId: "101293010"}}))
+	assert.Equal(t, "2022-07-30 17:22:00", LatestCursor([]SpaceTrackDecayUnit{{MsgEpoch: "2022-07-30 17:22:00"}, {MsgEpoch: "2021-05-09 03:14:00"}}))
+	assert.Equal(t, "", LatestCursor([]SpaceTrackCdmUnit{}))
+}
@@ -0,0 +1,170 @@
+package spacetrack
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+const (
+	// Appeared is the status of the objects which are only in the newer catalog.
+	Appeared = "appeared"
+	// Disappeared is the status of the objects which are only in the older catalog.
+	Disappeared = "disappeared"
+	// Decayed is the status of the objects whose decay date is only set in the newer catalog.
+	Decayed = "decayed"
+	// Changed is the status of the objects with some element changed beyond its threshold, see Thresholds.
+	Changed = "changed"
+)
+
+// Thresholds are the minimum absolute differences of the mean elements of an object between two catalogs to report
+// them as changed, e.g. a change of the mean motion may be a maneuver. Zero disables the comparison of an element.
+type Thresholds struct {
+	// Inclination in degrees.
+	Inclination float64 `json:"inclination"`
+	// MeanMotion in revolutions per day.
+	MeanMotion float64 `json:"mean_motion"`
+	// Eccentricity without units.
+	Eccentricity float64 `json:"eccentricity"`
+	// RaOfAscNode, the right ascension of the ascending node, in degrees.
+	RaOfAscNode float64 `json:"ra_of_asc_node"`
+}
+
+// DefaultThresholds are small enough to catch most maneuvers and big enough to ignore the drift between element sets.
+// The right ascension of the ascending node drifts several degrees a day, so it isn't compared.
+var DefaultThresholds = Thresholds{Inclination: 0.01, MeanMotion: 0.001, Eccentricity: 0.0005}
+
+// ElementChange is a mean element of an object whose difference between two catalogs is beyond its threshold.
+type ElementChange struct {
+	// Element is the name of the field, e.g. INCLINATION
+	Element string  `json:"element"`
+	Before  float64 `json:"before"`
+	After   float64 `json:"after"`
+	Delta   float64 `json:"delta"`
+}
+
+// ObjectDiff is an object which has appeared, disappeared, decayed or changed between two catalogs.
+type ObjectDiff struct {
+	NoradCatID string          `json:"norad_cat_id"`
+	ObjectName string          `json:"object_name"`
+	Status     string          `json:"status"`
+	Changes    []ElementChange `json:"changes,omitempty"`
+}
+
+// CatalogDiff are the differences between two catalogs, sorted by status and NORAD_CAT_ID.
+type CatalogDiff struct {
+	Objects []ObjectDiff `json:"objects"`
+}
+
+// Count returns the amount of objects with the status passed, e.g. Appeared.
+func (d CatalogDiff) Count(status string) int {
+	var n int
+
+	for _, o := range d.Objects {
+		if o.Status == status {
+			n++
+		}
+	}
+
+	return n
+}
+
+// ... element is a mean element compared by DiffCatalogs.
+type element struct {
+	name      string
+	value     func(SpaceTrackTleUnit) string
+	threshold func(Thresholds) float64
+	// ... angle elements wrap around 360 degrees.
+	angle bool
+}
+
+var elements = []element{
+	{name: "INCLINATION", value: func(u SpaceTrackTleUnit) string { return u.Inclination }, threshold: func(t Thresholds) float64 { return t.Inclination }},
+	{name: "MEAN_MOTION", value: func(u SpaceTrackTleUnit) string { return u.MeanMotion }, threshold: func(t Thresholds) float64 { return t.MeanMotion }},
+	{name: "ECCENTRICITY", value: func(u SpaceTrackTleUnit) string { return u.Eccentricity }, threshold: func(t Thresholds) float64 { return t.Eccentricity }},
+	{name: "RA_OF_ASC_NODE", value: func(u SpaceTrackTleUnit) string { return u.RaOfAscNode }, threshold: func(t Thresholds) float64 { return t.RaOfAscNode }, angle: true},
+}
+
+// DiffCatalogs compares two catalogs of element sets, e.g. two executions of the tle rest call, by NORAD_CAT_ID. If a
+// catalog has several element sets of an object, like gp_history, the one with the newest EPOCH is compared. Elements
+// which are empty or aren't numbers are not compared.
+func DiffCatalogs(before, after []SpaceTrackTleUnit, t Thresholds) CatalogDiff {
+	var (
+		diff = CatalogDiff{Objects: []ObjectDiff{}}
+		old  = newest(before)
+		cur  = newest(after)
+	)
+
+	for id, a := range cur {
+		b, ok := old[id]
+
+		switch {
+		case !ok:
+			diff.Objects = append(diff.Objects, ObjectDiff{NoradCatID: id, ObjectName: a.ObjectName, Status: Appeared})
+		case b.DecayDate == "" && a.DecayDate != "":
+			diff.Objects = append(diff.Objects, ObjectDiff{NoradCatID: id, ObjectName: a.ObjectName, Status: Decayed})
+		default:
+			if changes := compareElements(b, a, t); len(changes) > 0 {
+				diff.Objects = append(diff.Objects, ObjectDiff{NoradCatID: id, ObjectName: a.ObjectName, Status: Changed, Changes: changes})
+			}
+		}
+	}
+
+	for id, b := range old {
+		if _, ok := cur[id]; !ok {
+			diff.Objects = append(diff.Objects, ObjectDiff{NoradCatID: id, ObjectName: b.ObjectName, Status: Disappeared})
+		}
+	}
+
+	sort.Slice(diff.Objects, func(i, j int) bool {
+		if diff.Objects[i].Status != diff.Objects[j].Status {
+			return diff.Objects[i].Status < diff.Objects[j].Status
+		}
+		return CompareCursors(diff.Objects[i].NoradCatID, diff.Objects[j].NoradCatID) < 0
+	})
+
+	return diff
+}
+
+// ... newest returns the element set with the newest epoch of each object, by NORAD_CAT_ID.
+func newest(arr []SpaceTrackTleUnit) map[string]SpaceTrackTleUnit {
+	output := make(map[string]SpaceTrackTleUnit, len(arr))
+
+	for _, u := range arr {
+		id := RecordKey(u)
+		if prev, ok := output[id]; !ok || u.Epoch > prev.Epoch {
+			output[id] = u
+		}
+	}
+
+	return output
+}
+
+// ... compareElements returns the elements whose difference is beyond their threshold.
+func compareElements(before, after SpaceTrackTleUnit, t Thresholds) []ElementChange {
+	var changes []ElementChange
+
+	for _, e := range elements {
+		threshold := e.threshold(t)
+		if threshold <= 0 {
+			continue
+		}
+
+		b, errB := strconv.ParseFloat(e.value(before), 64)
+		a, errA := strconv.ParseFloat(e.value(after), 64)
+		if errB != nil || errA != nil {
+			continue
+		}
+
+		delta := a - b
+		if e.angle {
+			delta = math.Remainder(delta, 360)
+		}
+
+		if math.Abs(delta) >= threshold {
+			changes = append(changes, ElementChange{Element: e.name, Before: b, After: a, Delta: delta})
+		}
+	}
+
+	return changes
+}
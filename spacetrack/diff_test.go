@@ -0,0 +1,54 @@
+package spacetrack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCatalogs(t *testing.T) {
+	var (
+		iss = SpaceTrackTleUnit{NoradCatId: "25544", ObjectName: "ISS (ZARYA)", Epoch: "2022-07-30T12:00:00", Inclination: "51.6431", MeanMotion: "15.50103472", Eccentricity: "0.0004883", RaOfAscNode: "359.9000"}
+		css = SpaceTrackTleUnit{NoradCatId: "48274", ObjectName: "CSS (TIANHE)", Epoch: "2022-07-30T12:00:00", Inclination: "41.4700", MeanMotion: "15.61893251", Eccentricity: "0.0005190"}
+		vg  = SpaceTrackTleUnit{NoradCatId: "5", ObjectName: "VANGUARD 1", Epoch: "2022-07-30T12:00:00", Inclination: "34.2500", MeanMotion: "10.84869164"}
+		deb = SpaceTrackTleUnit{NoradCatId: "49863", ObjectName: "COSMOS 1408 DEB", Epoch: "2022-07-30T12:00:00"}
+	)
+
+	// ... the iss has been reboosted, so its mean motion has changed, and its node has crossed 0 degrees.
+	issReboosted := iss
+	issReboosted.Epoch, issReboosted.MeanMotion, issReboosted.RaOfAscNode = "2022-07-31T12:00:00", "15.49603472", "0.1000"
+
+	decayed := deb
+	decayed.DecayDate = "2022-07-31"
+
+	t.Run("objects which have appeared, disappeared, decayed and changed", func(t *testing.T) {
+		diff := DiffCatalogs([]SpaceTrackTleUnit{iss, css, deb}, []SpaceTrackTleUnit{issReboosted, vg, decayed}, Thresholds{MeanMotion: 0.001, RaOfAscNode: 0.1})
+
+		var statuses []string
+		for _, o := range diff.Objects {
+			statuses = append(statuses, o.NoradCatID+" "+o.Status)
+		}
+
+		assert.Equal(t, []string{"5 appeared", "25544 changed", "49863 decayed", "48274 disappeared"}, statuses)
+
+		if changes := diff.Objects[1].Changes; assert.Len(t, changes, 2) {
+			assert.Equal(t, "MEAN_MOTION", changes[0].Element)
+			assert.InDelta(t, -0.005, changes[0].Delta, 1e-9)
+			assert.Equal(t, "RA_OF_ASC_NODE", changes[1].Element)
+			assert.InDelta(t, 0.2, changes[1].Delta, 1e-9, "deltas of angles wrap around 360 degrees")
+		}
+		assert.Equal(t, 1, diff.Count(Changed))
+	})
+
+	t.Run("changes below the thresholds are ignored", func(t *testing.T) {
+		diff := DiffCatalogs([]SpaceTrackTleUnit{iss, css}, []SpaceTrackTleUnit{issReboosted, css}, Thresholds{MeanMotion: 0.01})
+
+		assert.Empty(t, diff.Objects)
+	})
+
+	t.Run("newest element set of each object is compared", func(t *testing.T) {
+		diff := DiffCatalogs([]SpaceTrackTleUnit{iss}, []SpaceTrackTleUnit{issReboosted, iss}, DefaultThresholds)
+
+		assert.Equal(t, 1, diff.Count(Changed))
+	})
+}
@@ -0,0 +1,38 @@
+package spacetrack
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	// ErrUnexpectedStatus is returned when space-track answers with a status code other than 200, see StatusError.
+	ErrUnexpectedStatus = errors.New("response status code not 200")
+	// ErrNotAuthenticated is returned when there is no authenticated session, e.g. it has expired or the credentials are wrong.
+	ErrNotAuthenticated = errors.New("not authenticated against space-track")
+	// ErrTransport is returned when the http client can't be built, e.g. the CA file doesn't contain certificates.
+	ErrTransport = errors.New("invalid http transport")
+)
+
+// StatusError is the error returned when space-track answers with a status code other than 200. It matches
+// ErrUnexpectedStatus with errors.Is.
+type StatusError struct {
+	// StatusCode of the response, e.g. 401
+	StatusCode int
+	// Status of the response, e.g. 401 Unauthorized
+	Status string
+}
+
+func (se *StatusError) Error() string {
+	status := se.Status
+	if status == "" {
+		status = strconv.Itoa(se.StatusCode)
+	}
+
+	return ErrUnexpectedStatus.Error() + ": " + status
+}
+
+// Is reports whether the target is ErrUnexpectedStatus.
+func (se *StatusError) Is(target error) bool {
+	return target == ErrUnexpectedStatus
+}
@@ -0,0 +1,127 @@
+package spacetrack
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// ... namespace of all the metrics, e.g. spacetrack_requests_total
+	metricsNamespace = "spacetrack"
+	// ... class labels of the authentication requests, which don't query any class.
+	authClass   = "login"
+	logoutClass = "logout"
+)
+
+// Metrics are the prometheus metrics of the requests of a client to space-track. It is a prometheus.Collector,
+// so it must be registered by the program, see WithMetrics.
+type Metrics struct {
+	requestsTotal      *prometheus.CounterVec
+	requestDuration    *prometheus.HistogramVec
+	responseBytesTotal *prometheus.CounterVec
+	recordsParsedTotal *prometheus.CounterVec
+	rateLimiterWait    prometheus.Histogram
+	authFailuresTotal  prometheus.Counter
+}
+
+// NewMetrics returns the metrics of the requests to space-track, which can be shared by several clients.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "requests_total",
+			Help:      "Requests to space-track by class and status code, being error when there is no response.",
+		}, []string{"class", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the requests to space-track by class.",
+			Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"class"}),
+		responseBytesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "response_bytes_total",
+			Help:      "Bytes downloaded from space-track by class.",
+		}, []string{"class"}),
+		recordsParsedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "records_parsed_total",
+			Help:      "Records parsed from the responses of space-track by class.",
+		}, []string{"class"}),
+		rateLimiterWait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "rate_limiter_wait_seconds",
+			Help:      "Time waited by the requests for the rate limiter.",
+			Buckets:   []float64{0, .5, 1, 2, 5, 10, 30, 60},
+		}),
+		authFailuresTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "auth_failures_total",
+			Help:      "Authentications against space-track which have failed.",
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+}
+
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.requestsTotal,
+		m.requestDuration,
+		m.responseBytesTotal,
+		m.recordsParsedTotal,
+		m.rateLimiterWait,
+		m.authFailuresTotal,
+	}
+}
+
+// ... the methods below do nothing if the metrics are nil, which is the default of the clients.
+
+func (m *Metrics) request(class, code string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.requestDuration.WithLabelValues(class).Observe(d.Seconds())
+	m.requestsTotal.WithLabelValues(class, code).Inc()
+}
+
+func (m *Metrics) responseBytes(class string, n int) {
+	if m == nil {
+		return
+	}
+	m.responseBytesTotal.WithLabelValues(class).Add(float64(n))
+}
+
+func (m *Metrics) recordsParsed(class string, n int) {
+	if m == nil {
+		return
+	}
+	m.recordsParsedTotal.WithLabelValues(class).Add(float64(n))
+}
+
+func (m *Metrics) waited(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.rateLimiterWait.Observe(d.Seconds())
+}
+
+func (m *Metrics) authFailed() {
+	if m == nil {
+		return
+	}
+	m.authFailuresTotal.Inc()
+}
@@ -1,4 +1,4 @@
-package main
+package spacetrack
 
 import (
 	"encoding/xml"
@@ -46,7 +46,7 @@ type SpaceTrackTleUnit struct {
 	Site               string `json:"SITE" xml:"SITE" csv:"SITE" html:"l=SITE,e=span"`
 	DecayDate          string `json:"DECAY_DATE" xml:"DECAY_DATE" csv:"DECAY_DATE" html:"l=DECAY_DATE,e=span"`
 	File               string `json:"FILE" xml:"FILE" csv:"FILE" html:"l=FILE,e=span"`
-	GpId               string `json:"GP_ID" xml:"GP_ID" scv:"GP_ID" html:"l=GP_ID,e=span"`
+	GpId               string `json:"GP_ID" xml:"GP_ID" csv:"GP_ID" html:"l=GP_ID,e=span"`
 	TleLine0           string `json:"TLE_LINE0" xml:"TLE_LINE0" csv:"TLE_LINE0" html:"l=TLE_LINE0,e=span"`
 	TleLine1           string `json:"TLE_LINE1" xml:"TLE_LINE1" csv:"TLE_LINE1" html:"l=TLE_LINE1,e=span"`
 	TleLine2           string `json:"TLE_LINE2" xml:"TLE_LINE2" csv:"TLE_LINE2" html:"l=TLE_LINE2,e=span"`
@@ -54,52 +54,59 @@ type SpaceTrackTleUnit struct {
 
 type SpaceTrackDecay struct {
 	XMLName              xml.Name              `json:"-" xml:"spacetrack-decay"`
-	SpaceTrackDecayUnits []SpaceTrackDecayUnit `json:"item" xml:"item"`
+	SpaceTrackDecayUnits []SpaceTrackDecayUnit `json:"item" xml:"item" html:"item"`
 }
 
 type SpaceTrackDecayUnit struct {
-	XMLName      xml.Name `json:"-" xml:"item"`
-	NoradCatID   string   `json:"NORAD_CAT_ID" xml:"NORAD_CAT_ID"`
-	ObjectNumber string   `json:"OBJECT_NUMBER" xml:"OBJECT_NUMBER"`
-	ObjectName   string   `json:"OBJECT_NAME" xml:"OBJECT_NAME"`
-	IntlDes      string   `json:"INTLDES" xml:"INTLDES"`
-	ObjectID     string   `json:"OBJECT_ID" xml:"OBJECT_ID"`
-	Rcs          string   `json:"RCS" xml:"RCS"`
-	RcsSize      string   `json:"RCS_SIZE" xml:"RCS_SIZE"`
-	Country      string   `json:"COUNTRY" xml:"COUNTRY"`
-	MsgEpoch     string   `json:"MSG_EPOCH" xml:"MSG_EPOCH"`
-	DecayEpoch   string   `json:"DECAY_EPOCH" xml:"DECAY_EPOCH"`
-	Source       string   `json:"SOURCE" xml:"SOURCE"`
-	MsgType      string   `json:"MSG_TYPE" xml:"MSG_TYPE"`
-	Precedence   string   `json:"PRECEDENCE" xml:"PRECEDENCE"`
+	XMLName      xml.Name `json:"-" xml:"item" csv:"-"`
+	NoradCatID   string   `json:"NORAD_CAT_ID" xml:"NORAD_CAT_ID" csv:"NORAD_CAT_ID" html:"l=NORAD_CAT_ID,e=span"`
+	ObjectNumber string   `json:"OBJECT_NUMBER" xml:"OBJECT_NUMBER" csv:"OBJECT_NUMBER" html:"l=OBJECT_NUMBER,e=span"`
+	ObjectName   string   `json:"OBJECT_NAME" xml:"OBJECT_NAME" csv:"OBJECT_NAME" html:"l=OBJECT_NAME,e=span"`
+	IntlDes      string   `json:"INTLDES" xml:"INTLDES" csv:"INTLDES" html:"l=INTLDES,e=span"`
+	ObjectID     string   `json:"OBJECT_ID" xml:"OBJECT_ID" csv:"OBJECT_ID" html:"l=OBJECT_ID,e=span"`
+	Rcs          string   `json:"RCS" xml:"RCS" csv:"RCS" html:"l=RCS,e=span"`
+	RcsSize      string   `json:"RCS_SIZE" xml:"RCS_SIZE" csv:"RCS_SIZE" html:"l=RCS_SIZE,e=span"`
+	Country      string   `json:"COUNTRY" xml:"COUNTRY" csv:"COUNTRY" html:"l=COUNTRY,e=span"`
+	MsgEpoch     string   `json:"MSG_EPOCH" xml:"MSG_EPOCH" csv:"MSG_EPOCH" html:"l=MSG_EPOCH,e=span"`
+	DecayEpoch   string   `json:"DECAY_EPOCH" xml:"DECAY_EPOCH" csv:"DECAY_EPOCH" html:"l=DECAY_EPOCH,e=span"`
+	Source       string   `json:"SOURCE" xml:"SOURCE" csv:"SOURCE" html:"l=SOURCE,e=span"`
+	MsgType      string   `json:"MSG_TYPE" xml:"MSG_TYPE" csv:"MSG_TYPE" html:"l=MSG_TYPE,e=span"`
+	Precedence   string   `json:"PRECEDENCE" xml:"PRECEDENCE" csv:"PRECEDENCE" html:"l=PRECEDENCE,e=span"`
 }
 
 type SpaceTrackCdm struct {
 	XMLName            xml.Name            `json:"-" xml:"spacetrack-cdm"`
-	SpaceTrackCdmUnits []SpaceTrackCdmUnit `json:"item" xml:"item"`
+	SpaceTrackCdmUnits []SpaceTrackCdmUnit `json:"item" xml:"item" html:"item"`
 }
 
 type SpaceTrackCdmUnit struct {
-	XMLName             xml.Name `json:"-" xml:"item"`
-	CdmID               string   `json:"CDM_ID" xml:"CDM_ID"`
-	Created             string   `json:"CREATED" xml:"CREATED"`
-	EmergencyReportable string   `json:"EMERGENCY_REPORTABLE" xml:"EMERGENCY_REPORTABLE"`
-	Tca                 string   `json:"TCA" xml:"TCA"`
-	MinRng              string   `json:"MIN_RNG" xml:"MIN_RNG"`
-	Pc                  string   `json:"PC" xml:"PC"`
-	FirstSatID          string   `json:"SAT_1_ID" xml:"SAT_1_ID"`
-	FirstSatName        string   `json:"SAT_1_NAME" xml:"SAT_1_NAME"`
-	FirstSatObjectType  string   `json:"SAT1_OBJECT_TYPE" xml:"SAT1_OBJECT_TYPE"`
-	FirstSatRcs         string   `json:"SAT1_RCS" xml:"SAT1_RCS"`
-	FirstSatExclVol     string   `json:"SAT_1_EXCL_VOL" xml:"SAT_1_EXCL_VOL"`
-	SecondSatID         string   `json:"SAT_2_ID" xml:"SAT_2_ID"`
-	SecondSatName       string   `json:"SAT_2_NAME" xml:"SAT_2_NAME"`
-	SecondSatObjectType string   `json:"SAT2_OBJECT_TYPE" xml:"SAT2_OBJECT_TYPE"`
-	SecondSatRcs        string   `json:"SAT2_RCS" xml:"SAT2_RCS"`
-	SecondSatExclVol    string   `json:"SAT_2_EXCL_VOL" xml:"SAT_2_EXCL_VOL"`
+	XMLName             xml.Name `json:"-" xml:"item" csv:"-"`
+	CdmID               string   `json:"CDM_ID" xml:"CDM_ID" csv:"CDM_ID" html:"l=CDM_ID,e=span"`
+	Created             string   `json:"CREATED" xml:"CREATED" csv:"CREATED" html:"l=CREATED,e=span"`
+	EmergencyReportable string   `json:"EMERGENCY_REPORTABLE" xml:"EMERGENCY_REPORTABLE" csv:"EMERGENCY_REPORTABLE" html:"l=EMERGENCY_REPORTABLE,e=span"`
+	Tca                 string   `json:"TCA" xml:"TCA" csv:"TCA" html:"l=TCA,e=span"`
+	MinRng              string   `json:"MIN_RNG" xml:"MIN_RNG" csv:"MIN_RNG" html:"l=MIN_RNG,e=span"`
+	Pc                  string   `json:"PC" xml:"PC" csv:"PC" html:"l=PC,e=span"`
+	FirstSatID          string   `json:"SAT_1_ID" xml:"SAT_1_ID" csv:"SAT_1_ID" html:"l=SAT_1_ID,e=span"`
+	FirstSatName        string   `json:"SAT_1_NAME" xml:"SAT_1_NAME" csv:"SAT_1_NAME" html:"l=SAT_1_NAME,e=span"`
+	FirstSatObjectType  string   `json:"SAT1_OBJECT_TYPE" xml:"SAT1_OBJECT_TYPE" csv:"SAT1_OBJECT_TYPE" html:"l=SAT1_OBJECT_TYPE,e=span"`
+	FirstSatRcs         string   `json:"SAT1_RCS" xml:"SAT1_RCS" csv:"SAT1_RCS" html:"l=SAT1_RCS,e=span"`
+	FirstSatExclVol     string   `json:"SAT_1_EXCL_VOL" xml:"SAT_1_EXCL_VOL" csv:"SAT_1_EXCL_VOL" html:"l=SAT_1_EXCL_VOL,e=span"`
+	SecondSatID         string   `json:"SAT_2_ID" xml:"SAT_2_ID" csv:"SAT_2_ID" html:"l=SAT_2_ID,e=span"`
+	SecondSatName       string   `json:"SAT_2_NAME" xml:"SAT_2_NAME" csv:"SAT_2_NAME" html:"l=SAT_2_NAME,e=span"`
+	SecondSatObjectType string   `json:"SAT2_OBJECT_TYPE" xml:"SAT2_OBJECT_TYPE" csv:"SAT2_OBJECT_TYPE" html:"l=SAT2_OBJECT_TYPE,e=span"`
+	SecondSatRcs        string   `json:"SAT2_RCS" xml:"SAT2_RCS" csv:"SAT2_RCS" html:"l=SAT2_RCS,e=span"`
+	SecondSatExclVol    string   `json:"SAT_2_EXCL_VOL" xml:"SAT_2_EXCL_VOL" csv:"SAT_2_EXCL_VOL" html:"l=SAT_2_EXCL_VOL,e=span"`
 }
 
-func newSpaceTrackObjFromArr[T SpaceTrackTleUnit | SpaceTrackDecayUnit | SpaceTrackCdmUnit](arr []T) any {
+// Unit is any of the records returned by space-track.
+type Unit interface {
+	SpaceTrackTleUnit | SpaceTrackDecayUnit | SpaceTrackCdmUnit
+}
+
+// Wrap returns the records inside the root element of their class, e.g. SpaceTrackTle for SpaceTrackTleUnit, which
+// is what the marshallers expect.
+func Wrap[T Unit](arr []T) any {
 	switch t := any(arr).(type) {
 	case []SpaceTrackTleUnit:
 		return SpaceTrackTle{SpaceTrackTleUnits: t}
@@ -111,7 +118,8 @@ func newSpaceTrackObjFromArr[T SpaceTrackTleUnit | SpaceTrackDecayUnit | SpaceTr
 	return nil
 }
 
-func newArrSpaceTrackObj(input any, oneE bool) []any {
+// Split returns the root element passed, see Wrap, as the only item if oneE is true, or one root element per record otherwise.
+func Split(input any, oneE bool) []any {
 	switch t := any(input).(type) {
 	case SpaceTrackTle:
 		if oneE {
@@ -149,3 +157,13 @@ func newArrSpaceTrackObj(input any, oneE bool) []any {
 	}
 	return nil
 }
+
+func arrToAny[T any](src []T) []any {
+	var dst = make([]any, len(src))
+
+	for i := range src {
+		dst[i] = any(src[i])
+	}
+
+	return dst
+}
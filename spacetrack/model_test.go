@@ -0,0 +1,46 @@
+package spacetrack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	t.Run("space-track tle unit", func(t *testing.T) {
+		var input = make([]SpaceTrackTleUnit, 10)
+
+		assert.IsType(t, SpaceTrackTle{}, Wrap(input))
+	})
+
+	t.Run("space-track tle decay", func(t *testing.T) {
+		var input = make([]SpaceTrackDecayUnit, 10)
+
+		assert.IsType(t, SpaceTrackDecay{}, Wrap(input))
+	})
+
+	t.Run("space-track tle cdm", func(t *testing.T) {
+		var input = make([]SpaceTrackCdmUnit, 10)
+
+		assert.IsType(t, SpaceTrackCdm{}, Wrap(input))
+	})
+}
+
+func TestSplit(t *testing.T) {
+	var input = Wrap([]SpaceTrackDecayUnit{{NoradCatID: "25544"}, {NoradCatID: "48274"}})
+
+	t.Run("one element holds all the records", func(t *testing.T) {
+		assert.Equal(t, []any{input}, Split(input, true))
+	})
+
+	t.Run("one element per record", func(t *testing.T) {
+		assert.Equal(t, []any{
+			SpaceTrackDecay{SpaceTrackDecayUnits: []SpaceTrackDecayUnit{{NoradCatID: "25544"}}},
+			SpaceTrackDecay{SpaceTrackDecayUnits: []SpaceTrackDecayUnit{{NoradCatID: "48274"}}},
+		}, Split(input, false))
+	})
+
+	t.Run("unknown element", func(t *testing.T) {
+		assert.Nil(t, Split("not a space-track element", false))
+	})
+}
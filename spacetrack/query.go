@@ -0,0 +1,142 @@
+package spacetrack
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ClassGP is the class of the general perturbations, the latest element set of each object.
+	ClassGP = "gp"
+	// ClassGPHistory is the class of every element set published of each object, to fetch past periods of time.
+	ClassGPHistory = "gp_history"
+	// ClassCdmPublic is the class of the public conjunction data messages.
+	ClassCdmPublic = "cdm_public"
+	// ClassDecay is the class of the predicted and historical decays.
+	ClassDecay = "decay"
+
+	// ... default predicates of each rest call, which can be replaced by the query of each job.
+	tleQuery   = "DECAY_DATE/null-val/EPOCH/>now-1/orderby/NORAD_CAT_ID asc"
+	decayQuery = "DECAY_EPOCH/>now-1/orderby/NORAD_CAT_ID asc"
+	cdmQuery   = "CREATED/>now-1/orderby/CDM_ID asc"
+	// ... default predicates of each rest call fetching only the records newer than a cursor, see RestCall.IncrementalQuery.
+	tleIncrementalQuery   = "DECAY_DATE/null-val/GP_ID/>%s/orderby/GP_ID asc"
+	decayIncrementalQuery = "MSG_EPOCH/>%s/orderby/MSG_EPOCH asc"
+	cdmIncrementalQuery   = "CDM_ID/>%s/orderby/CDM_ID asc"
+	// ... layout of the times of the ranges, see Query.Between.
+	rangeLayout = "2006-01-02 15:04:05.000000"
+	// ... suffix of every query, so we always get a json, even if there are no results.
+	querySuffix = "/format/json/emptyresult/show"
+)
+
+// ... queryModifiers are the predicates which aren't filters, so they go after them, see InsertPredicate.
+var queryModifiers = map[string]bool{
+	"orderby": true, "limit": true, "metadata": true, "distinct": true, "predicates": true, "favorites": true,
+	"format": true, "emptyresult": true,
+}
+
+// Query is a request to the query controller of space-track: the class to fetch and the predicates to filter it, e.g.
+//
+//	NewQuery(ClassGP).Where("NORAD_CAT_ID", "25544").OrderBy("EPOCH desc").Limit(1)
+//
+// Queries are values, so each method returns a new one and the original can be reused.
+type Query struct {
+	class      string
+	predicates []string
+}
+
+// NewQuery returns a query of every record of the class.
+func NewQuery(class string) Query {
+	return Query{class: class}
+}
+
+// Class is the class queried.
+func (q Query) Class() string {
+	return q.class
+}
+
+// Where filters the field by the value, which can use the operators of space-track, e.g. >now-1, null-val or 1,2,3
+func (q Query) Where(field, value string) Query {
+	return q.with(field, value)
+}
+
+// OrderBy sorts the records by the fields, each one followed by asc or desc, e.g. NORAD_CAT_ID asc
+func (q Query) OrderBy(fields ...string) Query {
+	return q.with("orderby", strings.Join(fields, ","))
+}
+
+// Limit returns at most n records.
+func (q Query) Limit(n int) Query {
+	return q.with("limit", strconv.Itoa(n))
+}
+
+// Between filters the field by the range of times from and to, both included, e.g. EPOCH/2022-01-01 00:00:00--2022-01-01 23:59:59.999999
+// Times are sent in UTC with microseconds, the precision of space-track.
+func (q Query) Between(field string, from, to time.Time) Query {
+	return q.with(field, from.UTC().Format(rangeLayout)+"--"+to.UTC().Format(rangeLayout))
+}
+
+// Predicates appends predicates already joined by slashes, like the queries of the configuration,
+// e.g. NORAD_CAT_ID/25544/orderby/EPOCH desc
+func (q Query) Predicates(predicates string) Query {
+	if predicates = strings.Trim(predicates, "/"); predicates == "" {
+		return q
+	}
+	return q.with(predicates)
+}
+
+// InsertPredicate returns the predicates already joined by slashes with the field filtered by the value, placing it
+// before the modifiers, like orderby or limit, which space-track expects after every filter, e.g. the field GP_ID and
+// the value >101 turn NORAD_CAT_ID/25544/orderby/EPOCH desc into NORAD_CAT_ID/25544/GP_ID/>101/orderby/EPOCH desc
+func InsertPredicate(predicates, field, value string) string {
+	if predicates = strings.Trim(predicates, "/"); predicates == "" {
+		return field + "/" + value
+	}
+
+	var (
+		segments = strings.Split(predicates, "/")
+		at       = len(segments)
+	)
+
+	// ... predicates are pairs of a field, or a modifier, and its value.
+	for i := 0; i < len(segments); i += 2 {
+		if queryModifiers[strings.ToLower(segments[i])] {
+			at = i
+			break
+		}
+	}
+
+	output := make([]string, 0, len(segments)+2)
+	output = append(output, segments[:at]...)
+	output = append(output, field, value)
+	output = append(output, segments[at:]...)
+
+	return strings.Join(output, "/")
+}
+
+// Path is the path of the query below the query controller, always asking for a json even if there are no results.
+func (q Query) Path() string {
+	var sb strings.Builder
+
+	sb.WriteString("/class/" + q.class)
+	for _, p := range q.predicates {
+		sb.WriteString("/" + p)
+	}
+	sb.WriteString(querySuffix)
+
+	return sb.String()
+}
+
+func (q Query) String() string {
+	return q.Path()
+}
+
+// ... with returns a copy of the query with the predicates appended, so queries sharing a parent don't share its slice.
+func (q Query) with(predicates ...string) Query {
+	output := make([]string, 0, len(q.predicates)+len(predicates))
+	output = append(output, q.predicates...)
+	q.predicates = append(output, predicates...)
+
+	return q
+}
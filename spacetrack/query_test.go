@@ -0,0 +1,107 @@
+package spacetrack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		query       Query
+		want        string
+	}{
+		{
+			description: "every record of the class",
+			query:       NewQuery(ClassGP),
+			want:        "/class/gp/format/json/emptyresult/show",
+		},
+		{
+			description: "filtered, sorted and limited",
+			query:       NewQuery(ClassGP).Where("NORAD_CAT_ID", "25544").OrderBy("EPOCH desc").Limit(1),
+			want:        "/class/gp/NORAD_CAT_ID/25544/orderby/EPOCH desc/limit/1/format/json/emptyresult/show",
+		},
+		{
+			description: "several fields to sort",
+			query:       NewQuery(ClassCdmPublic).OrderBy("TCA asc", "CDM_ID asc"),
+			want:        "/class/cdm_public/orderby/TCA asc,CDM_ID asc/format/json/emptyresult/show",
+		},
+		{
+			description: "raw predicates are trimmed",
+			query:       NewQuery(ClassDecay).Predicates("/DECAY_EPOCH/>now-1/"),
+			want:        "/class/decay/DECAY_EPOCH/>now-1/format/json/emptyresult/show",
+		},
+		{
+			description: "range of times in utc",
+			query:       NewQuery(ClassGPHistory).Between("EPOCH", time.Date(2022, 1, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600)), time.Date(2022, 1, 1, 23, 59, 59, 999999000, time.UTC)),
+			want:        "/class/gp_history/EPOCH/2022-01-01 00:00:00.000000--2022-01-01 23:59:59.999999/format/json/emptyresult/show",
+		},
+		{
+			description: "empty raw predicates",
+			query:       NewQuery(ClassDecay).Predicates(""),
+			want:        "/class/decay/format/json/emptyresult/show",
+		},
+		{
+			description: "rest call with its default predicates",
+			query:       Tle.Query(""),
+			want:        "/class/gp/" + tleQuery + querySuffix,
+		},
+		{
+			description: "rest call with custom predicates",
+			query:       Cdm.Query("CDM_ID/1"),
+			want:        "/class/cdm_public/CDM_ID/1" + querySuffix,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.Equal(t, each.want, each.query.Path())
+		})
+	}
+
+	t.Run("queries sharing a parent are independent", func(t *testing.T) {
+		parent := NewQuery(ClassGP).Where("OBJECT_TYPE", "PAYLOAD")
+		iss, css := parent.Where("NORAD_CAT_ID", "25544"), parent.Where("NORAD_CAT_ID", "48274")
+
+		assert.Equal(t, "/class/gp/OBJECT_TYPE/PAYLOAD/NORAD_CAT_ID/25544"+querySuffix, iss.Path())
+		assert.Equal(t, "/class/gp/OBJECT_TYPE/PAYLOAD/NORAD_CAT_ID/48274"+querySuffix, css.Path())
+	})
+}
+
+func TestInsertPredicate(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		predicates  string
+		want        string
+	}{
+		{
+			description: "no predicates",
+			predicates:  "",
+			want:        "GP_ID/>101",
+		},
+		{
+			description: "filters only",
+			predicates:  "NORAD_CAT_ID/25544/",
+			want:        "NORAD_CAT_ID/25544/GP_ID/>101",
+		},
+		{
+			description: "before orderby",
+			predicates:  "NORAD_CAT_ID/25544/orderby/EPOCH desc",
+			want:        "NORAD_CAT_ID/25544/GP_ID/>101/orderby/EPOCH desc",
+		},
+		{
+			description: "before limit, whatever its case",
+			predicates:  "OBJECT_TYPE/PAYLOAD/LIMIT/10/orderby/EPOCH desc",
+			want:        "OBJECT_TYPE/PAYLOAD/GP_ID/>101/LIMIT/10/orderby/EPOCH desc",
+		},
+		{
+			description: "only modifiers",
+			predicates:  "orderby/GP_ID asc",
+			want:        "GP_ID/>101/orderby/GP_ID asc",
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.Equal(t, each.want, InsertPredicate(each.predicates, "GP_ID", ">101"))
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package spacetrack
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// ... extensions of the files of each recording: the metadata and the raw body of the response.
+	recordingExt = ".json"
+	bodyExt      = ".body"
+	// ... layout of the time prefix of the recordings, so sorting them by name sorts them by time.
+	recordingTimeLayout = "20060102T150405.000000000Z"
+)
+
+var (
+	// ErrRecording is returned when a response can't be recorded or a recording can't be read.
+	ErrRecording = errors.New("recording")
+	// ErrNoRecording is returned when replaying a request which wasn't recorded, or whose recordings have been
+	// already replayed.
+	ErrNoRecording = errors.New("no recording of the request")
+)
+
+// Recording is a request to space-track and its response, saved by a Recorder. The body of the response is kept byte
+// for byte in a sibling file, BodyFile, and its checksum is verified when it is replayed.
+type Recording struct {
+	// Time when the request was sent.
+	Time time.Time `json:"time"`
+	// Duration of the request until the whole body was read.
+	Duration time.Duration `json:"duration"`
+	// Request sent. Its body, if any, is never recorded, because it contains the credentials.
+	Request RecordedRequest `json:"request"`
+	// Response received.
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the metadata of a recorded request, whose sensitive headers are masked.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+}
+
+// RecordedResponse is the metadata of a recorded response, whose sensitive headers, like the cookie, are masked.
+type RecordedResponse struct {
+	Status     string      `json:"status"`
+	StatusCode int         `json:"status_code"`
+	Proto      string      `json:"proto"`
+	Header     http.Header `json:"header"`
+	// BodyFile is the name of the file with the body, relative to the recording.
+	BodyFile string `json:"body_file"`
+	// BodySize is the amount of bytes of the body.
+	BodySize int `json:"body_size"`
+	// BodySHA256 is the hex encoded checksum of the body.
+	BodySHA256 string `json:"body_sha256"`
+}
+
+// Recorder is an http.RoundTripper which saves every response, with the metadata of its request, into a dir, so they
+// can be replayed later, see NewReplayer. Requests which fail without response aren't recorded.
+type Recorder struct {
+	next http.RoundTripper
+	dir  string
+	seq  uint64
+}
+
+// NewRecorder returns a recorder which sends the requests with the round tripper passed, or http.DefaultTransport if
+// nil, recording them into dir. The dir is created if it doesn't exist.
+func NewRecorder(dir string, next http.RoundTripper) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRecording, err)
+	}
+
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Recorder{next: next, dir: dir}, nil
+}
+
+// RoundTrip sends the request and records its response, returning an error wrapping ErrRecording if the response
+// can't be saved, so a recorded run is never incomplete without noticing.
+func (rec *Recorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	res, err := rec.next.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := rec.save(r, res, body, start); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRecording, err)
+	}
+
+	return res, nil
+}
+
+// ... save writes the body and the metadata of the response, named after the time of the request, a sequence number,
+// so concurrent requests don't collide, and the endpoint, e.g. 20230102T150405.000000000Z-000001-gp.json
+func (rec *Recorder) save(r *http.Request, res *http.Response, body []byte, start time.Time) error {
+	var (
+		name     = fmt.Sprintf("%s-%06d-%s", start.UTC().Format(recordingTimeLayout), atomic.AddUint64(&rec.seq, 1), endpoint(r))
+		checksum = sha256.Sum256(body)
+	)
+
+	recording := Recording{
+		Time:     start,
+		Duration: time.Since(start),
+		Request: RecordedRequest{
+			Method: r.Method,
+			URL:    r.URL.String(),
+			Header: redactHeaders(r.Header),
+		},
+		Response: RecordedResponse{
+			Status:     res.Status,
+			StatusCode: res.StatusCode,
+			Proto:      res.Proto,
+			Header:     redactHeaders(res.Header),
+			BodyFile:   name + bodyExt,
+			BodySize:   len(body),
+			BodySHA256: hex.EncodeToString(checksum[:]),
+		},
+	}
+
+	metadata, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(rec.dir, name+bodyExt), body, 0640); err != nil {
+		return err
+	}
+
+	// ... the metadata is written the last one, so replayers never find a recording without body.
+	return os.WriteFile(filepath.Join(rec.dir, name+recordingExt), metadata, 0640)
+}
+
+// ... endpoint names the request in the recordings: the class of the queries or the last segment of the path otherwise,
+// e.g. gp or login.
+func endpoint(r *http.Request) string {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	for i := range segments {
+		if segments[i] == "class" && i+1 < len(segments) {
+			return segments[i+1]
+		}
+	}
+
+	return path.Base(r.URL.Path)
+}
+
+// Replayer is an http.RoundTripper which answers the requests with the responses recorded by a Recorder, without
+// network. Each request is answered with the oldest recording of the same method and url, ignoring the host, which
+// hasn't been replayed yet, so a run is replayed in the same order it was recorded.
+type Replayer struct {
+	dir string
+
+	mu         sync.Mutex
+	recordings []Recording
+	replayed   []bool
+}
+
+// NewReplayer reads the recordings of dir. It returns an error wrapping ErrRecording if they can't be read or there
+// are none.
+func NewReplayer(dir string) (*Replayer, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+recordingExt))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRecording, err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no recordings found in %s", ErrRecording, dir)
+	}
+
+	// ... names start with the time of the request, so this is the order in which they were recorded.
+	sort.Strings(files)
+
+	rep := &Replayer{dir: dir, recordings: make([]Recording, len(files)), replayed: make([]bool, len(files))}
+
+	for i, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRecording, err)
+		}
+
+		if err := json.Unmarshal(b, &rep.recordings[i]); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrRecording, f, err)
+		}
+	}
+
+	return rep, nil
+}
+
+// RoundTrip answers the request with its next recording, returning an error wrapping ErrNoRecording if there is none,
+// or ErrRecording if the body of the recording doesn't match its checksum.
+func (rep *Replayer) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body != nil {
+		r.Body.Close()
+	}
+
+	recording, err := rep.next(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := os.ReadFile(filepath.Join(rep.dir, recording.Response.BodyFile))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRecording, err)
+	}
+
+	if checksum := sha256.Sum256(body); hex.EncodeToString(checksum[:]) != recording.Response.BodySHA256 {
+		return nil, fmt.Errorf("%w: body of %s doesn't match its checksum", ErrRecording, recording.Response.BodyFile)
+	}
+
+	return &http.Response{
+		Status:        recording.Response.Status,
+		StatusCode:    recording.Response.StatusCode,
+		Proto:         recording.Response.Proto,
+		Header:        recording.Response.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}, nil
+}
+
+// ... next returns the oldest recording of the request which hasn't been replayed yet, marking it as replayed.
+func (rep *Replayer) next(r *http.Request) (Recording, error) {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	for i, recording := range rep.recordings {
+		if rep.replayed[i] || recording.Request.Method != r.Method {
+			continue
+		}
+
+		u, err := r.URL.Parse(recording.Request.URL)
+		if err != nil || u.RequestURI() != r.URL.RequestURI() {
+			continue
+		}
+
+		rep.replayed[i] = true
+
+		return recording, nil
+	}
+
+	return Recording{}, fmt.Errorf("%w: %s %s", ErrNoRecording, r.Method, r.URL.RequestURI())
+}
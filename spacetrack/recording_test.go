@@ -0,0 +1,161 @@
+package spacetrack
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack/spacetracktest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ... recordRun records a login and the queries passed against the fake server, returning their bodies.
+func recordRun(t *testing.T, dir string, queries ...Query) [][]byte {
+	s := spacetracktest.NewServer()
+	defer s.Close()
+
+	rec, err := NewRecorder(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(Credentials{Identity: spacetracktest.Identity, Password: spacetracktest.Password},
+		WithBaseURL(s.URL), WithHTTPClient(&http.Client{Transport: rec}), WithRateLimit(NoRateLimit))
+
+	bodies := make([][]byte, len(queries))
+	for i, q := range queries {
+		if bodies[i], err = c.Query(context.Background(), q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return bodies
+}
+
+// ... newReplayClient returns a client answered by the recordings of dir, whose base url doesn't exist.
+func newReplayClient(t *testing.T, dir string) *Client {
+	rep, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return New(Credentials{}, WithBaseURL("http://replay.invalid"), WithHTTPClient(&http.Client{Transport: rep}), WithRateLimit(NoRateLimit))
+}
+
+func TestRecordReplay(t *testing.T) {
+	var (
+		dir     = t.TempDir()
+		queries = []Query{Tle.Query(""), Decay.Query(""), Tle.Query("")}
+		bodies  = recordRun(t, dir, queries...)
+	)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, files, 8, "metadata and body of the login and each query")
+
+	t.Run("secrets aren't recorded", func(t *testing.T) {
+		for _, f := range files {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.NotContains(t, string(b), spacetracktest.Password, f)
+			assert.NotContains(t, string(b), "chocolatechip=", f)
+		}
+	})
+
+	t.Run("run is replayed byte for byte without network", func(t *testing.T) {
+		c := newReplayClient(t, dir)
+
+		for i, q := range queries {
+			got, err := c.Query(context.Background(), q)
+
+			assert.Nil(t, err)
+			assert.Equal(t, bodies[i], got)
+		}
+
+		_, err := c.Query(context.Background(), Tle.Query(""))
+		assert.ErrorIs(t, err, ErrNoRecording, "each recording is replayed once")
+	})
+
+	t.Run("replayed records are parsed", func(t *testing.T) {
+		got, err := Fetch[SpaceTrackDecayUnit](context.Background(), newReplayClient(t, dir), Decay.Query(""))
+
+		assert.Nil(t, err)
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("query which wasn't recorded", func(t *testing.T) {
+		_, err := newReplayClient(t, dir).Query(context.Background(), Cdm.Query(""))
+
+		assert.ErrorIs(t, err, ErrNoRecording)
+	})
+
+	t.Run("body which doesn't match its checksum", func(t *testing.T) {
+		bodyFiles, err := filepath.Glob(filepath.Join(dir, "*-gp"+bodyExt))
+		if err != nil || len(bodyFiles) == 0 {
+			t.Fatal(bodyFiles, err)
+		}
+
+		c := newReplayClient(t, dir)
+
+		if err := os.WriteFile(bodyFiles[0], []byte(`[]`), 0640); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = c.Query(context.Background(), Tle.Query(""))
+		assert.ErrorIs(t, err, ErrRecording)
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("requests which fail aren't recorded", func(t *testing.T) {
+		dir := t.TempDir()
+
+		rec, err := NewRecorder(dir, roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, context.DeadlineExceeded
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = (&http.Client{Transport: rec, Timeout: time.Second}).Get("http://replay.invalid/ajaxauth/login")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		files, _ := os.ReadDir(dir)
+		assert.Empty(t, files)
+	})
+
+	t.Run("dir which can't be created", func(t *testing.T) {
+		f := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(f, nil, 0640); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := NewRecorder(filepath.Join(f, "recordings"), nil)
+		assert.ErrorIs(t, err, ErrRecording)
+	})
+
+	t.Run("recordings are named after their endpoint", func(t *testing.T) {
+		for path, want := range map[string]string{
+			"/ajaxauth/login": "login",
+			"/basicspacedata/query/class/cdm_public/CREATED/>now-1/format/json": "cdm_public",
+		} {
+			r, _ := http.NewRequest(http.MethodGet, "http://replay.invalid"+strings.ReplaceAll(path, ">", "%3E"), nil)
+			assert.Equal(t, want, endpoint(r))
+		}
+	})
+}
+
+func TestNewReplayer(t *testing.T) {
+	_, err := NewReplayer(t.TempDir())
+
+	assert.ErrorIs(t, err, ErrRecording)
+}
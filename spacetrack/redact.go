@@ -0,0 +1,197 @@
+package spacetrack
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// RedactedValue is logged instead of any secret.
+	RedactedValue = "[REDACTED]"
+	// DefaultBodyDumpLimit is the amount of bytes of a body that we dump when the user doesn't specify the limit.
+	DefaultBodyDumpLimit = 1024
+)
+
+var (
+	// ... headers whose values never reach the logs. Keys must be canonical, see http.CanonicalHeaderKey.
+	sensitiveHeaders = map[string]struct{}{
+		"Authorization":       {},
+		"Cookie":              {},
+		"Proxy-Authorization": {},
+		"Set-Cookie":          {},
+	}
+
+	// ... form parameters and zap field keys which contain credentials or session information.
+	sensitiveKeys = map[string]struct{}{
+		"authorization": {},
+		"cookie":        {},
+		"identity":      {},
+		"password":      {},
+		"secret":        {},
+		"set-cookie":    {},
+	}
+)
+
+// BodyDump is the opt-in to log the http bodies sent to and received from space-track. Bodies are only logged
+// in debug level, with credentials masked and truncated to Limit bytes.
+type BodyDump struct {
+	// Enabled if true, bodies are logged in debug level.
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Limit is the maximum amount of bytes of each body that we log. If zero, DefaultBodyDumpLimit is used.
+	Limit int `json:"limit" yaml:"limit" mapstructure:"limit"`
+}
+
+func (bd BodyDump) limit() int {
+	if bd.Limit <= 0 {
+		return DefaultBodyDumpLimit
+	}
+	return bd.Limit
+}
+
+// IsSensitive reports whether the key, of a form, a zap field or a configuration value, holds credentials or session information.
+func IsSensitive(key string) bool {
+	_, ok := sensitiveKeys[strings.ToLower(key)]
+	return ok
+}
+
+// ... redactHeaders returns a copy of the headers with the sensitive values masked.
+func redactHeaders(headers http.Header) http.Header {
+	var output = make(http.Header, len(headers))
+
+	for k, v := range headers {
+		if _, ok := sensitiveHeaders[http.CanonicalHeaderKey(k)]; ok {
+			output[k] = []string{RedactedValue}
+		} else {
+			output[k] = v
+		}
+	}
+
+	return output
+}
+
+// ... redactForm masks the sensitive values of an url encoded form, like the one sent to authenticate.
+// If the body can't be parsed, the whole body is masked, so we never leak something we don't understand.
+func redactForm(body string) string {
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return RedactedValue
+	}
+
+	for k := range values {
+		if IsSensitive(k) {
+			values[k] = []string{RedactedValue}
+		}
+	}
+
+	return values.Encode()
+}
+
+// ... truncateBody cuts the body to limit bytes, appending how many bytes were left out.
+func truncateBody(body string, limit int) string {
+	if limit < 0 || len(body) <= limit {
+		return body
+	}
+
+	return body[:limit] + "... (" + strconv.Itoa(len(body)-limit) + " more bytes)"
+}
+
+// ... redactCore is a zapcore.Core which masks the fields whose key is a sensitive one before writing them.
+type redactCore struct {
+	zapcore.Core
+}
+
+// NewRedactCore wraps the core, masking the fields which hold credentials or session information, see IsSensitive.
+func NewRedactCore(core zapcore.Core) zapcore.Core {
+	return redactCore{core}
+}
+
+func (rc redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return redactCore{rc.Core.With(redactFields(fields))}
+}
+
+// Check delegates on the wrapped core, so each core of a tee keeps its own level, and writes the entry checked by it
+// with the fields masked. The errors of the wrapped cores are returned, so they are reported to the error output of
+// the logger, see checkedRedactCore.
+func (rc redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := rc.Core.Check(entry, nil)
+	if inner == nil {
+		return checked
+	}
+
+	return checked.AddCore(entry, checkedRedactCore{Core: rc, checked: inner})
+}
+
+func (rc redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return rc.Core.Write(entry, redactFields(fields))
+}
+
+// ... checkedRedactCore writes an entry already checked by the wrapped core of a redactCore.
+type checkedRedactCore struct {
+	zapcore.Core
+	checked *zapcore.CheckedEntry
+}
+
+// Write returns the errors of the cores of the checked entry, which only reports them to its ErrorOutput.
+func (c checkedRedactCore) Write(_ zapcore.Entry, fields []zapcore.Field) error {
+	var errs writeErrors
+
+	c.checked.ErrorOutput = &errs
+	c.checked.Write(redactFields(fields)...)
+
+	return errs.err()
+}
+
+// ... writeErrors is the ErrorOutput of the entries checked by the wrapped core of a redactCore, keeping the errors
+// reported as "<time> write error: <error>".
+type writeErrors []string
+
+func (w *writeErrors) Write(b []byte) (int, error) {
+	msg := strings.TrimSpace(string(b))
+	if _, after, ok := strings.Cut(msg, " write error: "); ok {
+		msg = after
+	}
+
+	*w = append(*w, msg)
+
+	return len(b), nil
+}
+
+func (w *writeErrors) Sync() error {
+	return nil
+}
+
+func (w writeErrors) err() error {
+	if len(w) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(w, "; "))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	var output []zapcore.Field
+
+	for i := range fields {
+		if !IsSensitive(fields[i].Key) {
+			continue
+		}
+
+		if output == nil {
+			output = make([]zapcore.Field, len(fields))
+			copy(output, fields)
+		}
+
+		output[i] = zap.String(fields[i].Key, RedactedValue)
+	}
+
+	if output == nil {
+		return fields
+	}
+
+	return output
+}
@@ -0,0 +1,167 @@
+package spacetrack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// ... observeLogs returns a logger which keeps every log entry, so we can inspect them.
+func observeLogs() (*zap.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(core), logs
+}
+
+func assertNoSecrets(t *testing.T, logs *observer.ObservedLogs, secrets ...string) {
+	assert.NotZero(t, logs.Len())
+
+	for _, entry := range logs.AllUntimed() {
+		line := entry.Message + fmt.Sprint(entry.ContextMap())
+		for _, secret := range secrets {
+			assert.NotContains(t, line, secret)
+		}
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{
+		"Cookie":       []string{testCookie},
+		"Set-Cookie":   []string{testCookie},
+		"Content-Type": []string{"application/json"},
+	}
+
+	got := redactHeaders(headers)
+
+	assert.Equal(t, []string{RedactedValue}, got["Cookie"])
+	assert.Equal(t, []string{RedactedValue}, got["Set-Cookie"])
+	assert.Equal(t, []string{"application/json"}, got["Content-Type"])
+	assert.Equal(t, []string{testCookie}, headers["Cookie"], "original headers must not be modified")
+}
+
+func TestRedactForm(t *testing.T) {
+	for _, each := range []struct {
+		description, input, want string
+	}{
+		{
+			description: "credentials are masked",
+			input:       "identity=" + testIdentity + "&password=" + testPassword,
+			want:        "identity=%5BREDACTED%5D&password=%5BREDACTED%5D",
+		},
+		{
+			description: "non sensitive values are kept",
+			input:       "identity=" + testIdentity + "&query=gp",
+			want:        "identity=%5BREDACTED%5D&query=gp",
+		},
+		{
+			description: "body which is not a form is masked entirely",
+			input:       "%zz",
+			want:        RedactedValue,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.Equal(t, each.want, redactForm(each.input))
+		})
+	}
+}
+
+func TestTruncateBody(t *testing.T) {
+	assert.Equal(t, "hello", truncateBody("hello", 10))
+	assert.Equal(t, "hel... (2 more bytes)", truncateBody("hello", 3))
+	assert.Equal(t, "hello", truncateBody("hello", -1))
+}
+
+func TestRedactCore(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	zap.New(NewRedactCore(core)).With(zap.String("cookie", testCookie)).Info("some message", zap.String("password", testPassword), zap.String("rest_call", "tle"))
+
+	entries := logs.AllUntimed()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, RedactedValue, entries[0].ContextMap()["cookie"])
+		assert.Equal(t, RedactedValue, entries[0].ContextMap()["password"])
+		assert.Equal(t, "tle", entries[0].ContextMap()["rest_call"])
+	}
+}
+
+func TestRedactCoreOfTee(t *testing.T) {
+	var (
+		debugCore, debugLogs = observer.New(zapcore.DebugLevel)
+		infoCore, infoLogs   = observer.New(zapcore.InfoLevel)
+		l                    = zap.New(NewRedactCore(zapcore.NewTee(debugCore, infoCore)))
+	)
+
+	l.Debug("debug message", zap.String("password", testPassword))
+	l.Info("info message", zap.String("password", testPassword))
+
+	assert.Equal(t, 2, debugLogs.Len())
+	if assert.Equal(t, 1, infoLogs.Len(), "each core keeps its own level") {
+		assert.Equal(t, "info message", infoLogs.AllUntimed()[0].Message)
+	}
+	assertNoSecrets(t, debugLogs, testPassword)
+	assertNoSecrets(t, infoLogs, testPassword)
+}
+
+func TestRedactCoreWriteErrors(t *testing.T) {
+	var (
+		errOutput bytes.Buffer
+		failing   = zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(failingWriter{}), zapcore.InfoLevel)
+		l         = zap.New(NewRedactCore(failing), zap.ErrorOutput(zapcore.AddSync(&errOutput)))
+	)
+
+	l.Info("some message", zap.String("password", testPassword))
+
+	assert.Contains(t, errOutput.String(), "write error: disk full", "reported to the error output of the logger")
+	assert.Equal(t, 1, strings.Count(errOutput.String(), "write error"))
+}
+
+// ... failingWriter fails every write, like a full disk.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestRequestsDoNotLogSecrets(t *testing.T) {
+	t.Run("auth request with body dump enabled", func(t *testing.T) {
+		l, logs := observeLogs()
+		c, _ := newTestClient(http.StatusOK, `""`, WithLogger(l), WithBodyDump(BodyDump{Enabled: true}))
+
+		cookie, err := c.authRequest(context.Background(), testCredentials)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, testCookie, cookie)
+		assertNoSecrets(t, logs, testIdentity, testPassword, "t0k3nv4lu3")
+	})
+
+	t.Run("auth request failing", func(t *testing.T) {
+		l, logs := observeLogs()
+		c, _ := newTestClient(http.StatusUnauthorized, `{"Login":"Failed"}`, WithLogger(l), WithBodyDump(BodyDump{Enabled: true}))
+
+		_, err := c.authRequest(context.Background(), testCredentials)
+
+		assert.ErrorIs(t, err, ErrUnexpectedStatus)
+		assertNoSecrets(t, logs, testIdentity, testPassword, "t0k3nv4lu3")
+	})
+
+	t.Run("request with body dump enabled", func(t *testing.T) {
+		l, logs := observeLogs()
+		c, _ := newTestClient(http.StatusOK, `[]`, WithLogger(l), WithBodyDump(BodyDump{Enabled: true}))
+
+		if _, err := c.Query(context.Background(), Tle.Query("")); err != nil {
+			t.Fatal(err)
+		}
+
+		assertNoSecrets(t, logs, testIdentity, testPassword, "t0k3nv4lu3")
+	})
+}
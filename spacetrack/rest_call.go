@@ -0,0 +1,151 @@
+package spacetrack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrParsingRestCall is returned when the input is not the name of a rest call.
+var ErrParsingRestCall = errors.New("parsing input to rest call")
+
+const (
+	Tle   RestCall = "tle"
+	Cdm   RestCall = "cdm"
+	Decay RestCall = "dec"
+	All   RestCall = "all"
+)
+
+// RestCall is a shortcut to one of the classes of space-track, with its default predicates. All stands for every one of them.
+type RestCall string
+
+// RestCallValues are the names of the rest calls allowed.
+var RestCallValues []string = []string{Tle.String(), Cdm.String(), Decay.String(), All.String()}
+
+func (rc RestCall) String() string {
+	var result = "all"
+
+	switch rc {
+	case Tle:
+		result = "tle"
+	case Cdm:
+		result = "cdm"
+	case Decay:
+		result = "dec"
+	case All:
+		result = "all"
+	}
+
+	return result
+}
+
+// Class is the space-track class queried by the rest call. All has no class on its own.
+func (rc RestCall) Class() string {
+	var result = ""
+
+	switch rc {
+	case Tle:
+		result = ClassGP
+	case Cdm:
+		result = ClassCdmPublic
+	case Decay:
+		result = ClassDecay
+	}
+
+	return result
+}
+
+// DefaultQuery is the query executed by the rest call if the job doesn't specify one.
+func (rc RestCall) DefaultQuery() string {
+	var result = ""
+
+	switch rc {
+	case Tle:
+		result = tleQuery
+	case Cdm:
+		result = cdmQuery
+	case Decay:
+		result = decayQuery
+	}
+
+	return result
+}
+
+// CursorField is the field of the class of the rest call which grows with each new record: GP_ID, CDM_ID or MSG_EPOCH.
+// Its newest value, see LatestCursor, is the high-water mark to fetch only the records newer than the last run.
+func (rc RestCall) CursorField() string {
+	var result = ""
+
+	switch rc {
+	case Tle:
+		result = "GP_ID"
+	case Cdm:
+		result = "CDM_ID"
+	case Decay:
+		result = "MSG_EPOCH"
+	}
+
+	return result
+}
+
+// IncrementalQuery is the default query of the rest call fetching only the records whose cursor, see CursorField,
+// is greater than the one passed. Unlike DefaultQuery, it has no time window, so no record is missed between runs.
+func (rc RestCall) IncrementalQuery(after string) string {
+	var result = ""
+
+	switch rc {
+	case Tle:
+		result = fmt.Sprintf(tleIncrementalQuery, after)
+	case Cdm:
+		result = fmt.Sprintf(cdmIncrementalQuery, after)
+	case Decay:
+		result = fmt.Sprintf(decayIncrementalQuery, after)
+	}
+
+	return result
+}
+
+// Query returns the query of the class of the rest call filtered by the predicates passed, being the default ones if empty.
+func (rc RestCall) Query(predicates string) Query {
+	if predicates == "" {
+		predicates = rc.DefaultQuery()
+	}
+	return NewQuery(rc.Class()).Predicates(predicates)
+}
+
+func (rc RestCall) Type() string {
+	return "string"
+}
+
+func (rc *RestCall) Set(input string) error {
+	if !rc.unmarshalText(input) && !rc.unmarshalText(strings.ToLower(input)) {
+		return ErrParsingRestCall
+	}
+	return nil
+}
+
+func (rc *RestCall) unmarshalText(input string) bool {
+	switch input {
+	case "tle", "TLE":
+		*rc = Tle
+	case "cdm", "CDM":
+		*rc = Cdm
+	case "dec", "DEC":
+		*rc = Decay
+	case "all", "ALL":
+		*rc = All
+	default:
+		return false
+	}
+
+	return true
+}
+
+// ParseRestCall returns the rest call whose name is the input, e.g. tle
+func ParseRestCall(input string) (RestCall, error) {
+	var rc RestCall
+	if !rc.unmarshalText(input) {
+		return rc, ErrParsingRestCall
+	}
+	return rc, nil
+}
@@ -1,4 +1,4 @@
-package main
+package spacetrack
 
 import (
 	"testing"
@@ -48,7 +48,7 @@ func TestRestCall(t *testing.T) {
 
 	t.Run("parse restCall returns the right restCall when exist", func(t *testing.T) {
 		for _, want := range restCallTestCases {
-			got, err := parseRestCall(want.str)
+			got, err := ParseRestCall(want.str)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -58,7 +58,7 @@ func TestRestCall(t *testing.T) {
 	})
 
 	t.Run("parse restCall returns error when not exist", func(t *testing.T) {
-		_, got := parseRestCall("notexistent")
+		_, got := ParseRestCall("notexistent")
 		want := ErrParsingRestCall
 
 		assert.ErrorIs(t, got, want)
@@ -69,4 +69,12 @@ func TestRestCall(t *testing.T) {
 			assert.Equal(t, "string", f.restCall.Type())
 		}
 	})
+
+	t.Run("incremental query fetches the records after the cursor", func(t *testing.T) {
+		assert.Equal(t, "DECAY_DATE/null-val/GP_ID/>235813705/orderby/GP_ID asc", Tle.IncrementalQuery("235813705"))
+		assert.Equal(t, "CDM_ID/>436178920/orderby/CDM_ID asc", Cdm.IncrementalQuery("436178920"))
+		assert.Equal(t, "MSG_EPOCH/>2022-07-30 17:22:00/orderby/MSG_EPOCH asc", Decay.IncrementalQuery("2022-07-30 17:22:00"))
+		assert.Empty(t, All.IncrementalQuery("1"))
+		assert.Empty(t, All.CursorField())
+	})
 }
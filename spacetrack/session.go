@@ -0,0 +1,158 @@
+package spacetrack
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultRateLimit is the amount of requests per minute allowed by space-track, see https://www.space-track.org/documentation#/api
+	DefaultRateLimit = 30
+	// NoRateLimit disables the rate limiter, e.g. when the responses are replayed, see NewReplayer. Never use it
+	// against space-track.
+	NoRateLimit = -1
+)
+
+// Credentials are the identity, aka username, and password of the space-track account.
+type Credentials struct {
+	Identity string
+	Password string
+}
+
+// ... encode returns the url encoded form sent to authenticate.
+func (c Credentials) encode() string {
+	return url.Values{"identity": {c.Identity}, "password": {c.Password}}.Encode()
+}
+
+// ... session keeps the cookie of space-track, authenticating only when there is no cookie yet,
+// so concurrent requests don't log in several times.
+type session struct {
+	mu          sync.Mutex
+	credentials Credentials
+	cookie      string
+	// ... authenticated mirrors whether there is a cookie, so it can be checked without waiting for a login in
+	// progress, which holds mu.
+	authenticated atomic.Bool
+}
+
+// ... setCookie replaces the cookie. It must be called holding mu.
+func (s *session) setCookie(cookie string) {
+	s.cookie = cookie
+	s.authenticated.Store(cookie != "")
+}
+
+// ... get returns the cookie of the session, authenticating through login if there is none.
+func (s *session) get(ctx context.Context, login func(context.Context, Credentials) (string, error)) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cookie != "" {
+		return s.cookie, nil
+	}
+
+	cookie, err := login(ctx, s.credentials)
+	if err != nil {
+		return "", err
+	}
+	s.setCookie(cookie)
+
+	return s.cookie, nil
+}
+
+// ... setCredentials replaces the credentials, dropping the cookie only if they have changed.
+func (s *session) setCredentials(c Credentials) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.credentials != c {
+		s.credentials = c
+		s.setCookie("")
+	}
+}
+
+// ... check returns an error if there is no authenticated session, e.g. it has expired or the credentials are wrong.
+// It doesn't wait for a login in progress.
+func (s *session) check() error {
+	if !s.authenticated.Load() {
+		return ErrNotAuthenticated
+	}
+	return nil
+}
+
+// ... invalidate drops the cookie, returning it, so the next call to get authenticates again, e.g. when the session
+// has expired.
+func (s *session) invalidate() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cookie := s.cookie
+	s.setCookie("")
+
+	return cookie
+}
+
+// ... rateLimiter spaces out the requests, allowing at most perMinute requests each minute.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(perMinute int) *rateLimiter {
+	rl := &rateLimiter{}
+	rl.setRate(perMinute)
+	return rl
+}
+
+// ... setRate updates the amount of requests allowed each minute, e.g. when the configuration is reloaded.
+func (rl *rateLimiter) setRate(perMinute int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	switch {
+	case perMinute == NoRateLimit:
+		rl.interval = 0
+	case perMinute <= 0:
+		rl.interval = time.Minute / DefaultRateLimit
+	default:
+		rl.interval = time.Minute / time.Duration(perMinute)
+	}
+}
+
+// ... reserve books the next slot, returning how long the request must wait for it.
+func (rl *rateLimiter) reserve() time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	var (
+		now   = time.Now()
+		delay = rl.next.Sub(now)
+	)
+
+	if delay < 0 {
+		delay = 0
+	}
+	rl.next = now.Add(delay + rl.interval)
+
+	return delay
+}
+
+// ... sleep blocks for the delay or until the context is done.
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package spacetrack
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession(t *testing.T) {
+	t.Run("concurrent logins authenticate only once", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		c, logins := newTestClient(http.StatusOK, `""`)
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				assert.Nil(t, c.Login(context.Background()))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(logins))
+		assert.Equal(t, testCookie, c.session.cookie)
+	})
+
+	t.Run("invalidate forces a new authentication", func(t *testing.T) {
+		c, logins := newTestClient(http.StatusOK, `""`)
+
+		_ = c.Login(context.Background())
+		c.session.invalidate()
+		_ = c.Login(context.Background())
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(logins))
+	})
+
+	t.Run("session is kept when the credentials don't change", func(t *testing.T) {
+		c, _ := newTestClient(http.StatusOK, `""`)
+		c.session.setCookie(testCookie)
+
+		c.SetCredentials(testCredentials)
+
+		assert.Nil(t, c.CheckSession())
+	})
+
+	t.Run("check doesn't wait for a login in progress", func(t *testing.T) {
+		var (
+			s       session
+			started = make(chan struct{})
+			release = make(chan struct{})
+		)
+
+		go s.get(context.Background(), func(context.Context, Credentials) (string, error) { //nolint:errcheck
+			close(started)
+			<-release
+			return testCookie, nil
+		})
+		<-started
+
+		done := make(chan error, 1)
+		go func() { done <- s.check() }()
+
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, ErrNotAuthenticated)
+		case <-time.After(time.Second):
+			t.Error("check is blocked by the login")
+		}
+
+		close(release)
+		assert.Eventually(t, func() bool { return s.check() == nil }, time.Second, time.Millisecond)
+	})
+
+	t.Run("session is dropped when the credentials change", func(t *testing.T) {
+		c, _ := newTestClient(http.StatusOK, `""`)
+		c.session.setCookie(testCookie)
+
+		c.SetCredentials(Credentials{Identity: "another", Password: testPassword})
+
+		assert.ErrorIs(t, c.CheckSession(), ErrNotAuthenticated)
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("requests are spaced out by the rate", func(t *testing.T) {
+		rl := newRateLimiter(int(time.Minute / (20 * time.Millisecond)))
+
+		assert.Zero(t, rl.reserve())
+		assert.InDelta(t, 20*time.Millisecond, rl.reserve(), float64(5*time.Millisecond))
+		assert.InDelta(t, 40*time.Millisecond, rl.reserve(), float64(5*time.Millisecond))
+	})
+
+	t.Run("sleep returns when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.ErrorIs(t, sleep(ctx, time.Minute), context.Canceled)
+	})
+
+	t.Run("client waits for the rate limiter", func(t *testing.T) {
+		c, _ := newTestClient(http.StatusOK, `""`, WithRateLimit(1))
+		ctx, cancel := context.WithCancel(context.Background())
+
+		assert.Nil(t, c.wait(ctx))
+		cancel()
+		assert.ErrorIs(t, c.wait(ctx), context.Canceled)
+	})
+
+	t.Run("non positive rates fall back to the default one", func(t *testing.T) {
+		rl := newRateLimiter(0)
+
+		assert.Equal(t, time.Minute/DefaultRateLimit, rl.interval)
+	})
+
+	t.Run("no rate limit never waits", func(t *testing.T) {
+		rl := newRateLimiter(NoRateLimit)
+
+		for i := 0; i < 3; i++ {
+			assert.Zero(t, rl.reserve())
+		}
+	})
+}
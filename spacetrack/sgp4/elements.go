@@ -0,0 +1,157 @@
+// Package sgp4 propagates the element sets fetched from space-track with the SGP4/SDP4 theory, as revised by Vallado
+// et al. in "Revisiting Spacetrack Report #3" (AIAA 2006-6753), with the WGS72 gravity model the element sets are
+// generated with. Positions and velocities are in the TEME frame, see TEMEToECEF to get them in an earth fixed one.
+package sgp4
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+)
+
+var (
+	// ErrInvalidTLE is returned when the lines of a two line element set can't be parsed.
+	ErrInvalidTLE = errors.New("invalid two line element set")
+	// ErrInvalidElements is returned when the mean elements of an element set are missing or aren't numbers.
+	ErrInvalidElements = errors.New("invalid mean elements")
+)
+
+// ... ommEpochLayout is the layout of the EPOCH of the OMM element sets, in UTC.
+const ommEpochLayout = "2006-01-02T15:04:05.999999"
+
+// Elements are the mean elements of an element set, in the units of the TLE and OMM formats.
+type Elements struct {
+	NoradCatID string
+	Epoch      time.Time
+	// MeanMotion in revolutions per day.
+	MeanMotion float64
+	// Eccentricity without units.
+	Eccentricity float64
+	// Inclination in degrees.
+	Inclination float64
+	// RaOfAscNode, the right ascension of the ascending node, in degrees.
+	RaOfAscNode float64
+	// ArgOfPericenter in degrees.
+	ArgOfPericenter float64
+	// MeanAnomaly in degrees.
+	MeanAnomaly float64
+	// Bstar, the drag term, in inverse earth radii.
+	Bstar float64
+	// MeanMotionDot, half the first derivative of the mean motion, in revolutions per day squared.
+	MeanMotionDot float64
+	// MeanMotionDdot, a sixth of the second derivative of the mean motion, in revolutions per day cubed.
+	MeanMotionDdot float64
+}
+
+// FromUnit returns the mean elements of the element set, parsed from TLE_LINE1 and TLE_LINE2, or from the OMM mean
+// elements, like MEAN_MOTION, if the lines are empty.
+func FromUnit(u spacetrack.SpaceTrackTleUnit) (Elements, error) {
+	if u.TleLine1 != "" && u.TleLine2 != "" {
+		return ParseTLE(u.TleLine1, u.TleLine2)
+	}
+
+	var (
+		e   = Elements{NoradCatID: u.NoradCatId}
+		err error
+	)
+
+	if e.Epoch, err = time.Parse(ommEpochLayout, u.Epoch); err != nil {
+		return e, fmt.Errorf("%w: EPOCH %q", ErrInvalidElements, u.Epoch)
+	}
+
+	for _, f := range []struct {
+		name  string
+		value string
+		dst   *float64
+	}{
+		{"MEAN_MOTION", u.MeanMotion, &e.MeanMotion},
+		{"ECCENTRICITY", u.Eccentricity, &e.Eccentricity},
+		{"INCLINATION", u.Inclination, &e.Inclination},
+		{"RA_OF_ASC_NODE", u.RaOfAscNode, &e.RaOfAscNode},
+		{"ARG_OF_PERICENTER", u.ArgOfPericenter, &e.ArgOfPericenter},
+		{"MEAN_ANOMALY", u.MeanAnomaly, &e.MeanAnomaly},
+		{"BSTAR", u.Bstar, &e.Bstar},
+		{"MEAN_MOTION_DOT", u.MeanMotionDot, &e.MeanMotionDot},
+		{"MEAN_MOTION_DDOT", u.MeanMotionDdot, &e.MeanMotionDdot},
+	} {
+		if *f.dst, err = strconv.ParseFloat(f.value, 64); err != nil {
+			return e, fmt.Errorf("%w: %s %q", ErrInvalidElements, f.name, f.value)
+		}
+	}
+
+	return e, nil
+}
+
+// ParseTLE returns the mean elements of a two line element set. The checksums of the lines are not verified.
+func ParseTLE(line1, line2 string) (Elements, error) {
+	var e Elements
+
+	line1, line2 = strings.TrimRight(line1, " \r\n"), strings.TrimRight(line2, " \r\n")
+
+	if len(line1) < 64 || line1[0] != '1' {
+		return e, fmt.Errorf("%w: line 1 %q", ErrInvalidTLE, line1)
+	}
+	if len(line2) < 63 || line2[0] != '2' {
+		return e, fmt.Errorf("%w: line 2 %q", ErrInvalidTLE, line2)
+	}
+
+	e.NoradCatID = strings.TrimLeft(strings.TrimSpace(line1[2:7]), "0")
+
+	p := tleParser{}
+
+	year := int(p.float(line1[18:20], "epoch year"))
+	if year < 57 {
+		year += 2000
+	} else {
+		year += 1900
+	}
+
+	doy := p.float(line1[20:32], "epoch day")
+	e.Epoch = time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(math.Round((doy-1)*86400e6)) * time.Microsecond)
+
+	e.MeanMotionDot = p.float(line1[33:43], "first derivative of the mean motion")
+	e.MeanMotionDdot = p.exponent(line1[44:52], "second derivative of the mean motion")
+	e.Bstar = p.exponent(line1[53:61], "bstar")
+
+	e.Inclination = p.float(line2[8:16], "inclination")
+	e.RaOfAscNode = p.float(line2[17:25], "right ascension of the ascending node")
+	e.Eccentricity = p.float("0."+strings.TrimSpace(line2[26:33]), "eccentricity")
+	e.ArgOfPericenter = p.float(line2[34:42], "argument of pericenter")
+	e.MeanAnomaly = p.float(line2[43:51], "mean anomaly")
+	e.MeanMotion = p.float(line2[52:63], "mean motion")
+
+	return e, p.err
+}
+
+// ... tleParser parses the fields of the lines of a two line element set, keeping the first error.
+type tleParser struct {
+	err error
+}
+
+func (p *tleParser) float(field, name string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("%w: %s %q", ErrInvalidTLE, name, field)
+	}
+	return f
+}
+
+// ... exponent parses the fields with an implied decimal point and exponent, e.g. -11606-4 is -0.11606e-4
+func (p *tleParser) exponent(field, name string) float64 {
+	field = strings.TrimSpace(field)
+	if len(field) < 3 {
+		return p.float(field, name)
+	}
+
+	sign := ""
+	if field[0] == '-' || field[0] == '+' {
+		sign, field = field[:1], field[1:]
+	}
+
+	return p.float(sign+"0."+field[:len(field)-2]+"e"+field[len(field)-2:], name)
+}
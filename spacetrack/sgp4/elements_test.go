@@ -0,0 +1,127 @@
+package sgp4
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+var vanguard = spacetrack.SpaceTrackTleUnit{
+	NoradCatId:      "5",
+	Epoch:           "2000-06-27T18:50:19.733568",
+	MeanMotion:      "10.82419157",
+	Eccentricity:    "0.18596670",
+	Inclination:     "34.2682",
+	RaOfAscNode:     "348.7242",
+	ArgOfPericenter: "331.7664",
+	MeanAnomaly:     "19.3264",
+	Bstar:           "0.000028098000",
+	MeanMotionDot:   "0.00000023",
+	MeanMotionDdot:  "0.0000000000000",
+	TleLine1:        "1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753",
+	TleLine2:        "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667",
+}
+
+func TestParseTLE(t *testing.T) {
+	for _, each := range []struct {
+		description  string
+		line1, line2 string
+		want         Elements
+		wantErr      bool
+	}{
+		{
+			description: "vanguard 1",
+			line1:       vanguard.TleLine1,
+			line2:       vanguard.TleLine2,
+			want: Elements{
+				NoradCatID:      "5",
+				Epoch:           time.Date(2000, 6, 27, 18, 50, 19, 733568000, time.UTC),
+				MeanMotion:      10.82419157,
+				Eccentricity:    0.1859667,
+				Inclination:     34.2682,
+				RaOfAscNode:     348.7242,
+				ArgOfPericenter: 331.7664,
+				MeanAnomaly:     19.3264,
+				Bstar:           0.28098e-4,
+				MeanMotionDot:   0.00000023,
+			},
+		},
+		{
+			description: "negative exponents and epoch of the last century",
+			line1:       "1 25544U 98067A   98264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+			line2:       "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+			want: Elements{
+				NoradCatID:      "25544",
+				Epoch:           time.Date(1998, 9, 21, 12, 25, 40, 104192000, time.UTC),
+				MeanMotion:      15.72125391,
+				Eccentricity:    0.0006703,
+				Inclination:     51.6416,
+				RaOfAscNode:     247.4627,
+				ArgOfPericenter: 130.536,
+				MeanAnomaly:     325.0288,
+				Bstar:           -0.11606e-4,
+				MeanMotionDot:   -0.00002182,
+			},
+		},
+		{description: "lines swapped", line1: vanguard.TleLine2, line2: vanguard.TleLine1, wantErr: true},
+		{description: "short line", line1: vanguard.TleLine1[:40], line2: vanguard.TleLine2, wantErr: true},
+		{description: "field which is not a number", line1: vanguard.TleLine1, line2: vanguard.TleLine2[:8] + "34.2a82" + vanguard.TleLine2[15:], wantErr: true},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got, err := ParseTLE(each.line1, each.line2)
+
+			if each.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTLE)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, each.want.NoradCatID, got.NoradCatID)
+			assert.Equal(t, each.want.Epoch, got.Epoch)
+			assert.InDelta(t, each.want.MeanMotion, got.MeanMotion, 1e-12)
+			assert.InDelta(t, each.want.Eccentricity, got.Eccentricity, 1e-12)
+			assert.InDelta(t, each.want.Inclination, got.Inclination, 1e-12)
+			assert.InDelta(t, each.want.RaOfAscNode, got.RaOfAscNode, 1e-12)
+			assert.InDelta(t, each.want.ArgOfPericenter, got.ArgOfPericenter, 1e-12)
+			assert.InDelta(t, each.want.MeanAnomaly, got.MeanAnomaly, 1e-12)
+			assert.InDelta(t, each.want.Bstar, got.Bstar, 1e-16)
+			assert.InDelta(t, each.want.MeanMotionDot, got.MeanMotionDot, 1e-16)
+			assert.InDelta(t, each.want.MeanMotionDdot, got.MeanMotionDdot, 1e-16)
+		})
+	}
+}
+
+func TestFromUnit(t *testing.T) {
+	t.Run("lines and omm mean elements are the same", func(t *testing.T) {
+		fromLines, err := FromUnit(vanguard)
+		assert.Nil(t, err)
+
+		omm := vanguard
+		omm.TleLine1, omm.TleLine2 = "", ""
+
+		fromOMM, err := FromUnit(omm)
+		assert.Nil(t, err)
+
+		assert.Equal(t, fromLines.Epoch, fromOMM.Epoch)
+		assert.InDelta(t, fromLines.Eccentricity, fromOMM.Eccentricity, 1e-7)
+		assert.InDelta(t, fromLines.Bstar, fromOMM.Bstar, 1e-12)
+	})
+
+	t.Run("missing omm mean element", func(t *testing.T) {
+		omm := vanguard
+		omm.TleLine1, omm.TleLine2, omm.Bstar = "", "", ""
+
+		_, err := FromUnit(omm)
+		assert.ErrorIs(t, err, ErrInvalidElements)
+	})
+
+	t.Run("invalid epoch", func(t *testing.T) {
+		omm := vanguard
+		omm.TleLine1, omm.TleLine2, omm.Epoch = "", "", "2000-06-27"
+
+		_, err := FromUnit(omm)
+		assert.ErrorIs(t, err, ErrInvalidElements)
+	})
+}
@@ -0,0 +1,106 @@
+package sgp4
+
+import (
+	"math"
+	"time"
+)
+
+// WGS84 constants, the ones of the geodetic coordinates.
+const (
+	wgs84A  = 6378.137
+	wgs84F  = 1 / 298.257223563
+	wgs84E2 = wgs84F * (2 - wgs84F)
+
+	// EarthRotation is the rotation rate of the earth, in radians per second.
+	EarthRotation = 7.292115146706979e-5
+)
+
+// Geodetic are the coordinates of a point over the WGS84 ellipsoid.
+type Geodetic struct {
+	// Latitude in degrees, north positive.
+	Latitude float64 `json:"latitude"`
+	// Longitude in degrees, east positive, within [-180, 180].
+	Longitude float64 `json:"longitude"`
+	// Altitude over the ellipsoid, in km.
+	Altitude float64 `json:"altitude"`
+}
+
+// ECEF returns the position of the point in the earth fixed frame, in km.
+func (g Geodetic) ECEF() Vector {
+	var (
+		lat, lon = g.Latitude * deg, g.Longitude * deg
+		sinLat   = math.Sin(lat)
+		n        = wgs84A / math.Sqrt(1-wgs84E2*sinLat*sinLat)
+	)
+
+	return Vector{
+		(n + g.Altitude) * math.Cos(lat) * math.Cos(lon),
+		(n + g.Altitude) * math.Cos(lat) * math.Sin(lon),
+		(n*(1-wgs84E2) + g.Altitude) * sinLat,
+	}
+}
+
+// ECEFToGeodetic returns the geodetic coordinates of a position in the earth fixed frame, in km.
+func ECEFToGeodetic(r Vector) Geodetic {
+	var (
+		p   = math.Hypot(r[0], r[1])
+		lat = math.Atan2(r[2], p*(1-wgs84E2))
+		alt float64
+	)
+
+	for i := 0; i < 10; i++ {
+		var (
+			sinLat = math.Sin(lat)
+			n      = wgs84A / math.Sqrt(1-wgs84E2*sinLat*sinLat)
+			prev   = lat
+		)
+
+		alt = p*math.Cos(lat) + r[2]*sinLat - wgs84A*wgs84A/n
+		lat = math.Atan2(r[2], p*(1-wgs84E2*n/(n+alt)))
+
+		if math.Abs(lat-prev) < 1e-12 {
+			break
+		}
+	}
+
+	return Geodetic{Latitude: lat / deg, Longitude: math.Atan2(r[1], r[0]) / deg, Altitude: alt}
+}
+
+// GMST returns the greenwich mean sidereal time, in radians, of the time passed, with the IAU 1982 model the TEME frame
+// is defined with. The time should be in UT1, although UTC is within a second of it.
+func GMST(t time.Time) float64 {
+	return gstime(julianDate(t))
+}
+
+// TEMEToECEF rotates a position, in km, and a velocity, in km/s, from the TEME frame to the earth fixed one at the time
+// passed. Polar motion is neglected, which is an error of some meters.
+func TEMEToECEF(r, v Vector, t time.Time) (Vector, Vector) {
+	var (
+		gmst       = GMST(t)
+		sinG, cosG = math.Sin(gmst), math.Cos(gmst)
+		rf         = Vector{cosG*r[0] + sinG*r[1], -sinG*r[0] + cosG*r[1], r[2]}
+		vf         = Vector{cosG*v[0] + sinG*v[1], -sinG*v[0] + cosG*v[1], v[2]}
+	)
+
+	// ... the velocity relative to the rotating earth.
+	vf[0] += EarthRotation * rf[1]
+	vf[1] -= EarthRotation * rf[0]
+
+	return rf, vf
+}
+
+// ECEF returns the position, in km, and velocity, in km/s, of the state in the earth fixed frame, see TEMEToECEF.
+func (s State) ECEF() (Vector, Vector) {
+	return TEMEToECEF(s.Position, s.Velocity, s.Time)
+}
+
+// Geodetic returns the geodetic coordinates of the position of the state, see ECEFToGeodetic.
+func (s State) Geodetic() Geodetic {
+	r, _ := s.ECEF()
+	return ECEFToGeodetic(r)
+}
+
+// ... julianDate returns the julian date of the time.
+func julianDate(t time.Time) float64 {
+	return float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5
+}
@@ -0,0 +1,72 @@
+package sgp4
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTEMEToECEF(t *testing.T) {
+	// ... example 3-15 of Vallado, Fundamentals of Astrodynamics and Applications, at 2004-04-06 07:51:28.386009 UTC,
+	// with UT1-UTC of -0.4399619 seconds. The earth fixed vectors include polar motion, of some meters.
+	var (
+		ut1 = time.Date(2004, 4, 6, 7, 51, 28, 386009000, time.UTC).Add(-439961900 * time.Nanosecond)
+		r   = Vector{5094.18016210, 6127.64465950, 6380.34453270}
+		v   = Vector{-4.746131487, 0.785818041, 5.531931288}
+	)
+
+	gotR, gotV := TEMEToECEF(r, v, ut1)
+
+	for i, want := range (Vector{-1033.4793830, 7901.2952754, 6380.3565958}) {
+		assert.InDelta(t, want, gotR[i], 0.02)
+	}
+	for i, want := range (Vector{-3.225636520, -2.872451450, 5.531924446}) {
+		assert.InDelta(t, want, gotV[i], 1e-4)
+	}
+}
+
+func TestECEFToGeodetic(t *testing.T) {
+	t.Run("example 3-3 of vallado", func(t *testing.T) {
+		got := ECEFToGeodetic(Vector{6524.834, 6862.875, 6448.296})
+
+		assert.InDelta(t, 34.352496, got.Latitude, 1e-5)
+		assert.InDelta(t, 46.4464, got.Longitude, 1e-4)
+		assert.InDelta(t, 5085.22, got.Altitude, 1e-2)
+	})
+
+	for _, want := range []Geodetic{
+		{Latitude: 40.4168, Longitude: -3.7038, Altitude: 0.667},
+		{Latitude: -33.8688, Longitude: 151.2093, Altitude: 408},
+		{Latitude: 89.99, Longitude: 0, Altitude: 35786},
+		{Latitude: 0, Longitude: -180, Altitude: 0},
+	} {
+		t.Run("round trip", func(t *testing.T) {
+			got := ECEFToGeodetic(want.ECEF())
+
+			assert.InDelta(t, want.Latitude, got.Latitude, 1e-9)
+			assert.InDelta(t, want.Longitude, got.Longitude, 1e-9)
+			assert.InDelta(t, want.Altitude, got.Altitude, 1e-6)
+		})
+	}
+}
+
+func TestStateGeodetic(t *testing.T) {
+	e, err := FromUnit(vanguard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := s.Propagate(e.Epoch)
+	assert.Nil(t, err)
+
+	g := st.Geodetic()
+
+	assert.InDelta(t, st.Position.Norm()-6378.137, g.Altitude, 25, "the altitude is about the radius minus the one of the earth")
+	assert.LessOrEqual(t, g.Latitude, 34.2682, "the latitude is not greater than the inclination")
+}
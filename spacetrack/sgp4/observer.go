@@ -0,0 +1,90 @@
+package sgp4
+
+import (
+	"math"
+	"time"
+)
+
+// AstronomicalUnit in km.
+const AstronomicalUnit = 149597870.7
+
+// Look is the direction and distance of a satellite as seen by an observer on the earth.
+type Look struct {
+	// Azimuth in degrees, clockwise from the north, within [0, 360).
+	Azimuth float64 `json:"azimuth"`
+	// Elevation in degrees over the horizon of the ellipsoid, negative below it.
+	Elevation float64 `json:"elevation"`
+	// Range in km.
+	Range float64 `json:"range"`
+	// RangeRate in km/s, positive when the satellite moves away.
+	RangeRate float64 `json:"range_rate"`
+}
+
+// Look returns the look angles of a position, in km, and velocity, in km/s, in the earth fixed frame, as seen from
+// the point.
+func (g Geodetic) Look(r, v Vector) Look {
+	var (
+		o              = g.ECEF()
+		rho            = Vector{r[0] - o[0], r[1] - o[1], r[2] - o[2]}
+		lat, lon       = g.Latitude * deg, g.Longitude * deg
+		sinLat, cosLat = math.Sin(lat), math.Cos(lat)
+		sinLon, cosLon = math.Sin(lon), math.Cos(lon)
+		east           = -sinLon*rho[0] + cosLon*rho[1]
+		north          = -sinLat*cosLon*rho[0] - sinLat*sinLon*rho[1] + cosLat*rho[2]
+		up             = cosLat*cosLon*rho[0] + cosLat*sinLon*rho[1] + sinLat*rho[2]
+		rng            = rho.Norm()
+		az             = math.Mod(math.Atan2(east, north)/deg+360, 360)
+		dot            = rho[0]*v[0] + rho[1]*v[1] + rho[2]*v[2]
+	)
+
+	return Look{Azimuth: az, Elevation: math.Asin(up/rng) / deg, Range: rng, RangeRate: dot / rng}
+}
+
+// Observe returns the look angles of the state as seen from the point.
+func (g Geodetic) Observe(s State) Look {
+	r, v := s.ECEF()
+	return g.Look(r, v)
+}
+
+// SunPosition returns the position of the sun, in km, in the mean equator and equinox of date frame at the time
+// passed, with the low precision algorithm of the astronomical almanac, accurate to 0.01 degrees. The difference with
+// the TEME frame is negligible for telling whether a satellite is sunlit.
+func SunPosition(t time.Time) Vector {
+	var (
+		tut1     = (julianDate(t) - 2451545) / 36525
+		meanLong = math.Mod(280.460+36000.771*tut1, 360)
+		anomaly  = math.Mod(357.5291092+35999.05034*tut1, 360) * deg
+		eclLong  = (meanLong + 1.914666471*math.Sin(anomaly) + 0.019994643*math.Sin(2*anomaly)) * deg
+		oblq     = (23.439291 - 0.0130042*tut1) * deg
+		magr     = (1.000140612 - 0.016708617*math.Cos(anomaly) - 0.000139589*math.Cos(2*anomaly)) * AstronomicalUnit
+	)
+
+	return Vector{
+		magr * math.Cos(eclLong),
+		magr * math.Cos(oblq) * math.Sin(eclLong),
+		magr * math.Sin(oblq) * math.Sin(eclLong),
+	}
+}
+
+// Sunlit reports whether the satellite is illuminated by the sun, or it is within the shadow of the earth, which is
+// modelled as a cylinder.
+func (s State) Sunlit() bool {
+	var (
+		sun  = SunPosition(s.Time)
+		norm = sun.Norm()
+		// ... projection of the position over the direction of the sun.
+		proj = (s.Position[0]*sun[0] + s.Position[1]*sun[1] + s.Position[2]*sun[2]) / norm
+	)
+
+	if proj >= 0 {
+		return true
+	}
+
+	perp := Vector{
+		s.Position[0] - proj*sun[0]/norm,
+		s.Position[1] - proj*sun[1]/norm,
+		s.Position[2] - proj*sun[2]/norm,
+	}
+
+	return perp.Norm() > wgs84A
+}
@@ -0,0 +1,110 @@
+package sgp4
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeodeticLook(t *testing.T) {
+	observer := Geodetic{Latitude: 40.4168, Longitude: -3.7038, Altitude: 0.667}
+
+	for _, each := range []struct {
+		description   string
+		point         Geodetic
+		wantAzimuth   float64
+		wantElevation float64
+	}{
+		{
+			description:   "zenith",
+			point:         Geodetic{Latitude: observer.Latitude, Longitude: observer.Longitude, Altitude: 500},
+			wantElevation: 90,
+		},
+		{
+			description:   "north",
+			point:         Geodetic{Latitude: observer.Latitude + 1, Longitude: observer.Longitude, Altitude: observer.Altitude},
+			wantAzimuth:   0,
+			wantElevation: -0.5,
+		},
+		{
+			description:   "east",
+			point:         Geodetic{Latitude: observer.Latitude, Longitude: observer.Longitude + 1, Altitude: observer.Altitude},
+			wantAzimuth:   89.7,
+			wantElevation: -0.4,
+		},
+		{
+			description:   "west",
+			point:         Geodetic{Latitude: observer.Latitude, Longitude: observer.Longitude - 1, Altitude: observer.Altitude},
+			wantAzimuth:   270.3,
+			wantElevation: -0.4,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got := observer.Look(each.point.ECEF(), Vector{})
+
+			if each.wantElevation != 90 {
+				assert.InDelta(t, each.wantAzimuth, got.Azimuth, 0.1)
+			}
+			assert.InDelta(t, each.wantElevation, got.Elevation, 0.1)
+			assert.GreaterOrEqual(t, got.Azimuth, 0.0)
+			assert.Less(t, got.Azimuth, 360.0)
+		})
+	}
+
+	t.Run("range and range rate", func(t *testing.T) {
+		var (
+			o = observer.ECEF()
+			r = Geodetic{Latitude: observer.Latitude, Longitude: observer.Longitude, Altitude: 500.667}.ECEF()
+			u = Vector{(r[0] - o[0]) / 500, (r[1] - o[1]) / 500, (r[2] - o[2]) / 500}
+		)
+
+		got := observer.Look(r, Vector{2 * u[0], 2 * u[1], 2 * u[2]})
+
+		assert.InDelta(t, 500, got.Range, 1e-6)
+		assert.InDelta(t, 2, got.RangeRate, 1e-6)
+	})
+}
+
+func TestSunPosition(t *testing.T) {
+	// ... example 5-1 of Vallado, Fundamentals of Astrodynamics and Applications.
+	got := SunPosition(time.Date(2006, 4, 2, 0, 0, 0, 0, time.UTC))
+
+	for i, want := range (Vector{0.9771945, 0.1924424, 0.0834308}) {
+		assert.InDelta(t, want, got[i]/AstronomicalUnit, 1e-4)
+	}
+}
+
+func TestStateSunlit(t *testing.T) {
+	var (
+		at  = time.Date(2006, 4, 2, 0, 0, 0, 0, time.UTC)
+		sun = SunPosition(at)
+		dir = Vector{sun[0] / sun.Norm(), sun[1] / sun.Norm(), sun[2] / sun.Norm()}
+	)
+
+	for _, each := range []struct {
+		description string
+		position    Vector
+		want        bool
+	}{
+		{
+			description: "day side",
+			position:    Vector{7000 * dir[0], 7000 * dir[1], 7000 * dir[2]},
+			want:        true,
+		},
+		{
+			description: "night side",
+			position:    Vector{-7000 * dir[0], -7000 * dir[1], -7000 * dir[2]},
+			want:        false,
+		},
+		{
+			description: "night side over the pole",
+			position:    Vector{-1000 * dir[0], -1000 * dir[1], 7000},
+			want:        true,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.Equal(t, each.want, State{Time: at, Position: each.position}.Sunlit())
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package sgp4
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrPassWindow is returned when the stop of the window of the passes is not after its start.
+var ErrPassWindow = errors.New("stop of the window of the passes not after its start")
+
+// ... precision of the times of acquisition and loss of signal and of the maximum elevation of the passes.
+const passPrecision = 100 * time.Millisecond
+
+// PassPoint is the look angles of a satellite at a time of a pass, and whether it is sunlit then, see State.Sunlit.
+type PassPoint struct {
+	Time time.Time `json:"time"`
+	Look
+	Sunlit bool `json:"sunlit"`
+}
+
+// Pass is an interval of time when a satellite is over the minimum elevation of an observer, from the acquisition of
+// signal, AOS, to the loss of signal, LOS. Passes in progress at the start or the stop of the window are cut by them.
+type Pass struct {
+	AOS PassPoint `json:"aos"`
+	// Max is the point of maximum elevation.
+	Max PassPoint `json:"max"`
+	LOS PassPoint `json:"los"`
+}
+
+// Duration returns the time between the acquisition and the loss of signal.
+func (p Pass) Duration() time.Duration {
+	return p.LOS.Time.Sub(p.AOS.Time)
+}
+
+// Passes returns the passes of the satellite over the observer, with the minimum elevation in degrees, within the
+// window of time passed. If the propagation fails, e.g. the satellite decays, the passes until then are returned along
+// with the error.
+func (s *Satellite) Passes(observer Geodetic, minElevation float64, start, stop time.Time) ([]Pass, error) {
+	if !stop.After(start) {
+		return nil, ErrPassWindow
+	}
+
+	var (
+		passes []Pass
+		step   = s.passStep()
+		above  = func(t time.Time) (bool, error) {
+			st, err := s.Propagate(t)
+			return observer.Observe(st).Elevation >= minElevation, err
+		}
+	)
+
+	prevTime := start
+	prevAbove, err := above(start)
+	if err != nil {
+		return passes, err
+	}
+
+	aos := start
+
+	for t := start.Add(step); ; t = t.Add(step) {
+		if t.After(stop) {
+			t = stop
+		}
+
+		cur, err := above(t)
+		if err != nil {
+			return passes, err
+		}
+
+		switch {
+		case cur && !prevAbove:
+			if _, aos, err = crossing(above, prevTime, t, prevAbove); err != nil {
+				return passes, err
+			}
+		case !cur && prevAbove:
+			// ... the loss of signal is the last time above the minimum elevation.
+			los, _, err := crossing(above, prevTime, t, prevAbove)
+			if err != nil {
+				return passes, err
+			}
+			p, err := s.pass(observer, aos, los)
+			if err != nil {
+				return passes, err
+			}
+			passes = append(passes, p)
+		}
+
+		prevTime, prevAbove = t, cur
+
+		if !t.Before(stop) {
+			break
+		}
+	}
+
+	if prevAbove {
+		p, err := s.pass(observer, aos, stop)
+		if err != nil {
+			return passes, err
+		}
+		passes = append(passes, p)
+	}
+
+	return passes, nil
+}
+
+// ... passStep returns the step to look for passes, short enough to not miss the ones of the low orbits, which last
+// some minutes.
+func (s *Satellite) passStep() time.Duration {
+	step := time.Duration(twoPi / s.no / 200 * float64(time.Minute))
+
+	if step > time.Minute {
+		return time.Minute
+	}
+	if step < time.Second {
+		return time.Second
+	}
+
+	return step
+}
+
+// ... crossing searches by bisection when the satellite crosses the minimum elevation between from, when it is above
+// it or not as passed, and to, when it is not, returning the last time before crossing and the first one after it.
+func crossing(above func(time.Time) (bool, error), from, to time.Time, fromAbove bool) (time.Time, time.Time, error) {
+	for to.Sub(from) > passPrecision {
+		mid := from.Add(to.Sub(from) / 2)
+
+		cur, err := above(mid)
+		if err != nil {
+			return from, to, err
+		}
+
+		if cur == fromAbove {
+			from = mid
+		} else {
+			to = mid
+		}
+	}
+
+	return from, to, nil
+}
+
+// ... pass returns the points of the pass, searching its maximum elevation by golden section.
+func (s *Satellite) pass(observer Geodetic, aos, los time.Time) (Pass, error) {
+	var (
+		p   Pass
+		err error
+		phi = (math.Sqrt(5) - 1) / 2
+	)
+
+	if p.AOS, err = s.passPoint(observer, aos); err != nil {
+		return p, err
+	}
+	if p.LOS, err = s.passPoint(observer, los); err != nil {
+		return p, err
+	}
+
+	var (
+		a, b   = aos, los
+		elevAt = func(t time.Time) (float64, error) {
+			st, err := s.Propagate(t)
+			return observer.Observe(st).Elevation, err
+		}
+	)
+
+	for b.Sub(a) > passPrecision {
+		var (
+			c = b.Add(-time.Duration(float64(b.Sub(a)) * phi))
+			d = a.Add(time.Duration(float64(b.Sub(a)) * phi))
+		)
+
+		elevC, err := elevAt(c)
+		if err != nil {
+			return p, err
+		}
+
+		elevD, err := elevAt(d)
+		if err != nil {
+			return p, err
+		}
+
+		if elevC > elevD {
+			b = d
+		} else {
+			a = c
+		}
+	}
+
+	p.Max, err = s.passPoint(observer, a.Add(b.Sub(a)/2))
+
+	// ... passes cut by the window may have their maximum at one of their ends.
+	for _, end := range []PassPoint{p.AOS, p.LOS} {
+		if end.Elevation > p.Max.Elevation {
+			p.Max = end
+		}
+	}
+
+	return p, err
+}
+
+func (s *Satellite) passPoint(observer Geodetic, t time.Time) (PassPoint, error) {
+	st, err := s.Propagate(t)
+	return PassPoint{Time: t, Look: observer.Observe(st), Sunlit: st.Sunlit()}, err
+}
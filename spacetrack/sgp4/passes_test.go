@@ -0,0 +1,93 @@
+package sgp4
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSatellitePasses(t *testing.T) {
+	e, err := ParseTLE(
+		"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+		"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		observer = Geodetic{Latitude: 40.4168, Longitude: -3.7038, Altitude: 0.667}
+		start    = e.Epoch
+		stop     = e.Epoch.Add(24 * time.Hour)
+	)
+
+	passes, err := s.Passes(observer, 10, start, stop)
+	assert.Nil(t, err)
+
+	t.Run("passes of the window", func(t *testing.T) {
+		// ... the passes are the intervals above the minimum elevation found sampling every 10 seconds.
+		var (
+			want  int
+			above bool
+		)
+
+		for at := start; !at.After(stop); at = at.Add(10 * time.Second) {
+			st, err := s.Propagate(at)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cur := observer.Observe(st).Elevation >= 10
+			if cur && !above {
+				want++
+			}
+			above = cur
+		}
+
+		assert.NotZero(t, want)
+		assert.Len(t, passes, want)
+	})
+
+	t.Run("points of each pass", func(t *testing.T) {
+		for i, p := range passes {
+			assert.True(t, p.AOS.Time.Before(p.Max.Time), "pass %d", i)
+			assert.True(t, p.Max.Time.Before(p.LOS.Time), "pass %d", i)
+			assert.InDelta(t, 10, p.AOS.Elevation, 0.1, "pass %d", i)
+			assert.InDelta(t, 10, p.LOS.Elevation, 0.1, "pass %d", i)
+			assert.GreaterOrEqual(t, p.Max.Elevation, 10.0, "pass %d", i)
+			assert.Less(t, p.Duration(), 15*time.Minute, "pass %d", i)
+
+			if i > 0 {
+				assert.True(t, passes[i-1].LOS.Time.Before(p.AOS.Time), "pass %d", i)
+			}
+		}
+	})
+
+	t.Run("pass cut by the window", func(t *testing.T) {
+		if len(passes) == 0 {
+			t.Skip("no passes")
+		}
+
+		p := passes[0]
+
+		got, err := s.Passes(observer, 10, p.Max.Time, p.LOS.Time.Add(time.Minute))
+
+		assert.Nil(t, err)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, p.Max.Time, got[0].AOS.Time)
+			assert.Equal(t, got[0].AOS, got[0].Max)
+			assert.WithinDuration(t, p.LOS.Time, got[0].LOS.Time, time.Second)
+		}
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		_, err := s.Passes(observer, 10, stop, start)
+		assert.ErrorIs(t, err, ErrPassWindow)
+	})
+}
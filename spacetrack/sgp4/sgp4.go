@@ -0,0 +1,497 @@
+package sgp4
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// WGS72 constants, the ones the element sets are generated with.
+const (
+	// EarthRadius is the equatorial radius of the earth, in km.
+	EarthRadius = 6378.135
+
+	mu    = 398600.8
+	j2    = 0.001082616
+	j3    = -0.00000253881
+	j4    = -0.00000165597
+	j3oj2 = j3 / j2
+
+	twoPi = 2 * math.Pi
+	x2o3  = 2.0 / 3.0
+	deg   = math.Pi / 180
+	// ... xpdotp is the amount of minutes in a day divided by 2 pi, to convert revolutions per day to radians per minute.
+	xpdotp = 1440 / twoPi
+)
+
+var (
+	// ... xke is the square root of mu in earth radii^1.5 per minute.
+	xke = 60 / math.Sqrt(EarthRadius*EarthRadius*EarthRadius/mu)
+	// ... vkmpersec converts earth radii per minute to km per second.
+	vkmpersec = EarthRadius * xke / 60
+)
+
+var (
+	// ErrEccentricity is returned when the eccentricity, perturbed at the time propagated, is not within [0, 1).
+	ErrEccentricity = errors.New("eccentricity out of range")
+	// ErrMeanMotion is returned when the mean motion, perturbed at the time propagated, is not positive.
+	ErrMeanMotion = errors.New("mean motion not positive")
+	// ErrSemiLatusRectum is returned when the semi-latus rectum at the time propagated is negative.
+	ErrSemiLatusRectum = errors.New("semi-latus rectum negative")
+	// ErrDecayed is returned when the orbit propagated is below the surface of the earth.
+	ErrDecayed = errors.New("satellite has decayed")
+	// ErrStep is returned when the step of an ephemeris is not positive.
+	ErrStep = errors.New("step of the ephemeris not positive")
+)
+
+// Vector is a cartesian vector, e.g. a position in km or a velocity in km/s.
+type Vector [3]float64
+
+// Norm returns the length of the vector.
+func (v Vector) Norm() float64 {
+	return math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+}
+
+// State is the position, in km, and velocity, in km/s, of a satellite at a time in the TEME frame.
+type State struct {
+	Time     time.Time
+	Position Vector
+	Velocity Vector
+}
+
+// Satellite propagates the mean elements of an element set. It is initialized once by New and is not modified when
+// propagated, so it is safe for concurrent use.
+type Satellite struct {
+	Elements
+
+	// ... mean elements at epoch, in radians and radians per minute, and epoch in days since 1949-12-31 00:00 UTC.
+	epoch                             float64
+	ecco, inclo, nodeo, argpo, mo, no float64
+	bstar                             float64
+
+	// ... near earth terms.
+	isimp                                      bool
+	aycof, con41, cc1, cc4, cc5, d2, d3, d4    float64
+	delmo, eta, argpdot, omgcof, sinmao, t2cof float64
+	t3cof, t4cof, t5cof, x1mth2, x7thm1, mdot  float64
+	nodedot, xlcof, xmcof, nodecf              float64
+
+	// ... deep space terms, see deepSpace.
+	deep *deepSpace
+}
+
+// New initializes the propagation of the mean elements, returning an error if they can't be propagated, e.g. their
+// eccentricity is not within [0, 1).
+func New(e Elements) (*Satellite, error) {
+	s := &Satellite{
+		Elements: e,
+		epoch:    float64(e.Epoch.Sub(time.Date(1949, 12, 31, 0, 0, 0, 0, time.UTC))) / float64(24*time.Hour),
+		ecco:     e.Eccentricity,
+		inclo:    e.Inclination * deg,
+		nodeo:    e.RaOfAscNode * deg,
+		argpo:    e.ArgOfPericenter * deg,
+		mo:       e.MeanAnomaly * deg,
+		no:       e.MeanMotion / xpdotp,
+		bstar:    e.Bstar,
+	}
+
+	if s.no <= 0 {
+		return nil, ErrMeanMotion
+	}
+	if s.ecco < 0 || s.ecco >= 1 {
+		return nil, ErrEccentricity
+	}
+
+	s.init()
+
+	if _, err := s.PropagateMinutes(0); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ... init is sgp4init of the revision of Vallado et al., computing the terms which don't depend on the time.
+func (s *Satellite) init() {
+	const temp4 = 1.5e-12
+
+	var (
+		ss         = 78/EarthRadius + 1
+		qzms2ttemp = (120 - 78) / EarthRadius
+		qzms2t     = qzms2ttemp * qzms2ttemp * qzms2ttemp * qzms2ttemp
+	)
+
+	// ... initl, recovering the original mean motion and semi-major axis from the kozai mean motion of the element set.
+	var (
+		eccsq  = s.ecco * s.ecco
+		omeosq = 1 - eccsq
+		rteosq = math.Sqrt(omeosq)
+		cosio  = math.Cos(s.inclo)
+		cosio2 = cosio * cosio
+		ak     = math.Pow(xke/s.no, x2o3)
+		d1     = 0.75 * j2 * (3*cosio2 - 1) / (rteosq * omeosq)
+		del    = d1 / (ak * ak)
+		adel   = ak * (1 - del*del - del*(1.0/3.0+134*del*del/81))
+	)
+
+	del = d1 / (adel * adel)
+	s.no = s.no / (1 + del)
+
+	var (
+		ao    = math.Pow(xke/s.no, x2o3)
+		sinio = math.Sin(s.inclo)
+		po    = ao * omeosq
+		con42 = 1 - 5*cosio2
+		posq  = po * po
+		rp    = ao * (1 - s.ecco)
+		gsto  = gstime(s.epoch + 2433281.5)
+	)
+
+	s.con41 = -con42 - cosio2 - cosio2
+
+	// ... perigees below 220 km use a simplified drag model.
+	s.isimp = rp < 220/EarthRadius+1
+
+	sfour, qzms24 := ss, qzms2t
+	if perige := (rp - 1) * EarthRadius; perige < 156 {
+		sfour = perige - 78
+		if perige < 98 {
+			sfour = 20
+		}
+		qzms24 = math.Pow((120-sfour)/EarthRadius, 4)
+		sfour = sfour/EarthRadius + 1
+	}
+
+	var (
+		pinvsq = 1 / posq
+		tsi    = 1 / (ao - sfour)
+		etasq  float64
+		eeta   float64
+		psisq  float64
+		coef   float64
+		coef1  float64
+		cc2    float64
+		cc3    float64
+	)
+
+	s.eta = ao * s.ecco * tsi
+	etasq = s.eta * s.eta
+	eeta = s.ecco * s.eta
+	psisq = math.Abs(1 - etasq)
+	coef = qzms24 * math.Pow(tsi, 4)
+	coef1 = coef / math.Pow(psisq, 3.5)
+	cc2 = coef1 * s.no * (ao*(1+1.5*etasq+eeta*(4+etasq)) + 0.375*j2*tsi/psisq*s.con41*(8+3*etasq*(8+etasq)))
+	s.cc1 = s.bstar * cc2
+	if s.ecco > 1e-4 {
+		cc3 = -2 * coef * tsi * j3oj2 * s.no * sinio / s.ecco
+	}
+	s.x1mth2 = 1 - cosio2
+	s.cc4 = 2 * s.no * coef1 * ao * omeosq * (s.eta*(2+0.5*etasq) + s.ecco*(0.5+2*etasq) -
+		j2*tsi/(ao*psisq)*(-3*s.con41*(1-2*eeta+etasq*(1.5-0.5*eeta))+0.75*s.x1mth2*(2*etasq-eeta*(1+etasq))*math.Cos(2*s.argpo)))
+	s.cc5 = 2 * coef1 * ao * omeosq * (1 + 2.75*(etasq+eeta) + eeta*etasq)
+
+	var (
+		cosio4 = cosio2 * cosio2
+		temp1  = 1.5 * j2 * pinvsq * s.no
+		temp2  = 0.5 * temp1 * j2 * pinvsq
+		temp3  = -0.46875 * j4 * pinvsq * pinvsq * s.no
+		xhdot1 = -temp1 * cosio
+	)
+
+	s.mdot = s.no + 0.5*temp1*rteosq*s.con41 + 0.0625*temp2*rteosq*(13-78*cosio2+137*cosio4)
+	s.argpdot = -0.5*temp1*con42 + 0.0625*temp2*(7-114*cosio2+395*cosio4) + temp3*(3-36*cosio2+49*cosio4)
+	s.nodedot = xhdot1 + (0.5*temp2*(4-19*cosio2)+2*temp3*(3-7*cosio2))*cosio
+
+	xpidot := s.argpdot + s.nodedot
+
+	s.omgcof = s.bstar * cc3 * math.Cos(s.argpo)
+	if s.ecco > 1e-4 {
+		s.xmcof = -x2o3 * coef * s.bstar / eeta
+	}
+	s.nodecf = 3.5 * omeosq * xhdot1 * s.cc1
+	s.t2cof = 1.5 * s.cc1
+	// ... avoids dividing by zero for an inclination of 180 degrees.
+	if math.Abs(cosio+1) > 1.5e-12 {
+		s.xlcof = -0.25 * j3oj2 * sinio * (3 + 5*cosio) / (1 + cosio)
+	} else {
+		s.xlcof = -0.25 * j3oj2 * sinio * (3 + 5*cosio) / temp4
+	}
+	s.aycof = -0.5 * j3oj2 * sinio
+	s.delmo = math.Pow(1+s.eta*math.Cos(s.mo), 3)
+	s.sinmao = math.Sin(s.mo)
+	s.x7thm1 = 7*cosio2 - 1
+
+	// ... orbits with periods of 225 minutes or more are deep space, perturbed by the sun and the moon.
+	if twoPi/s.no >= 225 {
+		s.isimp = true
+		s.deep = newDeepSpace(s, gsto, eccsq, xpidot)
+	}
+
+	if !s.isimp {
+		cc1sq := s.cc1 * s.cc1
+		s.d2 = 4 * ao * tsi * cc1sq
+		temp := s.d2 * tsi * s.cc1 / 3
+		s.d3 = (17*ao + sfour) * temp
+		s.d4 = 0.5 * temp * ao * tsi * (221*ao + 31*sfour) * s.cc1
+		s.t3cof = s.d2 + 2*cc1sq
+		s.t4cof = 0.25 * (3*s.d3 + s.cc1*(12*s.d2+10*cc1sq))
+		s.t5cof = 0.2 * (3*s.d4 + 12*s.cc1*s.d3 + 6*s.d2*s.d2 + 15*cc1sq*(2*s.d2+cc1sq))
+	}
+}
+
+// Propagate returns the state of the satellite at the time passed.
+func (s *Satellite) Propagate(t time.Time) (State, error) {
+	st, err := s.PropagateMinutes(float64(t.Sub(s.Epoch)) / float64(time.Minute))
+	st.Time = t
+	return st, err
+}
+
+// PropagateMinutes returns the state of the satellite at the minutes since the epoch of its element set passed. If the
+// satellite has decayed, its state is returned too, along with ErrDecayed.
+func (s *Satellite) PropagateMinutes(tsince float64) (State, error) {
+	const temp4 = 1.5e-12
+
+	var (
+		st = State{Time: s.Epoch.Add(time.Duration(tsince * float64(time.Minute)))}
+		t  = tsince
+		t2 = t * t
+
+		// ... secular gravity and atmospheric drag.
+		xmdf   = s.mo + s.mdot*t
+		argpdf = s.argpo + s.argpdot*t
+		nodedf = s.nodeo + s.nodedot*t
+		argpm  = argpdf
+		mm     = xmdf
+		nodem  = nodedf + s.nodecf*t2
+		tempa  = 1 - s.cc1*t
+		tempe  = s.bstar * s.cc4 * t
+		templ  = s.t2cof * t2
+	)
+
+	if !s.isimp {
+		var (
+			delomg   = s.omgcof * t
+			delmtemp = 1 + s.eta*math.Cos(xmdf)
+			delm     = s.xmcof * (delmtemp*delmtemp*delmtemp - s.delmo)
+			temp     = delomg + delm
+			t3       = t2 * t
+			t4       = t3 * t
+		)
+
+		mm = xmdf + temp
+		argpm = argpdf - temp
+		tempa = tempa - s.d2*t2 - s.d3*t3 - s.d4*t4
+		tempe = tempe + s.bstar*s.cc5*(math.Sin(mm)-s.sinmao)
+		templ = templ + s.t3cof*t3 + t4*(s.t4cof+t*s.t5cof)
+	}
+
+	var (
+		nm    = s.no
+		em    = s.ecco
+		inclm = s.inclo
+	)
+
+	if s.deep != nil {
+		em, argpm, inclm, mm, nodem, nm = s.deep.secular(s, t, em, argpm, inclm, mm, nodem)
+	}
+
+	if nm <= 0 {
+		return st, ErrMeanMotion
+	}
+
+	am := math.Pow(xke/nm, x2o3) * tempa * tempa
+	nm = xke / math.Pow(am, 1.5)
+	em = em - tempe
+
+	if em >= 1 || em < -0.001 {
+		return st, ErrEccentricity
+	}
+	if em < 1e-6 {
+		em = 1e-6
+	}
+
+	mm = mm + s.no*templ
+	xlm := mm + argpm + nodem
+
+	nodem = math.Mod(nodem, twoPi)
+	argpm = math.Mod(argpm, twoPi)
+	xlm = math.Mod(xlm, twoPi)
+	mm = math.Mod(xlm-argpm-nodem, twoPi)
+
+	var (
+		ep     = em
+		xincp  = inclm
+		argpp  = argpm
+		nodep  = nodem
+		mp     = mm
+		sinip  = math.Sin(inclm)
+		cosip  = math.Cos(inclm)
+		aycof  = s.aycof
+		xlcof  = s.xlcof
+		con41  = s.con41
+		x1mth2 = s.x1mth2
+		x7thm1 = s.x7thm1
+	)
+
+	if s.deep != nil {
+		// ... lunar and solar periodics.
+		ep, xincp, nodep, argpp, mp = s.deep.periodics(t, ep, xincp, nodep, argpp, mp, false)
+
+		if xincp < 0 {
+			xincp = -xincp
+			nodep = nodep + math.Pi
+			argpp = argpp - math.Pi
+		}
+
+		if ep < 0 || ep > 1 {
+			return st, ErrEccentricity
+		}
+
+		sinip = math.Sin(xincp)
+		cosip = math.Cos(xincp)
+		aycof = -0.5 * j3oj2 * sinip
+		if math.Abs(cosip+1) > 1.5e-12 {
+			xlcof = -0.25 * j3oj2 * sinip * (3 + 5*cosip) / (1 + cosip)
+		} else {
+			xlcof = -0.25 * j3oj2 * sinip * (3 + 5*cosip) / temp4
+		}
+	}
+
+	// ... long period periodics.
+	var (
+		axnl = ep * math.Cos(argpp)
+		temp = 1 / (am * (1 - ep*ep))
+		aynl = ep*math.Sin(argpp) + temp*aycof
+		xl   = mp + argpp + nodep + temp*xlcof*axnl
+	)
+
+	// ... kepler's equation.
+	var (
+		u              = math.Mod(xl-nodep, twoPi)
+		eo1            = u
+		tem5           = 9999.9
+		sineo1, coseo1 float64
+	)
+
+	for ktr := 1; math.Abs(tem5) >= 1e-12 && ktr <= 10; ktr++ {
+		sineo1 = math.Sin(eo1)
+		coseo1 = math.Cos(eo1)
+		tem5 = 1 - coseo1*axnl - sineo1*aynl
+		tem5 = (u - aynl*coseo1 + axnl*sineo1 - eo1) / tem5
+		if math.Abs(tem5) >= 0.95 {
+			tem5 = math.Copysign(0.95, tem5)
+		}
+		eo1 = eo1 + tem5
+	}
+
+	// ... short period periodics.
+	var (
+		ecose = axnl*coseo1 + aynl*sineo1
+		esine = axnl*sineo1 - aynl*coseo1
+		el2   = axnl*axnl + aynl*aynl
+		pl    = am * (1 - el2)
+	)
+
+	if pl < 0 {
+		return st, ErrSemiLatusRectum
+	}
+
+	var (
+		rl     = am * (1 - ecose)
+		rdotl  = math.Sqrt(am) * esine / rl
+		rvdotl = math.Sqrt(pl) / rl
+		betal  = math.Sqrt(1 - el2)
+	)
+
+	temp = esine / (1 + betal)
+
+	var (
+		sinu  = am / rl * (sineo1 - aynl - axnl*temp)
+		cosu  = am / rl * (coseo1 - axnl + aynl*temp)
+		su    = math.Atan2(sinu, cosu)
+		sin2u = (cosu + cosu) * sinu
+		cos2u = 1 - 2*sinu*sinu
+	)
+
+	temp = 1 / pl
+
+	var (
+		temp1 = 0.5 * j2 * temp
+		temp2 = temp1 * temp
+	)
+
+	if s.deep != nil {
+		cosisq := cosip * cosip
+		con41 = 3*cosisq - 1
+		x1mth2 = 1 - cosisq
+		x7thm1 = 7*cosisq - 1
+	}
+
+	var (
+		mrt   = rl*(1-1.5*temp2*betal*con41) + 0.5*temp1*x1mth2*cos2u
+		xnode = nodep + 1.5*temp2*cosip*sin2u
+		xinc  = xincp + 1.5*temp2*cosip*sinip*cos2u
+		mvt   = rdotl - nm*temp1*x1mth2*sin2u/xke
+		rvdot = rvdotl + nm*temp1*(x1mth2*cos2u+1.5*con41)/xke
+	)
+
+	su = su - 0.25*temp2*x7thm1*sin2u
+
+	// ... orientation vectors.
+	var (
+		sinsu, cossu = math.Sin(su), math.Cos(su)
+		snod, cnod   = math.Sin(xnode), math.Cos(xnode)
+		sini, cosi   = math.Sin(xinc), math.Cos(xinc)
+		xmx          = -snod * cosi
+		xmy          = cnod * cosi
+		ux           = Vector{xmx*sinsu + cnod*cossu, xmy*sinsu + snod*cossu, sini * sinsu}
+		vx           = Vector{xmx*cossu - cnod*sinsu, xmy*cossu - snod*sinsu, sini * cossu}
+	)
+
+	for i := range ux {
+		st.Position[i] = mrt * ux[i] * EarthRadius
+		st.Velocity[i] = (mvt*ux[i] + rvdot*vx[i]) * vkmpersec
+	}
+
+	if mrt < 1 {
+		return st, ErrDecayed
+	}
+
+	return st, nil
+}
+
+// ... gstime returns the greenwich mean sidereal time, in radians, of the julian date in UT1, see GMST.
+func gstime(jdut1 float64) float64 {
+	tut1 := (jdut1 - 2451545) / 36525
+	temp := -6.2e-6*tut1*tut1*tut1 + 0.093104*tut1*tut1 + (876600*3600+8640184.812866)*tut1 + 67310.54841
+	temp = math.Mod(temp*deg/240, twoPi)
+
+	if temp < 0 {
+		temp += twoPi
+	}
+
+	return temp
+}
+
+// Ephemeris returns the states of the satellite from start to stop, both included, every step. If the propagation
+// fails, e.g. the satellite decays, the states until then are returned along with the error.
+func (s *Satellite) Ephemeris(start, stop time.Time, step time.Duration) ([]State, error) {
+	if step <= 0 {
+		return nil, ErrStep
+	}
+
+	var states []State
+	if stop.After(start) {
+		states = make([]State, 0, stop.Sub(start)/step+1)
+	}
+
+	for t := start; !t.After(stop); t = t.Add(step) {
+		st, err := s.Propagate(t)
+		if err != nil {
+			return states, err
+		}
+		states = append(states, st)
+	}
+
+	return states, nil
+}
@@ -0,0 +1,233 @@
+package sgp4
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ... vectors of the verification of Vallado et al. (SGP4-VER.TLE and tcppver.out), positions in km and velocities in
+// km/s in the TEME frame at the minutes since epoch.
+var verificationVectors = []struct {
+	description  string
+	line1, line2 string
+	states       []struct {
+		tsince float64
+		r, v   Vector
+	}
+}{
+	{
+		description: "vanguard 1, near earth",
+		line1:       "1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753",
+		line2:       "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667",
+		states: []struct {
+			tsince float64
+			r, v   Vector
+		}{
+			{0, Vector{7022.46529266, -1400.08296755, 0.03995155}, Vector{1.893841015, 6.405893759, 4.534807250}},
+			{360, Vector{-7154.03120202, -3783.17682504, -3536.19412294}, Vector{4.741887409, -4.151817765, -2.093935425}},
+			{720, Vector{-7134.59340119, 6531.68641334, 3260.27186483}, Vector{-4.113793027, -2.911922039, -2.557327851}},
+			{1080, Vector{5568.53901181, 4492.06992591, 3863.87641983}, Vector{-4.209106476, 5.159719888, 2.744852980}},
+			{4320, Vector{-9060.47373569, 4658.70952502, 813.68673153}, Vector{-2.232832783, -4.110453490, -3.157345433}},
+		},
+	},
+	{
+		description: "delta 1 debris, near earth with high drag",
+		line1:       "1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985",
+		line2:       "2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774",
+		states: []struct {
+			tsince float64
+			r, v   Vector
+		}{
+			{0, Vector{3988.31022699, 5498.96657235, 0.90055879}, Vector{-3.290032738, 2.357652820, 6.496623475}},
+		},
+	},
+	{
+		description: "sun synchronous, near earth almost circular",
+		line1:       "1 28057U 03049A   06177.78615833  .00000060  00000-0  35940-4 0  1836",
+		line2:       "2 28057  98.4283 247.6961 0000884  88.1964 271.9322 14.35478080140550",
+		states: []struct {
+			tsince float64
+			r, v   Vector
+		}{
+			{0, Vector{-2715.28237486, -6619.26436889, -0.01341443}, Vector{-1.008587273, 0.422782003, 7.385272942}},
+		},
+	},
+	{
+		description: "molniya, deep space with 12 hours resonance",
+		line1:       "1 08195U 75081A   06176.33215444  .00000099  00000-0  11873-3 0   813",
+		line2:       "2 08195  64.1586 279.0717 6877146 264.7651  20.2257  2.00491383225656",
+		states: []struct {
+			tsince float64
+			r, v   Vector
+		}{
+			{0, Vector{2349.89483350, -14785.93811562, 0.02119378}, Vector{2.721488096, -3.256811655, 4.498416672}},
+			{120, Vector{15223.91713658, -17852.95881713, 25280.39558224}, Vector{1.079041732, 0.875187372, 2.485682813}},
+			{720, Vector{2622.13222207, -15125.15464924, 474.51048398}, Vector{2.688287199, -3.078426664, 4.494979530}},
+		},
+	},
+	{
+		description: "deep space without resonance",
+		line1:       "1 11801U          80230.29629788  .01431103  00000-0  14311-1 0    13",
+		line2:       "2 11801  46.7916 230.4354 7318036  47.4722  10.4117  2.28537848    13",
+		states: []struct {
+			tsince float64
+			r, v   Vector
+		}{
+			{0, Vector{7473.37102491, 428.94748312, 5828.74846783}, Vector{5.107155391, 6.444680305, -0.186133297}},
+			{720, Vector{14271.29083858, 24110.44309009, -4725.76320143}, Vector{-0.320504528, 2.679841539, -2.084054355}},
+			{1440, Vector{9787.87836256, 33753.32249667, -15030.79874625}, Vector{-1.094251553, 0.923589906, -1.522311008}},
+		},
+	},
+	{
+		description: "deep space, low inclination and high eccentricity",
+		line1:       "1 23599U 95029B   06171.76535463  .00085586  12891-6  12956-2 0  2905",
+		line2:       "2 23599   6.9327   0.2849 5782022 274.4436  25.2425  4.47796565123555",
+		states: []struct {
+			tsince float64
+			r, v   Vector
+		}{
+			{0, Vector{9892.63794341, 35.76144969, -1.08228838}, Vector{3.556643237, 6.456009375, 0.783610890}},
+			{120, Vector{816.64091546, 24118.98675475, 2932.69459428}, Vector{-2.626838010, 0.504502763, 0.062344306}},
+			{720, Vector{7140.41945884, 20539.25485336, 2501.21469368}, Vector{-2.293173684, 2.333507912, 0.282716311}},
+		},
+	},
+	{
+		description: "gps, deep space almost circular",
+		line1:       "1 28129U 03058A   06175.57071136 -.00000104  00000-0  10000-3 0   459",
+		line2:       "2 28129  54.7298 324.8098 0048506 266.2640  93.1856  2.00562768 18445",
+		states: []struct {
+			tsince float64
+			r, v   Vector
+		}{
+			{0, Vector{21710.47473603, -15314.42024767, 7.43774182}, Vector{1.302963491, 1.817656883, 3.161919790}},
+			{120, Vector{18611.96396249, 3172.70714679, 18837.07741931}, Vector{-2.077025187, 2.838303765, 1.585296527}},
+			{2880, Vector{22272.04709761, -14417.78129851, 1554.44474495}, Vector{1.076714440, 1.970612065, 3.153885181}},
+		},
+	},
+}
+
+func TestPropagateMinutes(t *testing.T) {
+	for _, each := range verificationVectors {
+		t.Run(each.description, func(t *testing.T) {
+			e, err := ParseTLE(each.line1, each.line2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := New(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, want := range each.states {
+				got, err := s.PropagateMinutes(want.tsince)
+
+				assert.Nil(t, err)
+				for i := range want.r {
+					assert.InDelta(t, want.r[i], got.Position[i], 1e-6, "position %d at %v minutes", i, want.tsince)
+					assert.InDelta(t, want.v[i], got.Velocity[i], 1e-9, "velocity %d at %v minutes", i, want.tsince)
+				}
+			}
+		})
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	e, err := ParseTLE(verificationVectors[0].line1, verificationVectors[0].line2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := e.Epoch.Add(360 * time.Minute)
+	got, err := s.Propagate(at)
+
+	assert.Nil(t, err)
+	assert.Equal(t, at, got.Time)
+	assert.InDelta(t, -7154.03120202, got.Position[0], 1e-6)
+
+	t.Run("deep space integration is the same forwards and backwards", func(t *testing.T) {
+		e, err := ParseTLE(verificationVectors[3].line1, verificationVectors[3].line2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, err := New(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		first, _ := s.PropagateMinutes(2880)  //nolint:errcheck
+		_, _ = s.PropagateMinutes(-1440)      //nolint:errcheck
+		second, _ := s.PropagateMinutes(2880) //nolint:errcheck
+
+		assert.Equal(t, first, second)
+	})
+}
+
+func TestNew(t *testing.T) {
+	valid := Elements{Epoch: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), MeanMotion: 15.5, Eccentricity: 0.001, Inclination: 51.6}
+
+	for _, each := range []struct {
+		description string
+		change      func(*Elements)
+		wantErr     error
+	}{
+		{description: "valid elements", change: func(*Elements) {}},
+		{description: "hyperbolic orbit", change: func(e *Elements) { e.Eccentricity = 1.2 }, wantErr: ErrEccentricity},
+		{description: "negative eccentricity", change: func(e *Elements) { e.Eccentricity = -0.1 }, wantErr: ErrEccentricity},
+		{description: "no mean motion", change: func(e *Elements) { e.MeanMotion = 0 }, wantErr: ErrMeanMotion},
+		{description: "orbit below the surface", change: func(e *Elements) { e.MeanMotion = 18 }, wantErr: ErrDecayed},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			e := valid
+			each.change(&e)
+
+			_, err := New(e)
+
+			if each.wantErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, each.wantErr)
+			}
+		})
+	}
+}
+
+func TestEphemeris(t *testing.T) {
+	e, err := ParseTLE(verificationVectors[0].line1, verificationVectors[0].line2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("start and stop are included", func(t *testing.T) {
+		states, err := s.Ephemeris(e.Epoch, e.Epoch.Add(720*time.Minute), 360*time.Minute)
+
+		assert.Nil(t, err)
+		assert.Len(t, states, 3)
+		assert.InDelta(t, -7134.59340119, states[2].Position[0], 1e-6)
+	})
+
+	t.Run("stop before start", func(t *testing.T) {
+		states, err := s.Ephemeris(e.Epoch, e.Epoch.Add(-time.Hour), time.Minute)
+
+		assert.Nil(t, err)
+		assert.Empty(t, states)
+	})
+
+	t.Run("step not positive", func(t *testing.T) {
+		_, err := s.Ephemeris(e.Epoch, e.Epoch.Add(time.Hour), 0)
+		assert.ErrorIs(t, err, ErrStep)
+	})
+}
@@ -0,0 +1,346 @@
+// Package spacetracktest provides a fake space-track server, built on httptest, to test the clients of space-track
+// offline. It implements the authentication, /ajaxauth/login and /ajaxauth/logout, and the query controller,
+// /basicspacedata/query, of the classes supported by go-spacetrack, answering with fixture files. Latency, failures
+// like 429 and 5xx, and expired sessions can be injected while the server is running.
+package spacetracktest
+
+import (
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Identity is the identity accepted by the server by default, see WithCredentials.
+	Identity = "someone@example.com"
+	// Password is the password accepted by the server by default, see WithCredentials.
+	Password = "sup3r-s3cr3t-passw0rd"
+
+	// ClassGP is the class of the general perturbations.
+	ClassGP = "gp"
+	// ClassGPHistory is the class of every element set published of each object.
+	ClassGPHistory = "gp_history"
+	// ClassCdmPublic is the class of the public conjunction data messages.
+	ClassCdmPublic = "cdm_public"
+	// ClassDecay is the class of the decays.
+	ClassDecay = "decay"
+
+	// ... name of the cookie of the session, the same as space-track.
+	cookieName = "chocolatechip"
+
+	loginPath  = "/ajaxauth/login"
+	logoutPath = "/ajaxauth/logout"
+	queryPath  = "/basicspacedata/query/"
+)
+
+// ... fixtures are the records returned by default for each class, in fixtures/${class}.json
+//
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Classes are the classes answered by the server.
+var Classes = []string{ClassGP, ClassGPHistory, ClassCdmPublic, ClassDecay}
+
+// Server is a fake space-track listening on a local address, see httptest.Server. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	identity, password string
+
+	mu       sync.Mutex
+	fixtures map[string][]byte
+	latency  time.Duration
+	// ... failures are the status codes of the next requests, consumed in order.
+	failures []int
+	sessions map[string]bool
+	logins   int
+	logouts  int
+	queries  int
+}
+
+// Option configures a Server, see NewServer.
+type Option func(*Server)
+
+// WithCredentials sets the identity and password accepted by the server. By default, Identity and Password.
+func WithCredentials(identity, password string) Option {
+	return func(s *Server) {
+		s.identity, s.password = identity, password
+	}
+}
+
+// WithFixture sets the json body returned by the queries of the class.
+func WithFixture(class string, body []byte) Option {
+	return func(s *Server) {
+		s.fixtures[class] = body
+	}
+}
+
+// WithFixtureDir reads the fixtures of the classes from the files ${dir}/${class}.json, e.g. ${dir}/gp.json. Classes
+// without a file keep the default fixture. It panics if some of the files can't be read, like httptest does when the
+// server can't listen.
+func WithFixtureDir(dir string) Option {
+	return func(s *Server) {
+		for _, class := range Classes {
+			body, err := os.ReadFile(filepath.Join(dir, class+".json"))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				panic(fmt.Sprintf("spacetracktest: reading fixture of %s: %v", class, err))
+			}
+			s.fixtures[class] = body
+		}
+	}
+}
+
+// WithLatency delays every response, see SetLatency.
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// NewServer starts and returns a fake space-track. The caller must call Close when finished, to shut it down.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		identity: Identity,
+		password: Password,
+		fixtures: make(map[string][]byte, len(Classes)),
+		sessions: make(map[string]bool),
+	}
+
+	for _, class := range Classes {
+		s.fixtures[class] = Fixture(class)
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(loginPath, s.login)
+	mux.HandleFunc(logoutPath, s.logout)
+	mux.HandleFunc(queryPath, s.query)
+
+	s.Server = httptest.NewServer(s.delay(s.fail(mux)))
+
+	return s
+}
+
+// Fixture returns the default json body of the queries of the class, being nil if the class is unknown.
+func Fixture(class string) []byte {
+	body, err := fs.ReadFile(fixtures, "fixtures/"+class+".json")
+	if err != nil {
+		return nil
+	}
+	return body
+}
+
+// SetLatency delays every response from now on. Requests whose context is done stop waiting.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// FailNext answers the next n requests, of any endpoint, with the status code passed, e.g. 429 or 500. Calls are
+// queued, so FailNext(1, 429) followed by FailNext(1, 500) answers 429 and then 500.
+func (s *Server) FailNext(n, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, statusCode)
+	}
+}
+
+// ExpireSessions drops every session, so the next queries are answered with 401 until the client logs in again.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions = make(map[string]bool)
+}
+
+// Logins returns the amount of successful logins.
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.logins
+}
+
+// Logouts returns the amount of logouts.
+func (s *Server) Logouts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.logouts
+}
+
+// Queries returns the amount of queries answered with the fixtures.
+func (s *Server) Queries() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.queries
+}
+
+// ... delay waits the latency before handling the request.
+func (s *Server) delay(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		latency := s.latency
+		s.mu.Unlock()
+
+		if latency > 0 {
+			t := time.NewTimer(latency)
+			defer t.Stop()
+
+			select {
+			case <-t.C:
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ... fail answers the request with the next injected failure, if any.
+func (s *Server) fail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		var statusCode int
+		if len(s.failures) > 0 {
+			statusCode, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if statusCode == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if statusCode == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "60")
+		}
+		writeError(w, statusCode, http.StatusText(statusCode))
+	})
+}
+
+// ... login creates a session if the credentials of the form are right. Like space-track, wrong credentials are
+// answered with 200 and a failed login, without cookie.
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "login must be a POST")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if r.PostForm.Get("identity") != s.identity || r.PostForm.Get("password") != s.password {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Login":"Failed"}`)
+		return
+	}
+
+	session := newSessionID()
+
+	s.mu.Lock()
+	s.sessions[session] = true
+	s.logins++
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: cookieName, Value: session, Path: "/", HttpOnly: true})
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, `""`)
+}
+
+// ... logout drops the session of the cookie, if any.
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	if c, err := r.Cookie(cookieName); err == nil {
+		delete(s.sessions, c.Value)
+	}
+	s.logouts++
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, `"Successfully logged out"`)
+}
+
+// ... query answers the fixture of the class of the path, e.g. /basicspacedata/query/class/gp/NORAD_CAT_ID/25544.
+// Predicates are ignored, so every query of a class returns the whole fixture.
+func (s *Server) query(w http.ResponseWriter, r *http.Request) {
+	if !s.authenticated(r) {
+		writeError(w, http.StatusUnauthorized, "You must be logged in to complete this action")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, queryPath), "/")
+	if len(parts) < 2 || parts[0] != "class" {
+		writeError(w, http.StatusBadRequest, "class is required, e.g. /basicspacedata/query/class/gp")
+		return
+	}
+
+	s.mu.Lock()
+	body, ok := s.fixtures[parts[1]]
+	if ok {
+		s.queries++
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown class "+parts[1])
+		return
+	}
+
+	if len(body) == 0 {
+		body = []byte("[]")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body) //nolint:errcheck
+}
+
+func (s *Server) authenticated(r *http.Request) bool {
+	c, err := r.Cookie(cookieName)
+	if err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sessions[c.Value]
+}
+
+func writeError(w http.ResponseWriter, statusCode int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	fmt.Fprintf(w, `{"error":%q}`, msg)
+}
+
+// ... newSessionID returns a random value for the cookie of a session.
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("spacetracktest: generating session: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,151 @@
+package spacetracktest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-spacetrack/spacetrack"
+	"github.com/stretchr/testify/assert"
+)
+
+// ... newClient returns a client of the server, authenticating with the default credentials and without rate limit.
+func newClient(s *Server) *spacetrack.Client {
+	return spacetrack.New(spacetrack.Credentials{Identity: Identity, Password: Password},
+		spacetrack.WithBaseURL(s.URL),
+		spacetrack.WithRateLimit(int(time.Minute/time.Microsecond)),
+	)
+}
+
+func TestServerFixtures(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := newClient(s)
+
+	t.Run("gp", func(t *testing.T) {
+		got, err := spacetrack.Fetch[spacetrack.SpaceTrackTleUnit](context.Background(), c, spacetrack.Tle.Query(""))
+
+		if assert.Nil(t, err) && assert.Len(t, got, 2) {
+			assert.Equal(t, "25544", got[0].NoradCatId)
+			assert.Equal(t, "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", got[0].TleLine1)
+		}
+	})
+
+	t.Run("decay", func(t *testing.T) {
+		got, err := spacetrack.Fetch[spacetrack.SpaceTrackDecayUnit](context.Background(), c, spacetrack.Decay.Query(""))
+
+		assert.Nil(t, err)
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("cdm public", func(t *testing.T) {
+		got, err := spacetrack.Fetch[spacetrack.SpaceTrackCdmUnit](context.Background(), c, spacetrack.Cdm.Query("CDM_ID/412345678"))
+
+		if assert.Nil(t, err) && assert.Len(t, got, 1) {
+			assert.Equal(t, "412345678", got[0].CdmID)
+		}
+	})
+
+	t.Run("unknown class", func(t *testing.T) {
+		_, err := c.Query(context.Background(), spacetrack.NewQuery("satcat"))
+
+		var se *spacetrack.StatusError
+		if assert.ErrorAs(t, err, &se) {
+			assert.Equal(t, http.StatusNotFound, se.StatusCode)
+		}
+	})
+
+	assert.Equal(t, 3, s.Queries())
+}
+
+func TestServerOptions(t *testing.T) {
+	t.Run("fixture replaced by the option", func(t *testing.T) {
+		s := NewServer(WithFixture(ClassDecay, []byte(`[]`)))
+		defer s.Close()
+
+		got, err := spacetrack.Fetch[spacetrack.SpaceTrackDecayUnit](context.Background(), newClient(s), spacetrack.Decay.Query(""))
+
+		assert.Nil(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("fixtures read from a dir", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "cdm_public.json"), []byte(`[{"CDM_ID":"1"},{"CDM_ID":"2"}]`), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		s := NewServer(WithFixtureDir(dir))
+		defer s.Close()
+
+		cdms, err := spacetrack.Fetch[spacetrack.SpaceTrackCdmUnit](context.Background(), newClient(s), spacetrack.Cdm.Query(""))
+		assert.Nil(t, err)
+		assert.Len(t, cdms, 2)
+
+		tles, err := spacetrack.Fetch[spacetrack.SpaceTrackTleUnit](context.Background(), newClient(s), spacetrack.Tle.Query(""))
+		assert.Nil(t, err)
+		assert.Len(t, tles, 2, "classes without file keep the default fixture")
+	})
+
+	t.Run("fixture dir which can't be read panics", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.Mkdir(filepath.Join(dir, "gp.json"), 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Panics(t, func() { NewServer(WithFixtureDir(dir)).Close() })
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		s := NewServer(WithCredentials("other", "secret"))
+		defer s.Close()
+
+		assert.ErrorIs(t, newClient(s).Login(context.Background()), spacetrack.ErrNotAuthenticated)
+		assert.Equal(t, 0, s.Logins())
+	})
+}
+
+func TestServerFailures(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := newClient(s)
+	s.FailNext(1, http.StatusTooManyRequests)
+	s.FailNext(2, http.StatusServiceUnavailable)
+
+	for _, want := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusServiceUnavailable} {
+		err := c.Login(context.Background())
+
+		var se *spacetrack.StatusError
+		if assert.ErrorAs(t, err, &se) {
+			assert.Equal(t, want, se.StatusCode)
+		}
+	}
+
+	assert.Nil(t, c.Login(context.Background()), "failures are consumed")
+	assert.Equal(t, 1, s.Logins())
+}
+
+func TestServerLatency(t *testing.T) {
+	s := NewServer(WithLatency(50 * time.Millisecond))
+	defer s.Close()
+
+	c := newClient(s)
+
+	start := time.Now()
+	assert.Nil(t, c.Login(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	s.SetLatency(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Query(ctx, spacetrack.Tle.Query(""))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
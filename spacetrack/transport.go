@@ -0,0 +1,130 @@
+package spacetrack
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Transport configures the http client used to request space-track, see NewHTTPClient. Zero values keep the
+// defaults of http.DefaultTransport.
+type Transport struct {
+	// Timeout is the maximum amount of time of each request, including reading the body. If zero, there is no timeout
+	// other than the deadline of the context.
+	Timeout time.Duration
+	// DialTimeout is the maximum amount of time to open the connection.
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout is the maximum amount of time of the TLS handshake.
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout is the maximum amount of time to wait for the headers of the response.
+	ResponseHeaderTimeout time.Duration
+	// IdleConnTimeout is the maximum amount of time an idle connection is kept in the pool.
+	IdleConnTimeout time.Duration
+	// Proxy is the url of the http or https proxy, e.g. http://proxy.example.com:3128. If empty, the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY env vars are used.
+	Proxy string
+	// CAFile is a bundle of PEM certificates trusted besides the ones of the system, e.g. the CA of a corporate proxy.
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and its key, used when the server asks for them.
+	CertFile string
+	KeyFile  string
+	// TLSMinVersion is the minimum TLS version accepted, e.g. tls.VersionTLS12. If zero, TLS 1.2 is used.
+	TLSMinVersion uint16
+	// MaxIdleConns is the maximum amount of idle connections of the pool.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum amount of idle connections of the pool to each host.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost is the maximum amount of connections to each host, including the ones in use.
+	MaxConnsPerHost int
+}
+
+// NewHTTPClient returns an http client configured with the transport passed. It returns an error if the proxy can't
+// be parsed or some of the certificate files can't be loaded.
+func NewHTTPClient(t Transport) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if t.DialTimeout > 0 {
+		transport.DialContext = (&net.Dialer{Timeout: t.DialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	}
+
+	if t.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = t.TLSHandshakeTimeout
+	}
+
+	if t.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = t.ResponseHeaderTimeout
+	}
+
+	if t.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = t.IdleConnTimeout
+	}
+
+	if t.MaxIdleConns > 0 {
+		transport.MaxIdleConns = t.MaxIdleConns
+	}
+
+	if t.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+	}
+
+	if t.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = t.MaxConnsPerHost
+	}
+
+	if t.Proxy != "" {
+		proxy, err := url.Parse(t.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%w: proxy: %v", ErrTransport, err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig, err := t.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport, Timeout: t.Timeout}, nil
+}
+
+// ... tlsConfig returns the TLS configuration, trusting the CA bundle and presenting the client certificate, if any.
+func (t Transport) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: t.TLSMinVersion}
+	if cfg.MinVersion == 0 {
+		cfg.MinVersion = tls.VersionTLS12
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ca file: %v", ErrTransport, err)
+		}
+
+		// ... the system pool can't be loaded on some platforms, so the bundle is the only trusted one there.
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: ca file: no PEM certificates found in %s", ErrTransport, t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: client certificate: %v", ErrTransport, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,154 @@
+package spacetrack
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ... writePEM writes the blocks, PEM encoded, into a file of a temporary dir, returning its name.
+func writePEM(t *testing.T, name string, blocks ...*pem.Block) string {
+	fileName := filepath.Join(t.TempDir(), name)
+
+	var content []byte
+	for _, b := range blocks {
+		content = append(content, pem.EncodeToMemory(b)...)
+	}
+
+	if err := os.WriteFile(fileName, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return fileName
+}
+
+// ... serverKeyPair writes the certificate and key of the TLS test server, returning the names of the files.
+func serverKeyPair(t *testing.T, srv *httptest.Server) (string, string) {
+	cert := srv.TLS.Certificates[0]
+
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return writePEM(t, "cert.pem", &pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}),
+		writePEM(t, "key.pem", &pem.Block{Type: "PRIVATE KEY", Bytes: key})
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	t.Run("ca file is trusted", func(t *testing.T) {
+		srv := httptest.NewTLSServer(ok)
+		defer srv.Close()
+
+		caFile, _ := serverKeyPair(t, srv)
+
+		untrusted, err := NewHTTPClient(Transport{})
+		if assert.Nil(t, err) {
+			_, err = untrusted.Get(srv.URL)
+			assert.NotNil(t, err, "certificate of the test server isn't trusted by the system")
+		}
+
+		trusted, err := NewHTTPClient(Transport{CAFile: caFile})
+		if assert.Nil(t, err) {
+			res, err := trusted.Get(srv.URL)
+			if assert.Nil(t, err) {
+				res.Body.Close()
+				assert.Equal(t, http.StatusOK, res.StatusCode)
+			}
+		}
+	})
+
+	t.Run("client certificate is presented", func(t *testing.T) {
+		srv := httptest.NewUnstartedServer(ok)
+		srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+		srv.StartTLS()
+		defer srv.Close()
+
+		certFile, keyFile := serverKeyPair(t, srv)
+
+		c, err := NewHTTPClient(Transport{CAFile: certFile, CertFile: certFile, KeyFile: keyFile})
+		if assert.Nil(t, err) {
+			res, err := c.Get(srv.URL)
+			if assert.Nil(t, err) {
+				res.Body.Close()
+				assert.Equal(t, http.StatusOK, res.StatusCode)
+			}
+		}
+	})
+
+	t.Run("tls min version is enforced", func(t *testing.T) {
+		srv := httptest.NewUnstartedServer(ok)
+		srv.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+		srv.StartTLS()
+		defer srv.Close()
+
+		caFile, _ := serverKeyPair(t, srv)
+
+		c, err := NewHTTPClient(Transport{CAFile: caFile, TLSMinVersion: tls.VersionTLS13})
+		if assert.Nil(t, err) {
+			_, err = c.Get(srv.URL)
+			assert.NotNil(t, err)
+		}
+	})
+
+	t.Run("requests go through the proxy", func(t *testing.T) {
+		var proxied int32
+
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&proxied, 1)
+			assert.Equal(t, "http://www.space-track.example/ajaxauth/login", r.URL.String())
+		}))
+		defer proxy.Close()
+
+		c, err := NewHTTPClient(Transport{Proxy: proxy.URL})
+		if assert.Nil(t, err) {
+			res, err := c.Get("http://www.space-track.example/ajaxauth/login")
+			if assert.Nil(t, err) {
+				res.Body.Close()
+			}
+		}
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&proxied))
+	})
+
+	t.Run("timeout of each request", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer srv.Close()
+
+		c, err := NewHTTPClient(Transport{Timeout: 10 * time.Millisecond})
+		if assert.Nil(t, err) {
+			_, err = c.Get(srv.URL)
+			assert.NotNil(t, err)
+		}
+	})
+
+	for _, each := range []struct {
+		description string
+		transport   Transport
+	}{
+		{description: "ca file which doesn't exist", transport: Transport{CAFile: filepath.Join(t.TempDir(), "notexistent.pem")}},
+		{description: "ca file without certificates", transport: Transport{CAFile: writePEM(t, "empty.pem")}},
+		{description: "client certificate without key", transport: Transport{CertFile: writePEM(t, "cert.pem")}},
+		{description: "proxy which isn't an url", transport: Transport{Proxy: "http://proxy:port"}},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			c, err := NewHTTPClient(each.transport)
+
+			assert.Nil(t, c)
+			assert.ErrorIs(t, err, ErrTransport)
+		})
+	}
+}